@@ -6,14 +6,30 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/yourusername/scheduled-reports-app/pkg/api"
 	"github.com/yourusername/scheduled-reports-app/pkg/cron"
+	"github.com/yourusername/scheduled-reports-app/pkg/secrets"
 	"github.com/yourusername/scheduled-reports-app/pkg/store"
 )
 
+// defaultMaxConcurrentRenders caps concurrent renders across all orgs unless
+// GF_PLUGIN_MAX_CONCURRENT_RENDERS is set
+const defaultMaxConcurrentRenders = 10
+
+// rotateSecretsKeyCommand re-encrypts settings secrets with a new key when passed as the first argument
+const rotateSecretsKeyCommand = "rotate-secrets-key"
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == rotateSecretsKeyCommand {
+		if err := rotateSecretsKey(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if err := run(); err != nil {
 		log.Fatal(err)
 	}
@@ -27,19 +43,52 @@ func min(a, b int) int {
 	return b
 }
 
-func run() error {
-	// Get plugin data path
-	dataPath := os.Getenv("GF_PLUGIN_APP_DATA_PATH")
-	if dataPath == "" {
-		// Fallback: use executable directory + data
-		execPath, err := os.Executable()
-		if err == nil {
-			dataPath = filepath.Join(filepath.Dir(execPath), "data")
-		} else {
-			dataPath = "./data"
-		}
+// pluginDataPath returns the directory the database and artifacts are stored in
+func pluginDataPath() string {
+	if dataPath := os.Getenv("GF_PLUGIN_APP_DATA_PATH"); dataPath != "" {
+		return dataPath
+	}
+	// Fallback: use executable directory + data
+	execPath, err := os.Executable()
+	if err != nil {
+		return "./data"
+	}
+	return filepath.Join(filepath.Dir(execPath), "data")
+}
+
+// rotateSecretsKey re-encrypts the settings and delivery target secrets with GF_PLUGIN_SECRETS_KEY, reading
+// those encrypted with GF_PLUGIN_SECRETS_PREVIOUS_KEY. Secrets stored as plaintext are encrypted too.
+func rotateSecretsKey() error {
+	keyring, err := secrets.FromEnv()
+	if err != nil {
+		return err
+	}
+	if keyring == nil {
+		return fmt.Errorf("set %s to the new key and %s to the current one", secrets.KeyEnv, secrets.PreviousKeyEnv)
+	}
+
+	dbPath := filepath.Join(pluginDataPath(), "reporting.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("database not found: %w", err)
+	}
+	st, err := store.NewStore(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
 	}
+	defer st.Close()
+
+	st.SetKeyring(keyring)
+	updated, err := st.EncryptSecrets()
+	if err != nil {
+		return fmt.Errorf("failed to re-encrypt secrets: %w", err)
+	}
+	log.Printf("Re-encrypted the settings secrets of %d org(s) and the delivery target secrets of %d schedule(s) in %s",
+		updated.Settings, updated.Schedules, dbPath)
+	return nil
+}
 
+func run() error {
+	dataPath := pluginDataPath()
 	log.Printf("Using data path: %s", dataPath)
 
 	// Ensure data directory exists
@@ -58,6 +107,30 @@ func run() error {
 	}
 	defer st.Close()
 
+	// Encrypt secrets at rest, including those stored before a key was configured. Once any secret
+	// is encrypted the key is required, as scheduled runs and retention need to read them.
+	keyring, err := secrets.FromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid secrets key configuration: %w", err)
+	}
+	if keyring != nil {
+		st.SetKeyring(keyring)
+		if updated, err := st.EncryptSecrets(); err != nil {
+			log.Printf("ERROR: Failed to encrypt secrets: %v", err)
+		} else {
+			log.Printf("Secrets encrypted with %s (%d org(s) and %d schedule(s) updated)", secrets.KeyEnv, updated.Settings, updated.Schedules)
+		}
+	} else {
+		encrypted, err := st.HasEncryptedSecrets()
+		if err != nil {
+			return fmt.Errorf("failed to check for encrypted secrets: %w", err)
+		}
+		if encrypted {
+			return fmt.Errorf("secrets in %s are encrypted; set %s to the key they were encrypted with", dbPath, secrets.KeyEnv)
+		}
+		log.Printf("%s is not set; secrets are stored as plaintext until it is", secrets.KeyEnv)
+	}
+
 	// Build Grafana URL from instance configuration
 	// Grafana sets these environment variables for plugins
 	protocol := os.Getenv("GF_INSTANCE_PROTOCOL")
@@ -122,10 +195,25 @@ func run() error {
 	}
 
 	// Initialize scheduler (token will be retrieved from context on first API call)
-	maxConcurrent := 5 // Default max concurrent renders
-	log.Printf("Initializing scheduler (max concurrent: %d)", maxConcurrent)
+	// Per-org limits come from settings; this caps concurrent renders across all orgs
+	maxConcurrent := defaultMaxConcurrentRenders
+	if value := os.Getenv("GF_PLUGIN_MAX_CONCURRENT_RENDERS"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			maxConcurrent = n
+		} else {
+			log.Printf("WARNING: Ignoring invalid GF_PLUGIN_MAX_CONCURRENT_RENDERS=%q", value)
+		}
+	}
+	log.Printf("Initializing scheduler (max concurrent across all orgs: %d)", maxConcurrent)
 	scheduler := cron.NewScheduler(st, grafanaURL, artifactsPath, maxConcurrent)
 
+	// Share delivery targets may only write below these directories (network shares mounted on this server)
+	if value := os.Getenv("GF_PLUGIN_SHARE_PATHS"); value != "" {
+		sharePaths := filepath.SplitList(value)
+		log.Printf("Share delivery targets allowed in: %v", sharePaths)
+		scheduler.SetSharePaths(sharePaths)
+	}
+
 	// Start scheduler
 	log.Println("Starting scheduler...")
 	if err := scheduler.Start(); err != nil {
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/store"
+)
+
+// Audit log page sizes
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// recordAudit records an action of the request's user with the diff between the resource before
+// and after it, either of which may be nil. The action already happened, so failures are only logged.
+func (h *Handler) recordAudit(r *http.Request, action, resourceType string, resourceID int64, before, after interface{}, redacted ...string) {
+	id := requestIdentity(r)
+	event := &model.AuditEvent{
+		OrgID:        id.OrgID,
+		UserLogin:    id.Login,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+	}
+	if before != nil || after != nil {
+		diff, err := model.AuditDiff(before, after, redacted...)
+		if err != nil {
+			log.Printf("[AUDIT] ERROR: Failed to diff %s of %s %d: %v", action, resourceType, resourceID, err)
+		}
+		event.Diff = diff
+	}
+	if err := h.store.CreateAuditEvent(event); err != nil {
+		log.Printf("[AUDIT] ERROR: Failed to record %s of %s %d by %s: %v", action, resourceType, resourceID, id.Login, err)
+	}
+}
+
+// recordSettingsAudit records a settings change, with the values of secrets redacted
+func (h *Handler) recordSettingsAudit(r *http.Request, before, after *model.Settings) {
+	var redacted []string
+	for path := range after.Secrets() {
+		redacted = append(redacted, path)
+	}
+	if before != nil {
+		for path := range before.Secrets() {
+			redacted = append(redacted, path)
+		}
+	}
+	var previous interface{}
+	if before != nil {
+		previous = before
+	}
+	h.recordAudit(r, model.AuditSettingsUpdate, model.AuditResourceSettings, 0, previous, after, redacted...)
+}
+
+// recordScheduleAudit records an action on a schedule, with the values of delivery target secrets
+// redacted. before is nil for created schedules, after for deleted ones.
+func (h *Handler) recordScheduleAudit(r *http.Request, action string, scheduleID int64, before, after *model.Schedule) {
+	var redacted []string
+	var previous, current interface{}
+	if before != nil {
+		for path := range before.Secrets() {
+			redacted = append(redacted, path)
+		}
+		previous = before
+	}
+	if after != nil {
+		for path := range after.Secrets() {
+			redacted = append(redacted, path)
+		}
+		current = after
+	}
+	h.recordAudit(r, action, model.AuditResourceSchedule, scheduleID, previous, current, redacted...)
+}
+
+// handleAudit handles GET /api/audit, listing the org's audit events newest first. The optional
+// query parameters user, action, resource_type, resource_id, from and to (RFC 3339) filter them,
+// limit and offset page through them.
+func (h *Handler) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := store.AuditFilter{
+		OrgID:        requestIdentity(r).OrgID,
+		UserLogin:    query.Get("user"),
+		Action:       query.Get("action"),
+		ResourceType: query.Get("resource_type"),
+		Limit:        defaultAuditLimit,
+	}
+
+	var err error
+	if filter.ResourceID, err = int64Param(query.Get("resource_id")); err != nil {
+		http.Error(w, "Invalid resource_id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.From, err = timeParam(query.Get("from")); err != nil {
+		http.Error(w, "Invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = timeParam(query.Get("to")); err != nil {
+		http.Error(w, "Invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			http.Error(w, fmt.Sprintf("Invalid limit: must be between 1 and %d", maxAuditLimit), http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid offset: must be 0 or more", http.StatusBadRequest)
+			return
+		}
+		filter.Offset = offset
+	}
+
+	events, total, err := h.store.ListAuditEvents(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, map[string]interface{}{
+		"events": events,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// int64Param parses an optional integer query parameter
+func int64Param(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// timeParam parses an optional RFC 3339 query parameter
+func timeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
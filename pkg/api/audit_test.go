@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/yourusername/scheduled-reports-app/pkg/cron"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/store"
+)
+
+func TestAuditLog(t *testing.T) {
+	// Grafana answering the dashboard access check
+	grafana := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/org/users":
+			w.Write([]byte(`[{"userId": 1, "login": "admin", "role": "Admin"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer grafana.Close()
+	t.Setenv("GF_PLUGIN_APP_CLIENT_SECRET", "token")
+
+	st, err := store.NewStore(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+	if err := st.UpsertSettings(&model.Settings{
+		OrgID:          1,
+		SMTPConfig:     &model.SMTPConfig{Host: "smtp.example.com", Port: 587, Password: "old-password"},
+		RendererConfig: model.RendererConfig{GrafanaURL: grafana.URL},
+	}); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+	h := NewHandler(st, cron.NewScheduler(st, grafana.URL, t.TempDir(), 1))
+
+	admin := &backend.User{Login: "admin", Role: roleAdmin}
+	do := func(user *backend.User, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(backend.WithPluginContext(req.Context(), backend.PluginContext{OrgID: 1, User: user}))
+		rec := httptest.NewRecorder()
+		authenticate(h.mux).ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(admin, http.MethodPost, "/api/schedules",
+		`{"name": "Weekly", "dashboard_uid": "abc", "interval_type": "weekly", "timezone": "UTC", "recipients": {"to": ["a@example.com"]},
+		"targets": [{"type": "slack", "slack": {"token": "xoxb-created", "channel": "C1"}}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/schedules = %d: %s", rec.Code, rec.Body)
+	}
+	var schedule model.Schedule
+	if err := json.Unmarshal(rec.Body.Bytes(), &schedule); err != nil {
+		t.Fatal(err)
+	}
+	schedulePath := "/api/schedules/" + strconv.FormatInt(schedule.ID, 10)
+
+	schedule.Recipients.To = []string{"b@example.com"}
+	schedule.Targets[0].Slack.Token = "xoxb-updated"
+	body, _ := json.Marshal(schedule)
+	if rec := do(admin, http.MethodPut, schedulePath, string(body)); rec.Code != http.StatusOK {
+		t.Fatalf("PUT %s = %d: %s", schedulePath, rec.Code, rec.Body)
+	}
+	settings := `{"smtp_config": {"host": "smtp.example.com", "port": 587, "password": "new-password"},
+		"renderer_config": {"grafana_url": "` + grafana.URL + `"}}`
+	if rec := do(admin, http.MethodPost, "/api/settings", settings); rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/settings = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do(admin, http.MethodDelete, schedulePath, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE %s = %d: %s", schedulePath, rec.Code, rec.Body)
+	}
+
+	list := func(query string) (events []model.AuditEvent, total int) {
+		rec := do(admin, http.MethodGet, "/api/audit"+query, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /api/audit%s = %d: %s", query, rec.Code, rec.Body)
+		}
+		var resp struct {
+			Events []model.AuditEvent `json:"events"`
+			Total  int                `json:"total"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Events, resp.Total
+	}
+
+	events, total := list("")
+	var actions []string
+	for _, event := range events {
+		actions = append(actions, event.Action)
+		if event.UserLogin != "admin" || event.OrgID != 1 {
+			t.Errorf("event %s by %s of org %d, want admin of org 1", event.Action, event.UserLogin, event.OrgID)
+		}
+	}
+	want := []string{model.AuditScheduleDelete, model.AuditSettingsUpdate, model.AuditScheduleUpdate, model.AuditScheduleCreate}
+	if total != 4 || strings.Join(actions, ",") != strings.Join(want, ",") {
+		t.Fatalf("GET /api/audit = %v (total %d), want %v", actions, total, want)
+	}
+
+	update := events[2]
+	if update.ResourceType != model.AuditResourceSchedule || update.ResourceID != schedule.ID ||
+		!strings.Contains(string(update.Diff), `"recipients.to":{"old":["a@example.com"],"new":["b@example.com"]}`) {
+		t.Errorf("schedule update event = %+v, diff %s", update, update.Diff)
+	}
+	if !strings.Contains(string(update.Diff), `"targets.0.slack.token":{"old":"[redacted]","new":"[redacted]"}`) {
+		t.Errorf("schedule update diff = %s, want the token change redacted", update.Diff)
+	}
+	// Target secrets never reach the stored events, whether the schedule is created, updated or deleted
+	stored, _, err := st.ListAuditEvents(store.AuditFilter{OrgID: 1})
+	if err != nil {
+		t.Fatalf("ListAuditEvents() error = %v", err)
+	}
+	for _, event := range stored {
+		if strings.Contains(string(event.Diff), "xoxb-") {
+			t.Errorf("%s event diff = %s, want the target token redacted", event.Action, event.Diff)
+		}
+	}
+	settingsDiff := string(events[1].Diff)
+	if !strings.Contains(settingsDiff, `"smtp_config.password":{"old":"[redacted]","new":"[redacted]"}`) ||
+		strings.Contains(settingsDiff, "old-password") || strings.Contains(settingsDiff, "new-password") {
+		t.Errorf("settings update diff = %s, want the password redacted", settingsDiff)
+	}
+
+	if events, total := list("?action=schedule.update&limit=1"); total != 1 || len(events) != 1 || events[0].Action != model.AuditScheduleUpdate {
+		t.Errorf("GET /api/audit?action=schedule.update = %+v (total %d)", events, total)
+	}
+	if events, total := list("?resource_type=schedule&limit=2&offset=2"); total != 3 || len(events) != 1 || events[0].Action != model.AuditScheduleCreate {
+		t.Errorf("GET /api/audit page 2 = %+v (total %d)", events, total)
+	}
+	if rec := do(admin, http.MethodGet, "/api/audit?from=yesterday", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("GET /api/audit?from=yesterday = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := do(&backend.User{Login: "editor", Role: roleEditor}, http.MethodGet, "/api/audit", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("GET /api/audit by an editor = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
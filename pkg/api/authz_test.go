@@ -0,0 +1,360 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/yourusername/scheduled-reports-app/pkg/cron"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/store"
+)
+
+func TestIdentityPermissions(t *testing.T) {
+	schedule := &model.Schedule{
+		OwnerLogin: "owner",
+		Recipients: model.Recipients{To: []string{"team@example.com"}, BCC: []string{" Viewer@Example.com "}},
+		Burst: &model.Burst{Variable: "region", Values: []model.BurstValue{
+			{Value: "emea", Recipients: model.Recipients{To: []string{"emea@example.com"}}},
+		}},
+	}
+	legacy := &model.Schedule{OwnerUserID: 1}
+
+	admin := &identity{Login: "admin", Role: roleAdmin}
+	owner := &identity{Login: "owner", Role: roleEditor}
+	editor := &identity{Login: "other", Role: roleEditor}
+	ownerViewer := &identity{Login: "owner", Role: roleViewer} // Demoted since creating the schedule
+	none := &identity{Login: "none", Role: "None"}
+
+	if !admin.hasRole(roleEditor) || !editor.hasRole(roleViewer) || editor.hasRole(roleAdmin) || none.hasRole(roleViewer) {
+		t.Error("hasRole() does not follow Viewer < Editor < Admin")
+	}
+
+	tests := []struct {
+		user     *identity
+		schedule *model.Schedule
+		want     bool
+	}{
+		{admin, schedule, true},
+		{owner, schedule, true},
+		{editor, schedule, false},
+		{ownerViewer, schedule, false},
+		{admin, legacy, true},
+		{editor, legacy, false},
+	}
+	for _, tt := range tests {
+		if got := tt.user.canManage(tt.schedule); got != tt.want {
+			t.Errorf("%s (%s).canManage(owner %q) = %v, want %v", tt.user.Login, tt.user.Role, tt.schedule.OwnerLogin, got, tt.want)
+		}
+	}
+
+	viewer := &identity{Login: "viewer", Email: "viewer@example.com", Role: roleViewer}
+	emea := &identity{Login: "emea", Email: "emea@example.com", Role: roleViewer}
+	run := &model.Run{}
+	child := &model.Run{BurstValue: "emea"}
+	if !viewer.receives(schedule, run) || emea.receives(schedule, run) {
+		t.Error("receives() should match the schedule's recipients for its own runs")
+	}
+	if viewer.receives(schedule, child) || !emea.receives(schedule, child) {
+		t.Error("receives() should match the burst value's recipients for burst reports")
+	}
+	if (&identity{Role: roleViewer}).receives(schedule, run) {
+		t.Error("receives() matched a user without an email address")
+	}
+}
+
+func TestScheduleAuthorization(t *testing.T) {
+	st, err := store.NewStore(filepath.Join(t.TempDir(), "authz.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+	h := NewHandler(st, cron.NewScheduler(st, "http://localhost:3000", t.TempDir(), 1))
+
+	newSchedule := func() *model.Schedule {
+		schedule := &model.Schedule{
+			OrgID: 1, Name: "Weekly", DashboardUID: "abc", IntervalType: "weekly", Timezone: "UTC",
+			Recipients: model.Recipients{To: []string{"viewer@example.com"}}, OwnerLogin: "owner",
+		}
+		if err := st.CreateSchedule(schedule); err != nil {
+			t.Fatalf("CreateSchedule() error = %v", err)
+		}
+		return schedule
+	}
+	schedule := newSchedule()
+	run := &model.Run{ScheduleID: schedule.ID, OrgID: 1, StartedAt: time.Now(), Status: "completed"}
+	if err := st.CreateRun(run); err != nil {
+		t.Fatalf("CreateRun() error = %v", err)
+	}
+	run.EmailError = "rejected: boss@example.com"
+	if err := st.UpdateRun(run); err != nil {
+		t.Fatalf("UpdateRun() error = %v", err)
+	}
+	if err := st.SaveDelivery(1, run.ID, &model.DeliveryResult{Index: 0, Type: model.TargetSlack, Target: "C1", Success: true, DeliveredAt: time.Now()}); err != nil {
+		t.Fatalf("SaveDelivery() error = %v", err)
+	}
+	newTemplate := func(owner string) string {
+		template := &model.Template{OrgID: 1, Name: "Branding " + owner, Kind: "pdf", OwnerLogin: owner}
+		if err := st.CreateTemplate(template); err != nil {
+			t.Fatalf("CreateTemplate() error = %v", err)
+		}
+		return "/api/templates/" + strconv.FormatInt(template.ID, 10)
+	}
+	templatePath := newTemplate("owner")
+	legacyTemplatePath := newTemplate("")
+
+	do := func(user *backend.User, orgID int64, method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req = req.WithContext(backend.WithPluginContext(req.Context(), backend.PluginContext{OrgID: orgID, User: user}))
+		rec := httptest.NewRecorder()
+		authenticate(h.mux).ServeHTTP(rec, req)
+		return rec.Code
+	}
+	owner := &backend.User{Login: "owner", Role: roleEditor}
+	editor := &backend.User{Login: "other", Role: roleEditor}
+	viewer := &backend.User{Login: "viewer", Email: "viewer@example.com", Role: roleViewer}
+	outsider := &backend.User{Login: "outsider", Email: "outsider@example.com", Role: roleViewer}
+	admin := &backend.User{Login: "admin", Role: roleAdmin}
+	schedulePath := "/api/schedules/" + strconv.FormatInt(schedule.ID, 10)
+	artifactPath := "/api/runs/" + strconv.FormatInt(run.ID, 10) + "/artifact"
+	signedArtifactPath := h.scheduler.SignedArtifactURL("", 1, run.ID)
+
+	// Runs are listed in full to those managing the schedule, and to recipients without the deliveries
+	listRuns := func(user *backend.User) []model.Run {
+		req := httptest.NewRequest(http.MethodGet, schedulePath+"/runs", nil)
+		req = req.WithContext(backend.WithPluginContext(req.Context(), backend.PluginContext{OrgID: 1, User: user}))
+		rec := httptest.NewRecorder()
+		authenticate(h.mux).ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s/runs = %d: %s", schedulePath, rec.Code, rec.Body)
+		}
+		var resp struct {
+			Runs []model.Run `json:"runs"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Runs
+	}
+	if runs := listRuns(owner); len(runs) != 1 || len(runs[0].Deliveries) != 1 || runs[0].EmailError == "" {
+		t.Errorf("owner's runs = %+v, want the run with its deliveries", runs)
+	}
+	if runs := listRuns(viewer); len(runs) != 1 || runs[0].Deliveries != nil || runs[0].EmailError != "" {
+		t.Errorf("recipient's runs = %+v, want the run without its deliveries", runs)
+	}
+	if runs := listRuns(outsider); len(runs) != 0 {
+		t.Errorf("non-recipient's runs = %+v, want none", runs)
+	}
+
+	tests := []struct {
+		name   string
+		user   *backend.User
+		orgID  int64
+		method string
+		path   string
+		want   int
+	}{
+		{"viewer lists schedules", viewer, 1, http.MethodGet, "/api/schedules", http.StatusOK},
+		{"viewer can't create schedules", viewer, 1, http.MethodPost, "/api/schedules", http.StatusForbidden},
+		{"viewer can't run schedules", viewer, 1, http.MethodPost, schedulePath + "/run", http.StatusForbidden},
+		{"other editor can't delete", editor, 1, http.MethodDelete, schedulePath, http.StatusForbidden},
+		{"other editor can't update", editor, 1, http.MethodPut, schedulePath, http.StatusForbidden},
+		{"other editor can't see deliveries", editor, 1, http.MethodGet, "/api/runs/" + strconv.FormatInt(run.ID, 10) + "/deliveries", http.StatusForbidden},
+		// The run has no artifact, so those allowed to download it get 404 Not Found
+		{"recipient downloads", viewer, 1, http.MethodGet, artifactPath, http.StatusNotFound},
+		{"owner downloads", owner, 1, http.MethodGet, artifactPath, http.StatusNotFound},
+		{"non-recipient can't download", outsider, 1, http.MethodGet, artifactPath, http.StatusForbidden},
+		{"non-recipient downloads through a signed link", outsider, 1, http.MethodGet, signedArtifactPath, http.StatusNotFound},
+		{"signed link doesn't open in another org", outsider, 2, http.MethodGet, signedArtifactPath, http.StatusNotFound},
+		{"other org doesn't see the schedule", admin, 2, http.MethodDelete, schedulePath, http.StatusNotFound},
+		{"editor can't read settings", owner, 1, http.MethodGet, "/api/settings", http.StatusForbidden},
+		{"admin reads settings", admin, 1, http.MethodGet, "/api/settings", http.StatusOK},
+		{"editor can't test SMTP", owner, 1, http.MethodPost, "/api/smtp/test", http.StatusForbidden},
+		{"other editor can't update a template", editor, 1, http.MethodPut, templatePath, http.StatusForbidden},
+		{"other editor can't delete a template", editor, 1, http.MethodDelete, templatePath, http.StatusForbidden},
+		{"editor deletes a template without owner", editor, 1, http.MethodDelete, legacyTemplatePath, http.StatusNoContent},
+		{"template owner deletes", owner, 1, http.MethodDelete, templatePath, http.StatusNoContent},
+		{"owner deletes", owner, 1, http.MethodDelete, schedulePath, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := do(tt.user, tt.orgID, tt.method, tt.path); got != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+
+	// Admins manage everyone's schedules
+	other := newSchedule()
+	if got := do(admin, 1, http.MethodDelete, "/api/schedules/"+strconv.FormatInt(other.ID, 10)); got != http.StatusNoContent {
+		t.Errorf("admin DELETE = %d, want %d", got, http.StatusNoContent)
+	}
+}
+
+func TestCreateScheduleOwner(t *testing.T) {
+	// Grafana answering the dashboard access check
+	grafana := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/org/users":
+			w.Write([]byte(`[{"userId": 1, "login": "owner", "role": "Admin"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer grafana.Close()
+	t.Setenv("GF_PLUGIN_APP_CLIENT_SECRET", "token")
+
+	st, err := store.NewStore(filepath.Join(t.TempDir(), "owner.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+	if err := st.UpsertSettings(&model.Settings{OrgID: 1, RendererConfig: model.RendererConfig{GrafanaURL: grafana.URL}}); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+	h := NewHandler(st, cron.NewScheduler(st, grafana.URL, t.TempDir(), 1))
+
+	// The owner is taken from the plugin context, whatever the body claims
+	body := `{"name": "Weekly", "dashboard_uid": "abc", "interval_type": "weekly", "timezone": "UTC",
+		"recipients": {"to": ["a@example.com"]}, "owner_user_id": 7, "owner_login": "victim"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/schedules", strings.NewReader(body))
+	req = req.WithContext(backend.WithPluginContext(req.Context(), backend.PluginContext{OrgID: 1, User: &backend.User{Login: "owner", Role: roleAdmin}}))
+	rec := httptest.NewRecorder()
+	authenticate(h.mux).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/schedules = %d: %s", rec.Code, rec.Body)
+	}
+	var created model.Schedule
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := st.GetSchedule(1, created.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if stored.OwnerLogin != "owner" || stored.OwnerUserID != 0 {
+		t.Errorf("owner = %q (%d), want the caller's login and no user ID", stored.OwnerLogin, stored.OwnerUserID)
+	}
+}
+
+func TestClaimLegacyOwner(t *testing.T) {
+	// Grafana listing the org's users
+	grafana := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"userId": 7, "login": "owner", "role": "Editor"}, {"userId": 8, "login": "other", "role": "Editor"}]`))
+	}))
+	defer grafana.Close()
+	t.Setenv("GF_PLUGIN_APP_CLIENT_SECRET", "token")
+
+	st, err := store.NewStore(filepath.Join(t.TempDir(), "legacy.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+	if err := st.UpsertSettings(&model.Settings{OrgID: 1, RendererConfig: model.RendererConfig{GrafanaURL: grafana.URL}}); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+	h := NewHandler(st, cron.NewScheduler(st, grafana.URL, t.TempDir(), 1))
+
+	// Created before owners were identified by login
+	schedule := &model.Schedule{OrgID: 1, Name: "Weekly", DashboardUID: "abc", IntervalType: "weekly", Timezone: "UTC", OwnerUserID: 7}
+	if err := st.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	schedulePath := "/api/schedules/" + strconv.FormatInt(schedule.ID, 10)
+
+	do := func(user *backend.User, method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req = req.WithContext(backend.WithPluginContext(req.Context(), backend.PluginContext{OrgID: 1, User: user}))
+		rec := httptest.NewRecorder()
+		authenticate(h.mux).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if got := do(&backend.User{Login: "other", Role: roleEditor}, http.MethodPost, schedulePath+"/run"); got != http.StatusForbidden {
+		t.Errorf("other editor POST run = %d, want %d", got, http.StatusForbidden)
+	}
+	if got := do(&backend.User{Login: "owner", Role: roleEditor}, http.MethodGet, schedulePath+"/runs"); got != http.StatusOK {
+		t.Errorf("owner GET runs = %d, want %d", got, http.StatusOK)
+	}
+	stored, err := st.GetSchedule(1, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if stored.OwnerLogin != "owner" || stored.OwnerUserID != 7 {
+		t.Errorf("owner = %q (%d), want the owner's login recorded", stored.OwnerLogin, stored.OwnerUserID)
+	}
+	if got := do(&backend.User{Login: "owner", Role: roleEditor}, http.MethodDelete, schedulePath); got != http.StatusNoContent {
+		t.Errorf("owner DELETE = %d, want %d", got, http.StatusNoContent)
+	}
+}
+
+func TestScheduleSecretsMasked(t *testing.T) {
+	// Grafana answering the dashboard access check
+	grafana := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/org/users":
+			w.Write([]byte(`[{"userId": 1, "login": "owner", "role": "Admin"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer grafana.Close()
+	t.Setenv("GF_PLUGIN_APP_CLIENT_SECRET", "token")
+
+	st, err := store.NewStore(filepath.Join(t.TempDir(), "secrets.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+	if err := st.UpsertSettings(&model.Settings{OrgID: 1, RendererConfig: model.RendererConfig{GrafanaURL: grafana.URL}}); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+	h := NewHandler(st, cron.NewScheduler(st, grafana.URL, t.TempDir(), 1))
+
+	schedule := &model.Schedule{
+		OrgID: 1, Name: "Weekly", DashboardUID: "abc", IntervalType: "weekly", Timezone: "UTC", OwnerLogin: "owner",
+		Targets: model.DeliveryTargets{{Type: model.TargetSlack, Slack: &model.SlackTarget{Token: "xoxb-secret", Channel: "C1"}}},
+	}
+	if err := st.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	schedulePath := "/api/schedules/" + strconv.FormatInt(schedule.ID, 10)
+
+	do := func(user *backend.User, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(backend.WithPluginContext(req.Context(), backend.PluginContext{OrgID: 1, User: user}))
+		rec := httptest.NewRecorder()
+		authenticate(h.mux).ServeHTTP(rec, req)
+		return rec
+	}
+	viewer := &backend.User{Login: "viewer", Role: roleViewer}
+	owner := &backend.User{Login: "owner", Role: roleAdmin}
+
+	for _, path := range []string{"/api/schedules", schedulePath} {
+		rec := do(viewer, http.MethodGet, path, "")
+		if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "xoxb-secret") ||
+			!strings.Contains(rec.Body.String(), model.SecretUnchanged) {
+			t.Errorf("GET %s = %d: %s, want the token masked", path, rec.Code, rec.Body)
+		}
+	}
+
+	// Saving the masked schedule keeps the stored token
+	rec := do(owner, http.MethodGet, schedulePath, "")
+	rec = do(owner, http.MethodPut, schedulePath, rec.Body.String())
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "xoxb-secret") {
+		t.Fatalf("PUT %s = %d: %s", schedulePath, rec.Code, rec.Body)
+	}
+	stored, err := st.GetSchedule(1, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if stored.Targets[0].Slack.Token != "xoxb-secret" {
+		t.Errorf("stored token after PUT = %q, want it kept", stored.Targets[0].Slack.Token)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -12,7 +13,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -46,14 +46,18 @@ func NewHandler(st *store.Store, scheduler *cron.Scheduler) *Handler {
 
 // registerRoutes registers all HTTP routes
 func (h *Handler) registerRoutes() {
-	h.mux.HandleFunc("/api/schedules", h.handleSchedules)
-	h.mux.HandleFunc("/api/schedules/", h.handleSchedule)
-	h.mux.HandleFunc("/api/runs/", h.handleRun)
-	h.mux.HandleFunc("/api/settings", h.handleSettings)
-	h.mux.HandleFunc("/api/service-account/status", h.handleServiceAccountStatus)
-	h.mux.HandleFunc("/api/service-account/test-token", h.handleTestToken)
-	h.mux.HandleFunc("/api/chromium/check-version", h.handleChromiumCheckVersion)
-	h.mux.HandleFunc("/api/smtp/test", h.handleSMTPTest)
+	// Viewers can read schedules and templates; the handlers check the finer permissions
+	h.mux.HandleFunc("/api/schedules", requireRole(roleViewer, h.handleSchedules))
+	h.mux.HandleFunc("/api/schedules/", requireRole(roleViewer, h.handleSchedule))
+	h.mux.HandleFunc("/api/runs/", requireRole(roleViewer, h.handleRun))
+	h.mux.HandleFunc("/api/templates", requireRole(roleViewer, h.handleTemplates))
+	h.mux.HandleFunc("/api/templates/", requireRole(roleViewer, h.handleTemplate))
+	h.mux.HandleFunc("/api/settings", requireRole(roleAdmin, h.handleSettings))
+	h.mux.HandleFunc("/api/service-account/status", requireRole(roleAdmin, h.handleServiceAccountStatus))
+	h.mux.HandleFunc("/api/service-account/test-token", requireRole(roleAdmin, h.handleTestToken))
+	h.mux.HandleFunc("/api/chromium/check-version", requireRole(roleAdmin, h.handleChromiumCheckVersion))
+	h.mux.HandleFunc("/api/smtp/test", requireRole(roleAdmin, h.handleSMTPTest))
+	h.mux.HandleFunc("/api/audit", requireRole(roleAdmin, h.handleAudit))
 }
 
 // CallResource implements backend.CallResourceHandler
@@ -66,13 +70,14 @@ func (h *Handler) CallResource(ctx context.Context, req *backend.CallResourceReq
 		log.Println("Cached Grafana config context for scheduler")
 	}
 
-	adapter := httpadapter.New(h.mux)
+	// Every route needs the org and user from the plugin context
+	adapter := httpadapter.New(authenticate(h.mux))
 	return adapter.CallResource(ctx, req, sender)
 }
 
 // handleSchedules handles GET /api/schedules and POST /api/schedules
 func (h *Handler) handleSchedules(w http.ResponseWriter, r *http.Request) {
-	orgID := getOrgID(r)
+	orgID := requestIdentity(r).OrgID
 
 	switch r.Method {
 	case http.MethodGet:
@@ -81,9 +86,17 @@ func (h *Handler) handleSchedules(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		for _, schedule := range schedules {
+			schedule.MaskSecrets()
+		}
 		respondJSON(w, map[string]interface{}{"schedules": schedules})
 
 	case http.MethodPost:
+		if !requestIdentity(r).hasRole(roleEditor) {
+			forbidden(w, "creating schedules requires the Editor role")
+			return
+		}
+
 		var schedule model.Schedule
 		if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -91,27 +104,16 @@ func (h *Handler) handleSchedules(w http.ResponseWriter, r *http.Request) {
 		}
 
 		schedule.OrgID = orgID
-		schedule.OwnerUserID = getUserID(r)
+		schedule.OwnerUserID = 0 // Only schedules created before owners were identified by login have one
+		schedule.OwnerLogin = requestIdentity(r).Login
+		schedule.KeepSecrets(nil)
 
-		// Validate recipient email domains against whitelist
-		settings, err := h.store.GetSettings(orgID)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to get settings: %v", err), http.StatusInternalServerError)
+		if status, err := h.validateSchedule(orgID, &schedule); err != nil {
+			http.Error(w, err.Error(), status)
 			return
 		}
-		if settings != nil {
-			if err := model.ValidateRecipientDomains(schedule.Recipients, settings.Limits.AllowedDomains); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
-		}
-
-		// Validate CRON expression if provided
-		if schedule.CronExpr != "" {
-			if err := model.ValidateCronExpression(schedule.CronExpr); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
+		if !h.checkDashboardAccess(w, r, &schedule) {
+			return
 		}
 
 		// Calculate and set next run time only if schedule is enabled
@@ -126,7 +128,9 @@ func (h *Handler) handleSchedules(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		h.recordScheduleAudit(r, model.AuditScheduleCreate, schedule.ID, nil, &schedule)
 
+		schedule.MaskSecrets()
 		respondJSON(w, schedule)
 
 	default:
@@ -136,7 +140,7 @@ func (h *Handler) handleSchedules(w http.ResponseWriter, r *http.Request) {
 
 // handleSchedule handles operations on a specific schedule
 func (h *Handler) handleSchedule(w http.ResponseWriter, r *http.Request) {
-	orgID := getOrgID(r)
+	orgID := requestIdentity(r).OrgID
 	path := r.URL.Path
 
 	// Parse schedule ID and action from path
@@ -154,24 +158,45 @@ func (h *Handler) handleSchedule(w http.ResponseWriter, r *http.Request) {
 
 	// Handle actions
 	if action == "run" && r.Method == http.MethodPost {
-		schedule, err := h.store.GetSchedule(orgID, scheduleID)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+		schedule, ok := h.manageableSchedule(w, r, scheduleID)
+		if !ok {
 			return
 		}
 
 		h.scheduler.ExecuteSchedule(schedule)
+		h.recordAudit(r, model.AuditScheduleRun, model.AuditResourceSchedule, schedule.ID, nil, nil)
 		respondJSON(w, map[string]string{"status": "started"})
 		return
 	}
 
 	if action == "runs" && r.Method == http.MethodGet {
+		schedule, err := h.store.GetSchedule(orgID, scheduleID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		runs, err := h.store.ListRuns(orgID, scheduleID)
 		if err != nil {
 			fmt.Printf("Error loading runs for schedule %d, org %d: %v\n", scheduleID, orgID, err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+
+		// Those managing the schedule see every run; recipients only the runs of reports they
+		// received, without the deliveries to everyone else
+		h.claimLegacyOwner(r, schedule)
+		if id := requestIdentity(r); !id.canManage(schedule) {
+			received := make([]*model.Run, 0, len(runs))
+			for _, run := range runs {
+				if id.receives(schedule, run) {
+					run.Deliveries = nil
+					run.EmailError = ""
+					run.ArtifactPath = ""
+					received = append(received, run)
+				}
+			}
+			runs = received
+		}
 		respondJSON(w, map[string]interface{}{"runs": runs})
 		return
 	}
@@ -184,9 +209,15 @@ func (h *Handler) handleSchedule(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
+		schedule.MaskSecrets()
 		respondJSON(w, schedule)
 
 	case http.MethodPut:
+		existing, ok := h.manageableSchedule(w, r, scheduleID)
+		if !ok {
+			return
+		}
+
 		var schedule model.Schedule
 		if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -195,26 +226,20 @@ func (h *Handler) handleSchedule(w http.ResponseWriter, r *http.Request) {
 
 		schedule.ID = scheduleID
 		schedule.OrgID = orgID
+		schedule.OwnerUserID = existing.OwnerUserID
+		schedule.OwnerLogin = existing.OwnerLogin
+		schedule.CreatedAt = existing.CreatedAt
+		schedule.LastRunAt = existing.LastRunAt
 
-		// Validate recipient email domains against whitelist
-		settings, err := h.store.GetSettings(orgID)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to get settings: %v", err), http.StatusInternalServerError)
+		// Target secrets are write-only: the masked values the UI sends back keep the stored ones
+		schedule.KeepSecrets(existing)
+
+		if status, err := h.validateSchedule(orgID, &schedule); err != nil {
+			http.Error(w, err.Error(), status)
 			return
 		}
-		if settings != nil {
-			if err := model.ValidateRecipientDomains(schedule.Recipients, settings.Limits.AllowedDomains); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
-		}
-
-		// Validate CRON expression if provided
-		if schedule.CronExpr != "" {
-			if err := model.ValidateCronExpression(schedule.CronExpr); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
+		if !h.checkDashboardAccess(w, r, &schedule) {
+			return
 		}
 
 		// Recalculate next run time only if schedule is enabled
@@ -229,14 +254,21 @@ func (h *Handler) handleSchedule(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		h.recordScheduleAudit(r, model.AuditScheduleUpdate, schedule.ID, existing, &schedule)
 
+		schedule.MaskSecrets()
 		respondJSON(w, schedule)
 
 	case http.MethodDelete:
+		existing, ok := h.manageableSchedule(w, r, scheduleID)
+		if !ok {
+			return
+		}
 		if err := h.store.DeleteSchedule(orgID, scheduleID); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		h.recordScheduleAudit(r, model.AuditScheduleDelete, scheduleID, existing, nil)
 		w.WriteHeader(http.StatusNoContent)
 
 	default:
@@ -244,9 +276,42 @@ func (h *Handler) handleSchedule(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// checkDashboardAccess rejects schedules whose owner cannot view all of their dashboards with
+// 400 Bad Request, and responds with 500 Internal Server Error when Grafana can't tell. It returns
+// whether the schedule passed.
+func (h *Handler) checkDashboardAccess(w http.ResponseWriter, r *http.Request, schedule *model.Schedule) bool {
+	err := h.scheduler.CheckDashboardAccess(r.Context(), schedule)
+	switch {
+	case errors.Is(err, cron.ErrNoDashboardAccess):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	case err != nil:
+		http.Error(w, "Failed to verify dashboard access: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// manageableSchedule loads a schedule the user may manage. Otherwise it responds with 404 Not Found
+// or 403 Forbidden and returns false.
+func (h *Handler) manageableSchedule(w http.ResponseWriter, r *http.Request, scheduleID int64) (*model.Schedule, bool) {
+	id := requestIdentity(r)
+	schedule, err := h.store.GetSchedule(id.OrgID, scheduleID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return nil, false
+	}
+	h.claimLegacyOwner(r, schedule)
+	if !id.canManage(schedule) {
+		forbidden(w, "only the schedule's owner or an admin can manage it")
+		return nil, false
+	}
+	return schedule, true
+}
+
 // handleRun handles run-related operations
 func (h *Handler) handleRun(w http.ResponseWriter, r *http.Request) {
-	orgID := getOrgID(r)
+	orgID := requestIdentity(r).OrgID
 	path := r.URL.Path
 
 	var runID int64
@@ -264,25 +329,57 @@ func (h *Handler) handleRun(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
+		schedule, err := h.store.GetSchedule(orgID, run.ScheduleID)
+		if err != nil {
+			http.Error(w, "Schedule not found", http.StatusNotFound)
+			return
+		}
 
-		// Check if artifact is stored in database (new method)
-		if len(run.ArtifactData) > 0 {
-			// Get schedule to retrieve the name for filename
-			schedule, err := h.store.GetSchedule(orgID, run.ScheduleID)
+		// Reports can be downloaded by those managing the schedule and by their recipients, and by
+		// every member of the org through the signed links sent to chat and webhook channels
+		query := r.URL.Query()
+		signed := h.scheduler.VerifyArtifactLink(orgID, runID, query.Get("expires"), query.Get("signature"))
+		h.claimLegacyOwner(r, schedule)
+		if id := requestIdentity(r); !signed && !id.canManage(schedule) && !id.receives(schedule, run) {
+			forbidden(w, "only the schedule's owner, an admin, a recipient of the report or a signed link can download it")
+			return
+		}
+
+		// Stream the artifact from the storage backend holding it
+		if len(run.ArtifactData) > 0 || run.StorageKey != "" {
+			artifactStore, err := h.scheduler.ArtifactStore(orgID, run.StorageBackend)
 			if err != nil {
-				http.Error(w, "Schedule not found", http.StatusNotFound)
+				http.Error(w, fmt.Sprintf("Failed to open artifact storage: %v", err), http.StatusInternalServerError)
 				return
 			}
+			reader, size, err := artifactStore.Open(r.Context(), run)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			defer reader.Close()
+			h.recordAudit(r, model.AuditArtifactDownload, model.AuditResourceRun, run.ID, nil, nil)
 
 			// Generate filename from schedule name and timestamp
+			// Runs created before image formats existed have no content type and are PDFs
+			contentType := run.ContentType
+			if contentType == "" {
+				contentType = model.ContentTypePDF
+			}
 			timestamp := run.StartedAt.Format("2006-01-02-150405")
-			filename := fmt.Sprintf("%s-%s.pdf", strings.ReplaceAll(schedule.Name, " ", "_"), timestamp)
+			filename := fmt.Sprintf("%s-%s%s", strings.ReplaceAll(schedule.Name, " ", "_"), timestamp, model.ArtifactExtension(contentType))
 
-			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Type", contentType)
 			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
-			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(run.ArtifactData)))
-			w.Write(run.ArtifactData)
-			log.Printf("Served artifact from database: schedule_id=%d, run_id=%d, size=%d bytes", run.ScheduleID, run.ID, len(run.ArtifactData))
+			if size >= 0 {
+				w.Header().Set("Content-Length", fmt.Sprintf("%d", size))
+			}
+			written, err := io.Copy(w, reader)
+			if err != nil {
+				log.Printf("Failed to stream artifact for run %d: %v", run.ID, err)
+				return
+			}
+			log.Printf("Served artifact from %s storage: schedule_id=%d, run_id=%d, size=%d bytes", storageName(run.StorageBackend), run.ScheduleID, run.ID, written)
 			return
 		}
 
@@ -295,11 +392,17 @@ func (h *Handler) handleRun(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			defer file.Close()
+			h.recordAudit(r, model.AuditArtifactDownload, model.AuditResourceRun, run.ID, nil, nil)
 
 			// Set content type based on file extension
-			contentType := "application/pdf"
-			if len(run.ArtifactPath) >= 4 && run.ArtifactPath[len(run.ArtifactPath)-4:] == ".png" {
-				contentType = "image/png"
+			contentType := model.ContentTypePDF
+			switch strings.ToLower(filepath.Ext(run.ArtifactPath)) {
+			case ".png":
+				contentType = model.ContentTypePNG
+			case ".jpg", ".jpeg":
+				contentType = model.ContentTypeJPEG
+			case ".zip":
+				contentType = model.ContentTypeZIP
 			}
 
 			// Extract filename from path
@@ -317,12 +420,334 @@ func (h *Handler) handleRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Path format: /api/runs/{id}/deliveries
+	if action == "deliveries" && r.Method == http.MethodGet {
+		run, err := h.store.GetRun(orgID, runID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if _, ok := h.manageableSchedule(w, r, run.ScheduleID); !ok {
+			return
+		}
+		deliveries, err := h.store.ListDeliveries(orgID, runID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		respondJSON(w, map[string]interface{}{"deliveries": deliveries})
+		return
+	}
+
+	// Path format: /api/runs/{id}/redeliver
+	if action == "redeliver" && r.Method == http.MethodPost {
+		h.handleRedeliver(w, r, orgID, runID)
+		return
+	}
+
 	http.Error(w, "Invalid action", http.StatusBadRequest)
 }
 
+// handleRedeliver resends a run's stored artifact to its failed deliveries, or to the
+// deliveries listed in the optional body {"delivery_ids": [...]}, without rendering again
+func (h *Handler) handleRedeliver(w http.ResponseWriter, r *http.Request, orgID, runID int64) {
+	var req struct {
+		DeliveryIDs []int64 `json:"delivery_ids"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	run, err := h.store.GetRun(orgID, runID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	schedule, ok := h.manageableSchedule(w, r, run.ScheduleID)
+	if !ok {
+		return
+	}
+	if run.Status != "completed" {
+		http.Error(w, "Only completed runs can be redelivered", http.StatusConflict)
+		return
+	}
+
+	deliveries, err := h.store.ListDeliveries(orgID, runID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var selected model.DeliveryResults
+	if len(req.DeliveryIDs) == 0 {
+		for _, d := range deliveries {
+			if !d.Success {
+				selected = append(selected, d)
+			}
+		}
+	} else {
+		byID := make(map[int64]model.DeliveryResult, len(deliveries))
+		for _, d := range deliveries {
+			byID[d.ID] = d
+		}
+		for _, id := range req.DeliveryIDs {
+			d, ok := byID[id]
+			if !ok {
+				http.Error(w, fmt.Sprintf("Delivery %d not found for run %d", id, runID), http.StatusBadRequest)
+				return
+			}
+			selected = append(selected, d)
+		}
+	}
+	if len(selected) == 0 {
+		http.Error(w, "No failed deliveries to resend", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.scheduler.Redeliver(r.Context(), schedule, run, selected)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to redeliver: %v", err), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, map[string]interface{}{"deliveries": results})
+}
+
+// validateSchedule checks a schedule against the org's limits and the schedule rules before it is
+// saved. On failure it returns the error with the HTTP status to respond with.
+func (h *Handler) validateSchedule(orgID int64, schedule *model.Schedule) (int, error) {
+	// Validate recipient email domains and count against org limits
+	settings, err := h.store.GetSettings(orgID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("Failed to get settings: %v", err)
+	}
+	if settings != nil {
+		if err := model.ValidateRecipientDomains(schedule.Recipients, settings.Limits.AllowedDomains); err != nil {
+			return http.StatusBadRequest, err
+		}
+		if err := model.ValidateRecipientCount(schedule.Recipients, settings.Limits.MaxRecipients); err != nil {
+			return http.StatusBadRequest, err
+		}
+		if err := validateBurstRecipients(schedule.Burst, settings.Limits); err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+
+	// Validate CRON expression if provided
+	if schedule.CronExpr != "" {
+		if err := model.ValidateCronExpression(schedule.CronExpr); err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+
+	if err := model.ValidateScheduleFormat(schedule.Format); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := model.ValidateScheduleLayout(schedule.Layout); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := model.ValidateScheduleTheme(schedule.Theme); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := model.ValidateDashboards(schedule.Dashboards, schedule.Format); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := cron.ValidateTimeRanges(schedule); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := model.ValidateDataExport(schedule.DataFormat, schedule.DataOnly); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := model.ValidateDeliveryTargets(schedule.Targets); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := model.ValidateCondition(schedule.Condition); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := model.ValidateBurst(schedule.Burst); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	// Make sure the referenced template belongs to this org
+	if schedule.TemplateID != nil {
+		if _, err := h.store.GetTemplate(orgID, *schedule.TemplateID); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("Invalid template_id %d: %v", *schedule.TemplateID, err)
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// validateBurstRecipients applies the org's recipient limits to the recipients of each burst value
+func validateBurstRecipients(burst *model.Burst, limits model.Limits) error {
+	if burst == nil {
+		return nil
+	}
+	for _, value := range burst.Values {
+		if err := model.ValidateRecipientDomains(value.Recipients, limits.AllowedDomains); err != nil {
+			return fmt.Errorf("burst value '%s': %w", value.Value, err)
+		}
+		if err := model.ValidateRecipientCount(value.Recipients, limits.MaxRecipients); err != nil {
+			return fmt.Errorf("burst value '%s': %w", value.Value, err)
+		}
+	}
+	return nil
+}
+
+// storageName returns the display name of a run's storage backend
+func storageName(backend string) string {
+	if backend == "" {
+		return model.StorageDatabase
+	}
+	return backend
+}
+
+// handleTemplates handles GET /api/templates and POST /api/templates
+func (h *Handler) handleTemplates(w http.ResponseWriter, r *http.Request) {
+	orgID := requestIdentity(r).OrgID
+
+	switch r.Method {
+	case http.MethodGet:
+		templates, err := h.store.ListTemplates(orgID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		respondJSON(w, map[string]interface{}{"templates": templates})
+
+	case http.MethodPost:
+		if !requestIdentity(r).hasRole(roleEditor) {
+			forbidden(w, "creating templates requires the Editor role")
+			return
+		}
+
+		var template model.Template
+		if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		template.OrgID = orgID
+		template.OwnerLogin = requestIdentity(r).Login
+		if template.Kind == "" {
+			template.Kind = "pdf"
+		}
+
+		if err := model.ValidateTemplate(&template); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := h.store.CreateTemplate(&template); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		respondJSON(w, template)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleTemplate handles operations on a specific template
+func (h *Handler) handleTemplate(w http.ResponseWriter, r *http.Request) {
+	orgID := requestIdentity(r).OrgID
+
+	// Path format: /api/templates/{id}
+	var templateID int64
+	if _, err := fmt.Sscanf(r.URL.Path, "/api/templates/%d", &templateID); err != nil {
+		http.Error(w, "Invalid path", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		template, err := h.store.GetTemplate(orgID, templateID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		respondJSON(w, template)
+
+	case http.MethodPut:
+		existing, ok := h.manageableTemplate(w, r, templateID)
+		if !ok {
+			return
+		}
+
+		var template model.Template
+		if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		template.ID = templateID
+		template.OrgID = orgID
+		if template.Kind == "" {
+			template.Kind = "pdf"
+		}
+
+		if err := model.ValidateTemplate(&template); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		template.OwnerLogin = existing.OwnerLogin
+		template.CreatedAt = existing.CreatedAt
+
+		if err := h.store.UpdateTemplate(&template); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		respondJSON(w, template)
+
+	case http.MethodDelete:
+		if _, ok := h.manageableTemplate(w, r, templateID); !ok {
+			return
+		}
+
+		// ?detach=true unlinks referencing schedules instead of refusing the delete
+		detach := r.URL.Query().Get("detach") == "true"
+
+		if err := h.store.DeleteTemplate(orgID, templateID, detach); err != nil {
+			switch {
+			case errors.Is(err, store.ErrTemplateInUse):
+				http.Error(w, err.Error()+" (use ?detach=true to unlink them)", http.StatusConflict)
+			case err.Error() == "template not found":
+				http.Error(w, err.Error(), http.StatusNotFound)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// manageableTemplate loads a template the user may change. Otherwise it responds with 404 Not Found
+// or 403 Forbidden and returns false.
+func (h *Handler) manageableTemplate(w http.ResponseWriter, r *http.Request, templateID int64) (*model.Template, bool) {
+	id := requestIdentity(r)
+	template, err := h.store.GetTemplate(id.OrgID, templateID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return nil, false
+	}
+	if !id.canManageTemplate(template) {
+		forbidden(w, "only the template's owner or an admin can change it")
+		return nil, false
+	}
+	return template, true
+}
+
 // handleSettings handles settings operations
 func (h *Handler) handleSettings(w http.ResponseWriter, r *http.Request) {
-	orgID := getOrgID(r)
+	orgID := requestIdentity(r).OrgID
 
 	switch r.Method {
 	case http.MethodGet:
@@ -362,6 +787,7 @@ func (h *Handler) handleSettings(w http.ResponseWriter, r *http.Request) {
 					MaxConcurrentRenders: 5,
 					RetentionDays:        30,
 				},
+				Storage: model.StorageConfig{Backend: model.StorageDatabase},
 			}
 		} else {
 			// Ensure smtp_config has defaults if it's nil
@@ -377,6 +803,7 @@ func (h *Handler) handleSettings(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 		}
+		settings.MaskSecrets()
 		respondJSON(w, settings)
 
 	case http.MethodPost:
@@ -388,16 +815,31 @@ func (h *Handler) handleSettings(w http.ResponseWriter, r *http.Request) {
 
 		settings.OrgID = orgID
 
+		// Secrets are write-only: the masked values the UI sends back keep the stored ones
+		stored, err := h.store.GetSettings(orgID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		settings.KeepSecrets(stored)
+
+		if err := model.ValidateStorageConfig(settings.Storage); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		if err := h.store.UpsertSettings(&settings); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		h.recordSettingsAudit(r, stored, &settings)
 
 		// Clear renderer cache to force recreation with new settings
 		if err := h.scheduler.ClearRendererCache(orgID); err != nil {
 			log.Printf("Warning: Failed to clear renderer cache for org %d: %v", orgID, err)
 		}
 
+		settings.MaskSecrets()
 		respondJSON(w, settings)
 
 	default:
@@ -407,27 +849,6 @@ func (h *Handler) handleSettings(w http.ResponseWriter, r *http.Request) {
 
 // Helper functions
 
-func getOrgID(r *http.Request) int64 {
-	// In a real plugin, this would come from the Grafana request context
-	// For now, we'll try to get it from a header or default to 1
-	orgIDStr := r.Header.Get("X-Grafana-Org-Id")
-	if orgIDStr == "" {
-		return 1
-	}
-	orgID, _ := strconv.ParseInt(orgIDStr, 10, 64)
-	return orgID
-}
-
-func getUserID(r *http.Request) int64 {
-	// In a real plugin, this would come from the Grafana request context
-	userIDStr := r.Header.Get("X-Grafana-User-Id")
-	if userIDStr == "" {
-		return 1
-	}
-	userID, _ := strconv.ParseInt(userIDStr, 10, 64)
-	return userID
-}
-
 func respondJSON(w http.ResponseWriter, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(data)
@@ -725,6 +1146,19 @@ func (h *Handler) handleSMTPTest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Test with the stored password while it is unchanged in the form
+	if smtpConfig.Password == model.SecretUnchanged {
+		smtpConfig.Password = ""
+		stored, err := h.store.GetSettings(requestIdentity(r).OrgID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if stored != nil && stored.SMTPConfig != nil {
+			smtpConfig.Password = stored.SMTPConfig.Password
+		}
+	}
+
 	// Validate required fields
 	if smtpConfig.Host == "" {
 		respondJSON(w, map[string]interface{}{
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/cron"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/store"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	st, err := store.NewStore(filepath.Join(t.TempDir(), "validate.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+	if err := st.UpsertSettings(&model.Settings{OrgID: 1, Limits: model.Limits{AllowedDomains: []string{"example.com"}}}); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+	h := NewHandler(st, cron.NewScheduler(st, "http://localhost:3000", t.TempDir(), 1))
+
+	missingTemplate := int64(42)
+	tests := []struct {
+		name   string
+		modify func(s *model.Schedule)
+		want   int
+	}{
+		{"valid", func(s *model.Schedule) {}, http.StatusOK},
+		{"recipient outside the allowed domains", func(s *model.Schedule) { s.Recipients.To = []string{"a@other.com"} }, http.StatusBadRequest},
+		{"burst recipient outside the allowed domains", func(s *model.Schedule) {
+			s.Burst = &model.Burst{Variable: "region", Values: []model.BurstValue{
+				{Value: "emea", Recipients: model.Recipients{To: []string{"a@other.com"}}},
+			}}
+		}, http.StatusBadRequest},
+		{"invalid cron expression", func(s *model.Schedule) { s.CronExpr = "not cron" }, http.StatusBadRequest},
+		{"unknown format", func(s *model.Schedule) { s.Format = "docx" }, http.StatusBadRequest},
+		{"unknown layout", func(s *model.Schedule) { s.Layout = "poster" }, http.StatusBadRequest},
+		{"template of another org", func(s *model.Schedule) { s.TemplateID = &missingTemplate }, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &model.Schedule{
+				OrgID: 1, Name: "Weekly", DashboardUID: "abc", IntervalType: "weekly", Timezone: "UTC",
+				RangeFrom: "now-7d", RangeTo: "now", Recipients: model.Recipients{To: []string{"a@example.com"}},
+			}
+			tt.modify(schedule)
+			status, err := h.validateSchedule(1, schedule)
+			if status != tt.want || (err == nil) != (tt.want == http.StatusOK) {
+				t.Errorf("validateSchedule() = %d, %v; want %d", status, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// identity is the Grafana user a request is made by, taken from the plugin context Grafana sends
+// with every resource call. Client supplied headers are never trusted for it.
+type identity struct {
+	OrgID int64
+	Login string
+	Email string
+	Name  string
+	Role  string // Org role: Viewer, Editor or Admin
+}
+
+// identityKey is the request context key of the caller's identity
+type identityKey struct{}
+
+// identityFromPluginContext returns the identity in the plugin context of ctx, which httpadapter
+// sets from the CallResourceRequest
+func identityFromPluginContext(ctx context.Context) (*identity, error) {
+	pluginCtx := backend.PluginConfigFromContext(ctx)
+	if pluginCtx.OrgID <= 0 {
+		return nil, errors.New("request has no organization")
+	}
+	user := pluginCtx.User
+	if user == nil || user.Login == "" {
+		return nil, errors.New("request has no user")
+	}
+	return &identity{
+		OrgID: pluginCtx.OrgID,
+		Login: user.Login,
+		Email: user.Email,
+		Name:  user.Name,
+		Role:  user.Role,
+	}, nil
+}
+
+// authenticate rejects requests without an org and user, and passes the identity of the others to next
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := identityFromPluginContext(r.Context())
+		if err != nil {
+			http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, id)))
+	})
+}
+
+// requestIdentity returns the identity of a request that passed authenticate
+func requestIdentity(r *http.Request) *identity {
+	return r.Context().Value(identityKey{}).(*identity)
+}
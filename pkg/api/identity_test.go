@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func TestAuthenticate(t *testing.T) {
+	var got *identity
+	handler := authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = requestIdentity(r)
+	}))
+
+	editor := &backend.User{Login: "editor", Email: "editor@example.com", Role: "Editor"}
+	tests := []struct {
+		name       string
+		pluginCtx  *backend.PluginContext
+		wantStatus int
+	}{
+		{"identified", &backend.PluginContext{OrgID: 2, User: editor}, http.StatusOK},
+		{"no plugin context", nil, http.StatusUnauthorized},
+		{"no org", &backend.PluginContext{User: editor}, http.StatusUnauthorized},
+		{"no user", &backend.PluginContext{OrgID: 2}, http.StatusUnauthorized},
+		{"anonymous user", &backend.PluginContext{OrgID: 2, User: &backend.User{Role: "Viewer"}}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest(http.MethodGet, "/api/schedules", nil)
+			// Headers never identify the caller
+			req.Header.Set("X-Grafana-Org-Id", "1")
+			req.Header.Set("X-Grafana-User-Id", "1")
+			if tt.pluginCtx != nil {
+				req = req.WithContext(backend.WithPluginContext(req.Context(), *tt.pluginCtx))
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if got != nil {
+					t.Errorf("handler was called with %+v", got)
+				}
+				return
+			}
+			if got == nil || got.OrgID != 2 || got.Login != "editor" || got.Email != "editor@example.com" || got.Role != "Editor" {
+				t.Errorf("identity = %+v, want org 2 editor", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// ErrNoDashboardAccess is returned when the owner of a schedule cannot view one of its dashboards,
+// or the dashboard was deleted. Reports are rendered with the plugin's service account, so they
+// must never include dashboards their owner could not open.
+var ErrNoDashboardAccess = errors.New("no dashboard access")
+
+// CheckDashboardAccess verifies through Grafana's API that the schedule's owner can view all of its
+// dashboards. Failures to reach Grafana are returned as they are, loss of access wraps ErrNoDashboardAccess.
+func (s *Scheduler) CheckDashboardAccess(ctx context.Context, schedule *model.Schedule) error {
+	settings, err := s.getCachedSettings(schedule.OrgID)
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	if settings == nil {
+		settings = &model.Settings{}
+	}
+	grafanaURL := s.grafanaURL
+	if settings.RendererConfig.GrafanaURL != "" {
+		grafanaURL = settings.RendererConfig.GrafanaURL
+	}
+	return s.checkDashboardAccess(ctx, schedule, settings, grafanaURL)
+}
+
+// OrgUserID looks up the Grafana user ID of the org member with the login, which is 0 when
+// no member has it. The plugin context identifies users by login only.
+func (s *Scheduler) OrgUserID(ctx context.Context, orgID int64, login string) (int64, error) {
+	settings, err := s.getCachedSettings(orgID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get settings: %w", err)
+	}
+	if settings == nil {
+		settings = &model.Settings{}
+	}
+	grafanaURL := s.grafanaURL
+	if settings.RendererConfig.GrafanaURL != "" {
+		grafanaURL = settings.RendererConfig.GrafanaURL
+	}
+
+	token, err := grafana.ServiceAccountToken(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("no service account token available: %w", err)
+	}
+	client := grafana.NewClient(grafanaURL, token, orgID, settings.RendererConfig.SkipTLSVerify)
+	user, err := client.FindOrgUser(ctx, login, 0)
+	if err != nil || user == nil {
+		return 0, err
+	}
+	return user.UserID, nil
+}
+
+// checkDashboardAccess runs CheckDashboardAccess with the service account token from ctx
+func (s *Scheduler) checkDashboardAccess(ctx context.Context, schedule *model.Schedule, settings *model.Settings, grafanaURL string) error {
+	// Schedules created before owners were recorded have none to check
+	if schedule.OwnerLogin == "" && schedule.OwnerUserID == 0 {
+		return nil
+	}
+
+	token, err := grafana.ServiceAccountToken(ctx)
+	if err != nil {
+		return fmt.Errorf("no service account token available: %w", err)
+	}
+
+	client := grafana.NewClient(grafanaURL, token, schedule.OrgID, settings.RendererConfig.SkipTLSVerify)
+	return verifyDashboardAccess(ctx, client, schedule)
+}
+
+// verifyDashboardAccess checks every dashboard of the schedule against its owner's permissions
+func verifyDashboardAccess(ctx context.Context, client *grafana.Client, schedule *model.Schedule) error {
+	owner, err := client.FindOrgUser(ctx, schedule.OwnerLogin, schedule.OwnerUserID)
+	if err != nil {
+		return fmt.Errorf("failed to look up the schedule owner: %w", err)
+	}
+	name := schedule.OwnerLogin
+	if name == "" {
+		name = fmt.Sprintf("user %d", schedule.OwnerUserID)
+	}
+	if owner == nil {
+		return fmt.Errorf("%w: schedule owner %s is no longer a member of the organization", ErrNoDashboardAccess, name)
+	}
+
+	for _, part := range reportDashboards(schedule) {
+		uid := part.DashboardUID
+		ok, err := client.CanView(ctx, owner, uid)
+		if grafana.IsNotFound(err) {
+			return fmt.Errorf("%w: dashboard %s no longer exists", ErrNoDashboardAccess, uid)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check access to dashboard %s: %w", uid, err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: schedule owner %s cannot view dashboard %s", ErrNoDashboardAccess, name, uid)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+func TestVerifyDashboardAccess(t *testing.T) {
+	responses := map[string]string{
+		"/api/org/users": `[
+			{"userId": 2, "login": "alice", "role": "Viewer"},
+			{"userId": 3, "login": "bob", "role": "Editor"},
+			{"userId": 4, "login": "carol", "role": "Admin"}
+		]`,
+		// Viewers may view the folder, editors the dashboard
+		"/api/dashboards/uid/shared/permissions": `[
+			{"role": "Viewer", "permission": 1, "inherited": true},
+			{"role": "Editor", "permission": 2}
+		]`,
+		"/api/dashboards/uid/editors/permissions": `[{"role": "Editor", "permission": 1}]`,
+		"/api/dashboards/uid/team/permissions":    `[{"teamId": 7, "permission": 1}, {"role": "Editor", "permission": 1}]`,
+		"/api/dashboards/uid/direct/permissions":  `[{"userId": 2, "permission": 1}]`,
+		"/api/teams/7/members":                    `[{"userId": 2}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.Error(w, `{"message":"Dashboard not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	client := grafana.NewClient(server.URL, "token", 1, false)
+
+	tests := []struct {
+		name      string
+		schedule  *model.Schedule
+		wantLost  bool
+		wantError string
+	}{
+		{name: "role", schedule: &model.Schedule{OwnerLogin: "alice", DashboardUID: "shared"}},
+		{name: "team", schedule: &model.Schedule{OwnerLogin: "alice", DashboardUID: "team"}},
+		{name: "user", schedule: &model.Schedule{OwnerLogin: "alice", DashboardUID: "direct"}},
+		{name: "legacy owner by ID", schedule: &model.Schedule{OwnerUserID: 3, DashboardUID: "editors"}},
+		{name: "admin", schedule: &model.Schedule{OwnerLogin: "carol", DashboardUID: "editors"}},
+		{name: "role too low", schedule: &model.Schedule{OwnerLogin: "alice", DashboardUID: "editors"}, wantLost: true, wantError: "alice cannot view dashboard editors"},
+		{name: "not in team", schedule: &model.Schedule{OwnerLogin: "bob", DashboardUID: "direct"}, wantLost: true},
+		{
+			name: "one dashboard of many",
+			schedule: &model.Schedule{OwnerLogin: "alice", Dashboards: model.DashboardRefs{
+				{UID: "shared"}, {UID: "editors"},
+			}},
+			wantLost: true, wantError: "dashboard editors",
+		},
+		{name: "deleted dashboard", schedule: &model.Schedule{OwnerLogin: "carol", DashboardUID: "gone"}, wantLost: true, wantError: "no longer exists"},
+		{name: "owner left the org", schedule: &model.Schedule{OwnerLogin: "dave", DashboardUID: "shared"}, wantLost: true, wantError: "no longer a member"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyDashboardAccess(context.Background(), client, tt.schedule)
+			if tt.wantLost != errors.Is(err, ErrNoDashboardAccess) {
+				t.Fatalf("verifyDashboardAccess() error = %v, want lost access %v", err, tt.wantLost)
+			}
+			if !tt.wantLost && err != nil {
+				t.Fatalf("verifyDashboardAccess() error = %v", err)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("verifyDashboardAccess() error = %q, want it to contain %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestVerifyDashboardAccessGrafanaDown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := verifyDashboardAccess(context.Background(), grafana.NewClient(server.URL, "token", 1, false), &model.Schedule{OwnerLogin: "alice", DashboardUID: "shared"})
+	if err == nil || errors.Is(err, ErrNoDashboardAccess) {
+		t.Errorf("verifyDashboardAccess() error = %v, want a failure that isn't lost access", err)
+	}
+}
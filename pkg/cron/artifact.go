@@ -0,0 +1,110 @@
+package cron
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/mail"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
+	"github.com/yourusername/scheduled-reports-app/pkg/render"
+)
+
+// artifact is a rendered report ready to be stored and delivered
+type artifact struct {
+	data        []byte
+	contentType string
+	pages       int
+}
+
+// zipEntry is a single file added to a ZIP archive
+type zipEntry struct {
+	name string
+	data []byte
+}
+
+// renderArtifact renders the schedule in its configured format.
+// Image reports with more than one panel are bundled into a ZIP archive, multi-dashboard
+// reports are combined into one PDF, and PDF reports get the template's cover page and table of
+// contents.
+func renderArtifact(ctx context.Context, renderer render.Backend, schedule *model.Schedule, opts render.Options) (*artifact, error) {
+	switch schedule.Format {
+	case model.FormatPNG, model.FormatJPEG:
+		images, err := renderer.RenderImages(ctx, schedule, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(images) == 0 {
+			return nil, fmt.Errorf("renderer returned no images")
+		}
+
+		contentType := model.FormatContentType(schedule.Format)
+		if len(images) == 1 {
+			return &artifact{data: images[0], contentType: contentType, pages: 1}, nil
+		}
+
+		ext := model.ArtifactExtension(contentType)
+		entries := make([]zipEntry, 0, len(images))
+		for i, image := range images {
+			name := fmt.Sprintf("image-%d%s", i+1, ext)
+			if i < len(schedule.PanelIDs) {
+				name = fmt.Sprintf("panel-%d%s", schedule.PanelIDs[i], ext)
+			}
+			entries = append(entries, zipEntry{name: name, data: image})
+		}
+
+		data, err := zipFiles(entries)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive images: %w", err)
+		}
+		return &artifact{data: data, contentType: model.ContentTypeZIP, pages: len(images)}, nil
+
+	default:
+		if len(schedule.Dashboards) > 0 {
+			return renderCombined(ctx, renderer, schedule, opts)
+		}
+		doc, err := renderer.RenderDashboard(ctx, schedule, opts)
+		if err != nil {
+			return nil, err
+		}
+		if tmpl := opts.Template; wantsFrontMatter(tmpl) {
+			title := schedule.DashboardTitle
+			if title == "" {
+				title = schedule.DashboardUID
+			}
+			section := pdf.Section{Title: title, Data: doc.Data, Entries: doc.Contents}
+			return composeReport(ctx, renderer, schedule, opts, []pdf.Section{section}, title, tmpl.CoverPage, tmpl.TableOfContents)
+		}
+		return &artifact{data: doc.Data, contentType: model.ContentTypePDF, pages: pdf.PageCount(doc.Data)}, nil
+	}
+}
+
+// bundleAttachments returns the single attachment as-is, or a ZIP archive containing all of them
+func bundleAttachments(attachments []mail.Attachment, baseName string) (mail.Attachment, error) {
+	if len(attachments) == 1 {
+		return attachments[0], nil
+	}
+
+	return compressAttachments(attachments, baseName)
+}
+
+// zipFiles bundles the entries into an in-memory ZIP archive
+func zipFiles(entries []zipEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(entry.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
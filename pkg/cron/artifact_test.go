@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
+	"github.com/yourusername/scheduled-reports-app/pkg/render"
+)
+
+// fakeBackend returns canned output instead of driving a browser
+type fakeBackend struct {
+	pdf      []byte
+	contents []pdf.Entry
+	images   [][]byte
+	logo     []byte
+	rendered []*model.Schedule // Schedules passed to RenderDashboard
+	logos    []string          // Logo URLs passed to FetchLogo
+}
+
+func (f *fakeBackend) RenderDashboard(ctx context.Context, schedule *model.Schedule, opts render.Options) (*render.Document, error) {
+	f.rendered = append(f.rendered, schedule)
+	return &render.Document{Data: f.pdf, Contents: f.contents}, nil
+}
+
+func (f *fakeBackend) RenderImages(ctx context.Context, schedule *model.Schedule, opts render.Options) ([][]byte, error) {
+	return f.images, nil
+}
+
+func (f *fakeBackend) FetchLogo(ctx context.Context, logoURL string) ([]byte, error) {
+	f.logos = append(f.logos, logoURL)
+	if f.logo == nil {
+		return nil, fmt.Errorf("logo not found")
+	}
+	return f.logo, nil
+}
+
+func (f *fakeBackend) Close() error { return nil }
+
+func (f *fakeBackend) Name() string { return "fake" }
+
+func TestRenderArtifact(t *testing.T) {
+	backend := &fakeBackend{
+		pdf:    []byte("%PDF-1.4\n<</Type /Pages>>\n<</Type /Page>>\n<</Type /Page>>\n"),
+		images: [][]byte{[]byte("first"), []byte("second")},
+	}
+
+	tests := []struct {
+		name            string
+		schedule        *model.Schedule
+		images          [][]byte
+		wantContentType string
+		wantPages       int
+		wantZipEntries  []string
+	}{
+		{
+			name:            "pdf by default",
+			schedule:        &model.Schedule{},
+			wantContentType: model.ContentTypePDF,
+			wantPages:       2,
+		},
+		{
+			name:            "single png",
+			schedule:        &model.Schedule{Format: model.FormatPNG},
+			images:          [][]byte{[]byte("only")},
+			wantContentType: model.ContentTypePNG,
+			wantPages:       1,
+		},
+		{
+			name:            "jpeg panels are zipped",
+			schedule:        &model.Schedule{Format: model.FormatJPEG, PanelIDs: model.IntSlice{4, 2}},
+			images:          [][]byte{[]byte("first"), []byte("second")},
+			wantContentType: model.ContentTypeZIP,
+			wantPages:       2,
+			wantZipEntries:  []string{"panel-4.jpg", "panel-2.jpg"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend.images = tt.images
+			report, err := renderArtifact(context.Background(), backend, tt.schedule, render.Options{})
+			if err != nil {
+				t.Fatalf("renderArtifact() error = %v", err)
+			}
+			if report.contentType != tt.wantContentType {
+				t.Errorf("contentType = %q, want %q", report.contentType, tt.wantContentType)
+			}
+			if report.pages != tt.wantPages {
+				t.Errorf("pages = %d, want %d", report.pages, tt.wantPages)
+			}
+
+			if tt.wantZipEntries == nil {
+				return
+			}
+			zr, err := zip.NewReader(bytes.NewReader(report.data), int64(len(report.data)))
+			if err != nil {
+				t.Fatalf("artifact is not a ZIP archive: %v", err)
+			}
+			if len(zr.File) != len(tt.wantZipEntries) {
+				t.Fatalf("archive has %d entries, want %d", len(zr.File), len(tt.wantZipEntries))
+			}
+			for i, f := range zr.File {
+				if f.Name != tt.wantZipEntries[i] {
+					t.Errorf("entry %d = %q, want %q", i, f.Name, tt.wantZipEntries[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// executeBurst renders and emails one report per burst value, each recorded as a child run of the
+// schedule's run. Reports are produced one after another in the parent's worker slot; the parent
+// run fails when any of them failed. Bursting stops when the owner lost access to a dashboard,
+// which fails every report alike.
+func (s *Scheduler) executeBurst(schedule *model.Schedule, run *model.Run) error {
+	values := schedule.Burst.Values
+	failed := 0
+	for _, value := range values {
+		child := &model.Run{
+			ScheduleID:  schedule.ID,
+			OrgID:       schedule.OrgID,
+			StartedAt:   time.Now(),
+			Status:      "running",
+			ParentRunID: &run.ID,
+			BurstValue:  value.Value,
+			RangeFrom:   run.RangeFrom,
+			RangeTo:     run.RangeTo,
+		}
+		if err := s.store.CreateRun(child); err != nil {
+			log.Printf("[EXECUTE] ERROR: Failed to create run record for %s=%s of schedule ID=%d: %v", schedule.Burst.Variable, value.Value, schedule.ID, err)
+			failed++
+			continue
+		}
+
+		log.Printf("[EXECUTE] Bursting schedule ID=%d: rendering %s=%s as run ID=%d", schedule.ID, schedule.Burst.Variable, value.Value, child.ID)
+		err := s.runReport(burstSchedule(schedule, value), child)
+		if errors.Is(err, ErrNoDashboardAccess) {
+			return err
+		}
+		if child.Status == "failed" {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d burst reports failed", failed, len(values))
+	}
+	return nil
+}
+
+// burstSchedule returns the schedule as rendered for one burst value: the burst variable is set to
+// the value (replacing all of its values, also in dashboards of a multi-dashboard report that set
+// it) and the report goes only to the value's recipients
+func burstSchedule(schedule *model.Schedule, value model.BurstValue) *model.Schedule {
+	burst := *schedule
+	burst.Burst = nil
+	burst.Recipients = value.Recipients
+	burst.Targets = nil
+
+	variable := schedule.Burst.Variable
+	burst.Variables = setVariable(schedule.Variables, variable, value.Value, true)
+	if len(schedule.Dashboards) > 0 {
+		burst.Dashboards = make(model.DashboardRefs, len(schedule.Dashboards))
+		for i, ref := range schedule.Dashboards {
+			ref.Variables = setVariable(ref.Variables, variable, value.Value, false)
+			burst.Dashboards[i] = ref
+		}
+	}
+	return &burst
+}
+
+// setVariable returns a copy of the variables with name set to a single value.
+// A missing variable is appended only when add is set.
+func setVariable(variables model.VariableList, name, value string, add bool) model.VariableList {
+	result := make(model.VariableList, 0, len(variables)+1)
+	set := false
+	for _, v := range variables {
+		if v.Name == name {
+			if set {
+				continue
+			}
+			v.Value = value
+			set = true
+		}
+		result = append(result, v)
+	}
+	if !set && add {
+		result = append(result, model.Variable{Name: name, Value: value})
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package cron
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+func TestBurstSchedule(t *testing.T) {
+	schedule := &model.Schedule{
+		Name:       "Regional",
+		Recipients: model.Recipients{To: []string{"all@example.com"}},
+		Targets:    model.DeliveryTargets{{Type: model.TargetSlack, Slack: &model.SlackTarget{Token: "xoxb-1", Channel: "C1"}}},
+		Burst: &model.Burst{Variable: "region", Values: []model.BurstValue{
+			{Value: "emea", Recipients: model.Recipients{To: []string{"emea@example.com"}}},
+		}},
+	}
+	value := schedule.Burst.Values[0]
+
+	tests := []struct {
+		name      string
+		variables model.VariableList
+		want      model.VariableList
+	}{
+		{
+			name:      "replaces the variable",
+			variables: model.VariableList{{Name: "env", Value: "prod"}, {Name: "region", Value: "all", IsOriginal: true}},
+			want:      model.VariableList{{Name: "env", Value: "prod"}, {Name: "region", Value: "emea", IsOriginal: true}},
+		},
+		{
+			name:      "collapses multiple values",
+			variables: model.VariableList{{Name: "region", Value: "emea"}, {Name: "region", Value: "apac"}},
+			want:      model.VariableList{{Name: "region", Value: "emea"}},
+		},
+		{
+			name:      "adds a missing variable",
+			variables: model.VariableList{{Name: "env", Value: "prod"}},
+			want:      model.VariableList{{Name: "env", Value: "prod"}, {Name: "region", Value: "emea"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule.Variables = tt.variables
+			burst := burstSchedule(schedule, value)
+
+			if !reflect.DeepEqual(burst.Variables, tt.want) {
+				t.Errorf("Variables = %+v, want %+v", burst.Variables, tt.want)
+			}
+			if !reflect.DeepEqual(burst.Recipients, value.Recipients) {
+				t.Errorf("Recipients = %+v, want %+v", burst.Recipients, value.Recipients)
+			}
+			if burst.Targets != nil || burst.Burst != nil {
+				t.Errorf("burst schedule keeps targets %+v or burst %+v", burst.Targets, burst.Burst)
+			}
+			// The schedule itself is left untouched
+			if !reflect.DeepEqual(schedule.Variables, tt.variables) || schedule.Recipients.To[0] != "all@example.com" {
+				t.Errorf("burstSchedule() modified the schedule: %+v", schedule)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// conditionRefID is the refId of the condition query
+const conditionRefID = "A"
+
+// checkCondition runs the schedule's condition query through Grafana and reports whether the
+// condition is met, with a description of the values that decided it
+func (s *Scheduler) checkCondition(ctx context.Context, schedule *model.Schedule, settings *model.Settings, grafanaURL string) (bool, string, error) {
+	token, err := grafana.ServiceAccountToken(ctx)
+	if err != nil {
+		return false, "", fmt.Errorf("no service account token available: %w", err)
+	}
+
+	client := grafana.NewClient(grafanaURL, token, schedule.OrgID, settings.RendererConfig.SkipTLSVerify)
+	return evaluateCondition(ctx, client, schedule)
+}
+
+// evaluateCondition queries the condition's data source over the schedule's time range and variables
+func evaluateCondition(ctx context.Context, client *grafana.Client, schedule *model.Schedule) (bool, string, error) {
+	condition := schedule.Condition
+	vars := grafana.VariablesFromSchedule(schedule)
+
+	query := vars.Interpolate(condition.Query).(map[string]interface{})
+	query["refId"] = conditionRefID
+	query["datasource"] = map[string]interface{}{"uid": condition.DatasourceUID}
+
+	resp, err := client.QueryData(ctx, grafana.QueryRequest{
+		From:    schedule.RangeFrom,
+		To:      schedule.RangeTo,
+		Queries: []map[string]interface{}{query},
+	})
+	if err != nil {
+		return false, "", err
+	}
+	result, ok := resp.Results[conditionRefID]
+	if !ok {
+		return false, "", fmt.Errorf("condition query returned no result")
+	}
+	if result.Error != "" {
+		return false, "", fmt.Errorf("condition query: %s", result.Error)
+	}
+
+	met, reason := conditionMet(condition, result.Frames)
+	return met, reason, nil
+}
+
+// conditionMet reduces every numeric series in the frames and compares it against the threshold.
+// The condition is met as soon as one series matches; queries without numeric data never match.
+func conditionMet(condition *model.Condition, frames []grafana.Frame) (bool, string) {
+	var checked []string
+	for _, frame := range frames {
+		for i, field := range frame.Schema.Fields {
+			if field.Type != "number" || i >= len(frame.Data.Values) {
+				continue
+			}
+
+			value, ok := reduce(condition.Reducer, frame.Data.Values[i])
+			if !ok {
+				continue
+			}
+			name := seriesName(frame, field)
+			if condition.Compare(value) {
+				return true, fmt.Sprintf("%s of %s is %g (%s %g)", condition.Reducer, name, value, condition.Operator, condition.Threshold)
+			}
+			checked = append(checked, fmt.Sprintf("%s=%g", name, value))
+		}
+	}
+
+	if len(checked) == 0 {
+		return false, "Condition not met: the query returned no numeric data"
+	}
+	return false, fmt.Sprintf("Condition not met: %s %s %g is false for %v", condition.Reducer, condition.Operator, condition.Threshold, checked)
+}
+
+// reduce turns a series into a single value, ignoring nulls; ok is false for series without values
+// (except for count, which is 0)
+func reduce(reducer string, values []interface{}) (float64, bool) {
+	var numbers []float64
+	for _, value := range values {
+		if number, ok := value.(float64); ok && !math.IsNaN(number) {
+			numbers = append(numbers, number)
+		}
+	}
+	if reducer == model.ReducerCount {
+		return float64(len(numbers)), true
+	}
+	if len(numbers) == 0 {
+		return 0, false
+	}
+
+	result := numbers[0]
+	switch reducer {
+	case model.ReducerLast:
+		result = numbers[len(numbers)-1]
+	case model.ReducerMin:
+		for _, n := range numbers {
+			result = math.Min(result, n)
+		}
+	case model.ReducerMax:
+		for _, n := range numbers {
+			result = math.Max(result, n)
+		}
+	case model.ReducerSum, model.ReducerMean:
+		result = 0
+		for _, n := range numbers {
+			result += n
+		}
+		if reducer == model.ReducerMean {
+			result /= float64(len(numbers))
+		}
+	}
+	return result, true
+}
+
+// seriesName identifies a series in skip reasons: its display name, or the field name with labels
+func seriesName(frame grafana.Frame, field grafana.Field) string {
+	if field.Config.DisplayName != "" {
+		return field.Config.DisplayName
+	}
+	name := field.Name
+	if frame.Schema.Name != "" && (name == "" || name == "Value") {
+		name = frame.Schema.Name
+	}
+	if len(field.Labels) > 0 {
+		name = fmt.Sprintf("%s%v", name, field.Labels)
+	}
+	return name
+}
@@ -0,0 +1,102 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+func TestConditionMet(t *testing.T) {
+	var frames []grafana.Frame
+	if err := json.Unmarshal([]byte(`[
+		{"schema": {"name": "errors", "fields": [{"name": "Time", "type": "time"}, {"name": "Value", "type": "number", "labels": {"host": "a"}}]},
+		 "data": {"values": [[1, 2, 3], [2, null, 8]]}},
+		{"schema": {"fields": [{"name": "Value", "type": "number", "config": {"displayName": "host b"}}]},
+		 "data": {"values": [[1, 1, 1]]}}
+	]`), &frames); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		reducer   string
+		operator  string
+		threshold float64
+		frames    []grafana.Frame
+		wantMet   bool
+		wantIn    string
+	}{
+		{name: "last above threshold", reducer: model.ReducerLast, operator: ">", threshold: 5, frames: frames, wantMet: true, wantIn: "last of errors"},
+		{name: "max below threshold", reducer: model.ReducerMax, operator: ">", threshold: 10, frames: frames, wantMet: false, wantIn: "host b=1"},
+		{name: "mean ignores nulls", reducer: model.ReducerMean, operator: "==", threshold: 5, frames: frames, wantMet: true},
+		{name: "sum of second series", reducer: model.ReducerSum, operator: "<=", threshold: 3, frames: frames, wantMet: true, wantIn: "host b"},
+		{name: "count", reducer: model.ReducerCount, operator: ">=", threshold: 3, frames: frames, wantMet: true, wantIn: "host b"},
+		{name: "min", reducer: model.ReducerMin, operator: "<", threshold: 1, frames: frames, wantMet: false},
+		{name: "no data", reducer: model.ReducerLast, operator: ">", threshold: 0, frames: nil, wantMet: false, wantIn: "no numeric data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := &model.Condition{Reducer: tt.reducer, Operator: tt.operator, Threshold: tt.threshold}
+			met, reason := conditionMet(condition, tt.frames)
+			if met != tt.wantMet {
+				t.Errorf("conditionMet() = %v (%s), want %v", met, reason, tt.wantMet)
+			}
+			if !strings.Contains(reason, tt.wantIn) {
+				t.Errorf("conditionMet() reason = %q, want it to contain %q", reason, tt.wantIn)
+			}
+		})
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &request)
+		w.Write([]byte(`{"results": {"A": {"frames": [{"schema": {"fields": [{"name": "Value", "type": "number"}]}, "data": {"values": [[0, 3]]}}]}}}`))
+	}))
+	defer server.Close()
+
+	schedule := &model.Schedule{
+		OrgID:     1,
+		RangeFrom: "now-1d",
+		RangeTo:   "now",
+		Variables: model.VariableList{{Name: "env", Value: "prod"}},
+		Condition: &model.Condition{
+			DatasourceUID: "prom",
+			Query:         map[string]interface{}{"expr": "sum(errors{env=\"$env\"})"},
+			Reducer:       model.ReducerLast,
+			Operator:      ">",
+			Threshold:     0,
+		},
+	}
+	client := grafana.NewClient(server.URL, "token", 1, false)
+
+	met, _, err := evaluateCondition(context.Background(), client, schedule)
+	if err != nil {
+		t.Fatalf("evaluateCondition() error = %v", err)
+	}
+	if !met {
+		t.Error("evaluateCondition() = false, want true")
+	}
+
+	queries, _ := request["queries"].([]interface{})
+	if len(queries) != 1 {
+		t.Fatalf("expected 1 query, got %v", request["queries"])
+	}
+	query := queries[0].(map[string]interface{})
+	if query["expr"] != `sum(errors{env="prod"})` || query["refId"] != "A" {
+		t.Errorf("unexpected query %v", query)
+	}
+	if request["from"] != "now-1d" {
+		t.Errorf("from = %v, want now-1d", request["from"])
+	}
+}
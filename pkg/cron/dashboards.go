@@ -0,0 +1,71 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
+	"github.com/yourusername/scheduled-reports-app/pkg/render"
+)
+
+// reportDashboards returns the schedule as rendered for each of its dashboards, in report order.
+// Single-dashboard schedules are returned as they are.
+func reportDashboards(schedule *model.Schedule) []*model.Schedule {
+	if len(schedule.Dashboards) == 0 {
+		return []*model.Schedule{schedule}
+	}
+
+	parts := make([]*model.Schedule, 0, len(schedule.Dashboards))
+	for _, ref := range schedule.Dashboards {
+		parts = append(parts, dashboardSchedule(schedule, ref))
+	}
+	return parts
+}
+
+// dashboardSchedule returns the schedule as rendered for one dashboard of a multi-dashboard report
+func dashboardSchedule(schedule *model.Schedule, ref model.DashboardRef) *model.Schedule {
+	part := *schedule
+	part.Dashboards = nil
+	part.DashboardUID = ref.UID
+	part.DashboardTitle = ref.Title
+	if part.DashboardTitle == "" {
+		part.DashboardTitle = ref.UID
+	}
+	part.PanelIDs = ref.PanelIDs
+	if ref.RangeFrom != "" {
+		part.RangeFrom, part.RangeTo = ref.RangeFrom, ref.RangeTo
+	}
+
+	// The dashboard's own variables replace schedule variables of the same name
+	overridden := make(map[string]bool, len(ref.Variables))
+	for _, v := range ref.Variables {
+		overridden[v.Name] = true
+	}
+	part.Variables = make(model.VariableList, 0, len(schedule.Variables)+len(ref.Variables))
+	for _, v := range schedule.Variables {
+		if !overridden[v.Name] {
+			part.Variables = append(part.Variables, v)
+		}
+	}
+	part.Variables = append(part.Variables, ref.Variables...)
+	return &part
+}
+
+// renderCombined renders each dashboard of the schedule to PDF and merges them into one report
+// behind a generated cover page and table of contents
+func renderCombined(ctx context.Context, renderer render.Backend, schedule *model.Schedule, opts render.Options) (*artifact, error) {
+	parts := reportDashboards(schedule)
+	sections := make([]pdf.Section, 0, len(parts))
+	for i, part := range parts {
+		log.Printf("DEBUG: Rendering dashboard %d/%d (%s) of schedule %d", i+1, len(parts), part.DashboardUID, schedule.ID)
+		doc, err := renderer.RenderDashboard(ctx, part, opts)
+		if err != nil {
+			return nil, fmt.Errorf("dashboard %s: %w", part.DashboardTitle, err)
+		}
+		sections = append(sections, pdf.Section{Title: part.DashboardTitle, Data: doc.Data, Entries: doc.Contents})
+	}
+
+	return composeReport(ctx, renderer, schedule, opts, sections, fmt.Sprintf("%d dashboards", len(sections)), true, true)
+}
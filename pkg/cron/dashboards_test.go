@@ -0,0 +1,106 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
+	"github.com/yourusername/scheduled-reports-app/pkg/render"
+)
+
+func TestDashboardSchedule(t *testing.T) {
+	schedule := &model.Schedule{
+		Name:         "Ops pack",
+		DashboardUID: "primary",
+		RangeFrom:    "now-7d",
+		RangeTo:      "now",
+		Variables:    model.VariableList{{Name: "env", Value: "prod"}, {Name: "host", Value: "a"}, {Name: "host", Value: "b"}},
+	}
+
+	tests := []struct {
+		name          string
+		ref           model.DashboardRef
+		wantTitle     string
+		wantFrom      string
+		wantVariables model.VariableList
+	}{
+		{
+			name:          "inherits the schedule",
+			ref:           model.DashboardRef{UID: "net", Title: "Network"},
+			wantTitle:     "Network",
+			wantFrom:      "now-7d",
+			wantVariables: schedule.Variables,
+		},
+		{
+			name:          "own range and variables",
+			ref:           model.DashboardRef{UID: "db", RangeFrom: "now-1d", RangeTo: "now", Variables: model.VariableList{{Name: "host", Value: "db1"}}},
+			wantTitle:     "db",
+			wantFrom:      "now-1d",
+			wantVariables: model.VariableList{{Name: "env", Value: "prod"}, {Name: "host", Value: "db1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			part := dashboardSchedule(schedule, tt.ref)
+			if part.DashboardUID != tt.ref.UID || part.DashboardTitle != tt.wantTitle || part.RangeFrom != tt.wantFrom {
+				t.Errorf("dashboardSchedule() = uid %s, title %s, from %s", part.DashboardUID, part.DashboardTitle, part.RangeFrom)
+			}
+			if !reflect.DeepEqual(part.Variables, tt.wantVariables) {
+				t.Errorf("Variables = %+v, want %+v", part.Variables, tt.wantVariables)
+			}
+		})
+	}
+}
+
+func TestRenderCombined(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	page, err := pdf.NewGenerator().Generate([][]byte{buf.Bytes()}, pdf.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &fakeBackend{pdf: page}
+	schedule := &model.Schedule{
+		Name:     "Ops pack",
+		Format:   model.FormatPDF,
+		Timezone: "Europe/Berlin",
+		Dashboards: model.DashboardRefs{
+			{UID: "overview", Title: "Overview"},
+			{UID: "latency", Title: "Latency", PanelIDs: model.IntSlice{4}},
+			{UID: "errors", Title: "Errors"},
+		},
+	}
+
+	report, err := renderArtifact(context.Background(), backend, schedule, render.Options{})
+	if err != nil {
+		t.Fatalf("renderArtifact() error = %v", err)
+	}
+	if report.contentType != model.ContentTypePDF {
+		t.Errorf("contentType = %s, want PDF", report.contentType)
+	}
+	// Cover, contents and one page per dashboard
+	if report.pages != 5 {
+		t.Errorf("pages = %d, want 5", report.pages)
+	}
+
+	var uids []string
+	for _, rendered := range backend.rendered {
+		uids = append(uids, rendered.DashboardUID)
+	}
+	if !reflect.DeepEqual(uids, []string{"overview", "latency", "errors"}) {
+		t.Errorf("rendered dashboards = %v, want them in report order", uids)
+	}
+	if len(backend.rendered[1].PanelIDs) != 1 || backend.rendered[1].PanelIDs[0] != 4 {
+		t.Errorf("latency panels = %v, want [4]", backend.rendered[1].PanelIDs)
+	}
+}
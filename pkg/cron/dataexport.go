@@ -0,0 +1,166 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/export"
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+	"github.com/yourusername/scheduled-reports-app/pkg/mail"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// unsafeFilenameChars matches characters replaced in attachment file names
+var unsafeFilenameChars = regexp.MustCompile(`[^\w.\- ]+`)
+
+// exportData fetches the panel query results of the schedule and writes them as attachments
+func (s *Scheduler) exportData(ctx context.Context, schedule *model.Schedule, settings *model.Settings, grafanaURL, baseName string) ([]mail.Attachment, error) {
+	token, err := grafana.ServiceAccountToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("no service account token available: %w", err)
+	}
+
+	client := grafana.NewClient(grafanaURL, token, schedule.OrgID, settings.RendererConfig.SkipTLSVerify)
+	var tables []export.Table
+	for _, part := range reportDashboards(schedule) {
+		partTables, err := exportPanelData(ctx, client, part)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, partTables...)
+	}
+
+	return dataAttachments(tables, schedule.DataFormat, baseName)
+}
+
+// exportPanelData queries each selected panel (every panel when none are selected) through
+// Grafana's query API for the schedule's time range and variables, returning one table per data frame
+func exportPanelData(ctx context.Context, client *grafana.Client, schedule *model.Schedule) ([]export.Table, error) {
+	dashboard, err := client.GetDashboard(ctx, schedule.DashboardUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var panels []grafana.Panel
+	if len(schedule.PanelIDs) == 0 {
+		panels = dashboard.AllPanels()
+	} else {
+		for _, id := range schedule.PanelIDs {
+			panel, ok := dashboard.FindPanel(id)
+			if !ok {
+				return nil, fmt.Errorf("panel %d not found in dashboard %s", id, schedule.DashboardUID)
+			}
+			panels = append(panels, panel)
+		}
+	}
+
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	vars := grafana.VariablesFromSchedule(schedule)
+
+	var tables []export.Table
+	for _, panel := range panels {
+		queries, err := client.PanelQueries(ctx, panel, vars)
+		if err != nil {
+			return nil, err
+		}
+		if len(queries) == 0 {
+			continue // Text, news and other panels without queries
+		}
+
+		resp, err := client.QueryData(ctx, grafana.QueryRequest{
+			From:    schedule.RangeFrom,
+			To:      schedule.RangeTo,
+			Queries: queries,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("panel %d (%s): %w", panel.ID, panel.Title, err)
+		}
+
+		title := panel.Title
+		if title == "" {
+			title = fmt.Sprintf("Panel %d", panel.ID)
+		}
+
+		// Keep the panel's query order so tables come out in a predictable order
+		var frames []grafana.Frame
+		for _, query := range queries {
+			refID, _ := query["refId"].(string)
+			result, ok := resp.Results[refID]
+			if !ok {
+				continue
+			}
+			if result.Error != "" {
+				return nil, fmt.Errorf("panel %d (%s) query %s: %s", panel.ID, panel.Title, refID, result.Error)
+			}
+			frames = append(frames, result.Frames...)
+		}
+
+		for i, frame := range frames {
+			name := title
+			if len(frames) > 1 {
+				name = fmt.Sprintf("%s %d", title, i+1)
+			}
+			tables = append(tables, export.TableFromFrame(name, frame, loc))
+		}
+	}
+
+	log.Printf("Exported %d table(s) from %d panel(s) of dashboard %s", len(tables), len(panels), schedule.DashboardUID)
+	return tables, nil
+}
+
+// dataAttachments writes the tables in the schedule's data format: one CSV file per table,
+// or a single XLSX workbook with one sheet per table
+func dataAttachments(tables []export.Table, dataFormat, baseName string) ([]mail.Attachment, error) {
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no panel data to export")
+	}
+
+	if dataFormat == model.DataFormatXLSX {
+		data, err := export.XLSX(tables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write XLSX: %w", err)
+		}
+		return []mail.Attachment{{
+			Filename:    baseName + model.ArtifactExtension(model.ContentTypeXLSX),
+			ContentType: model.ContentTypeXLSX,
+			Data:        data,
+		}}, nil
+	}
+
+	attachments := make([]mail.Attachment, 0, len(tables))
+	used := map[string]int{}
+	for _, table := range tables {
+		data, err := export.CSV(table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write CSV for %s: %w", table.Name, err)
+		}
+
+		name := fmt.Sprintf("%s-%s", baseName, sanitizeFilename(table.Name))
+		used[name]++
+		if used[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, used[name])
+		}
+		attachments = append(attachments, mail.Attachment{
+			Filename:    name + model.ArtifactExtension(model.ContentTypeCSV),
+			ContentType: model.ContentTypeCSV,
+			Data:        data,
+		})
+	}
+	return attachments, nil
+}
+
+// sanitizeFilename replaces characters that are unsafe in attachment file names
+func sanitizeFilename(name string) string {
+	name = strings.TrimSpace(unsafeFilenameChars.ReplaceAllString(name, "_"))
+	if name == "" {
+		return "data"
+	}
+	return name
+}
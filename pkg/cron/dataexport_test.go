@@ -0,0 +1,102 @@
+package cron
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/export"
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+func TestExportPanelData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/dashboards/uid/dash":
+			w.Write([]byte(`{"dashboard": {"uid": "dash", "panels": [
+				{"id": 1, "title": "CPU", "datasource": {"uid": "prom"}, "targets": [{"refId": "A", "expr": "cpu"}]},
+				{"id": 2, "title": "Memory / host", "datasource": {"uid": "prom"}, "targets": [{"refId": "A", "expr": "mem"}]},
+				{"id": 3, "type": "text", "title": "Notes"}
+			]}}`))
+		case "/api/ds/query":
+			w.Write([]byte(`{"results": {"A": {"frames": [{"schema": {"fields": [{"name": "Value", "type": "number"}]}, "data": {"values": [[1, 2]]}}]}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := grafana.NewClient(server.URL, "token", 1, false)
+
+	tests := []struct {
+		name       string
+		panelIDs   model.IntSlice
+		wantTables []string
+		wantErr    bool
+	}{
+		{name: "all panels with queries", wantTables: []string{"CPU", "Memory / host"}},
+		{name: "selected panels in listed order", panelIDs: model.IntSlice{2, 1}, wantTables: []string{"Memory / host", "CPU"}},
+		{name: "unknown panel", panelIDs: model.IntSlice{9}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &model.Schedule{DashboardUID: "dash", PanelIDs: tt.panelIDs, RangeFrom: "now-1h", RangeTo: "now", Timezone: "UTC"}
+			tables, err := exportPanelData(context.Background(), client, schedule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("exportPanelData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(tables) != len(tt.wantTables) {
+				t.Fatalf("got %d tables, want %d", len(tables), len(tt.wantTables))
+			}
+			for i, table := range tables {
+				if table.Name != tt.wantTables[i] {
+					t.Errorf("table %d = %q, want %q", i, table.Name, tt.wantTables[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDataAttachments(t *testing.T) {
+	tables := []export.Table{
+		{Name: "CPU", Columns: []string{"Value"}, Rows: [][]interface{}{{float64(1)}}},
+		{Name: "Memory / host", Columns: []string{"Value"}, Rows: [][]interface{}{{float64(2)}}},
+		{Name: "CPU", Columns: []string{"Value"}, Rows: [][]interface{}{{float64(3)}}},
+	}
+
+	csvFiles, err := dataAttachments(tables, model.DataFormatCSV, "Report-2024")
+	if err != nil {
+		t.Fatalf("dataAttachments(csv) error = %v", err)
+	}
+	wantNames := []string{"Report-2024-CPU.csv", "Report-2024-Memory _ host.csv", "Report-2024-CPU-2.csv"}
+	if len(csvFiles) != len(wantNames) {
+		t.Fatalf("got %d CSV files, want %d", len(csvFiles), len(wantNames))
+	}
+	for i, file := range csvFiles {
+		if file.Filename != wantNames[i] {
+			t.Errorf("file %d = %q, want %q", i, file.Filename, wantNames[i])
+		}
+		if file.ContentType != model.ContentTypeCSV {
+			t.Errorf("file %d content type = %q", i, file.ContentType)
+		}
+	}
+	if !strings.HasPrefix(string(csvFiles[1].Data), "Value\n2\n") {
+		t.Errorf("unexpected CSV content %q", csvFiles[1].Data)
+	}
+
+	xlsxFiles, err := dataAttachments(tables, model.DataFormatXLSX, "Report-2024")
+	if err != nil {
+		t.Fatalf("dataAttachments(xlsx) error = %v", err)
+	}
+	if len(xlsxFiles) != 1 || xlsxFiles[0].Filename != "Report-2024.xlsx" || xlsxFiles[0].ContentType != model.ContentTypeXLSX {
+		t.Errorf("unexpected XLSX attachments: %+v", xlsxFiles)
+	}
+
+	if _, err := dataAttachments(nil, model.DataFormatCSV, "Report"); err == nil {
+		t.Error("dataAttachments() should fail without tables")
+	}
+}
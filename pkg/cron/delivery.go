@@ -0,0 +1,186 @@
+package cron
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/delivery"
+	"github.com/yourusername/scheduled-reports-app/pkg/mail"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// pluginID is the app plugin ID under which Grafana serves the plugin's resource API
+const pluginID = "fulgerx2007-scheduled-reports-app"
+
+// attachmentPlan describes how report files are attached to the email
+type attachmentPlan struct {
+	mode        string
+	attachments []mail.Attachment // Files to attach (none when linking)
+}
+
+// planDelivery fits the report files into the org's attachment size limit.
+// Files are attached as-is when they fit, zipped into one archive when that fits,
+// and otherwise left out so the email can link to the stored artifact instead.
+// A maxMB of zero or less means no limit.
+func planDelivery(attachments []mail.Attachment, maxMB int, baseName string) (*attachmentPlan, error) {
+	if maxMB <= 0 {
+		return &attachmentPlan{mode: model.DeliveryAttachment, attachments: attachments}, nil
+	}
+	maxBytes := int64(maxMB) * 1024 * 1024
+
+	if attachmentsSize(attachments) <= maxBytes {
+		return &attachmentPlan{mode: model.DeliveryAttachment, attachments: attachments}, nil
+	}
+
+	archive, err := compressAttachments(attachments, baseName)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(archive.Data)) <= maxBytes {
+		return &attachmentPlan{mode: model.DeliveryCompressed, attachments: []mail.Attachment{archive}}, nil
+	}
+
+	return &attachmentPlan{mode: model.DeliveryLink}, nil
+}
+
+// attachmentsSize returns the combined size of the attachments in bytes
+func attachmentsSize(attachments []mail.Attachment) int64 {
+	var total int64
+	for _, attachment := range attachments {
+		total += int64(len(attachment.Data))
+	}
+	return total
+}
+
+// compressAttachments zips the attachments into one archive, even when there is only one
+func compressAttachments(attachments []mail.Attachment, baseName string) (mail.Attachment, error) {
+	entries := make([]zipEntry, 0, len(attachments))
+	for _, attachment := range attachments {
+		entries = append(entries, zipEntry{name: attachment.Filename, data: attachment.Data})
+	}
+	data, err := zipFiles(entries)
+	if err != nil {
+		return mail.Attachment{}, fmt.Errorf("failed to compress attachments: %w", err)
+	}
+
+	return mail.Attachment{
+		Filename:    baseName + model.ArtifactExtension(model.ContentTypeZIP),
+		ContentType: model.ContentTypeZIP,
+		Data:        data,
+	}, nil
+}
+
+// artifactURL returns the Grafana URL downloading a run's artifact
+func artifactURL(grafanaURL string, runID int64) string {
+	return fmt.Sprintf("%s/api/plugins/%s/resources/api/runs/%d/artifact", strings.TrimSuffix(grafanaURL, "/"), pluginID, runID)
+}
+
+// artifactLinkKey names the store's signing key of artifact links
+const artifactLinkKey = "artifact_links"
+
+// ArtifactLinkTTL is how long signed artifact links can be opened
+const ArtifactLinkTTL = 7 * 24 * time.Hour
+
+// SignedArtifactURL returns the URL downloading a run's artifact, signed so that every member of the
+// org can open it until it expires, not only those managing the schedule and its recipients.
+// Chat and webhook messages link to it, as their readers are not email recipients. The plain URL is
+// returned when the signing key can't be loaded.
+func (s *Scheduler) SignedArtifactURL(grafanaURL string, orgID, runID int64) string {
+	key, err := s.store.SigningKey(artifactLinkKey)
+	if err != nil {
+		log.Printf("WARNING: Failed to load the artifact link key, linking run %d unsigned: %v", runID, err)
+		return artifactURL(grafanaURL, runID)
+	}
+	expires := time.Now().Add(ArtifactLinkTTL).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", artifactLinkSignature(key, orgID, runID, expires))
+	return artifactURL(grafanaURL, runID) + "?" + query.Encode()
+}
+
+// VerifyArtifactLink reports whether the expires and signature parameters of a signed artifact link
+// are valid for the run and the link has not expired
+func (s *Scheduler) VerifyArtifactLink(orgID, runID int64, expires, signature string) bool {
+	if expires == "" || signature == "" {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+	key, err := s.store.SigningKey(artifactLinkKey)
+	if err != nil {
+		log.Printf("WARNING: Failed to load the artifact link key: %v", err)
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(artifactLinkSignature(key, orgID, runID, expiresAt)))
+}
+
+// artifactLinkSignature signs the org, run and expiry of an artifact link
+func artifactLinkSignature(key []byte, orgID, runID, expires int64) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%d:%d:%d", orgID, runID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// downloadLinkNotice is appended to the email body when the report is too large to attach
+func downloadLinkNotice(url string, size int64, maxMB int) string {
+	return fmt.Sprintf(`<p>The report (%.1f MB) exceeds the %d MB attachment limit. <a href="%s">Download it from Grafana</a>.</p>`,
+		float64(size)/(1024*1024), maxMB, html.EscapeString(url))
+}
+
+// emailChannel sends the report to the schedule's recipients, fitting attachments into the size limit
+type emailChannel struct {
+	mailer     *mail.Mailer // nil when SMTP is not configured
+	recipients model.Recipients
+	plan       *attachmentPlan
+	maxMB      int
+	size       int64 // Combined size of the report files before compression
+}
+
+// newEmailChannel creates the email channel for an org; it fails to deliver when SMTP is not configured
+func newEmailChannel(settings *model.Settings, recipients model.Recipients, plan *attachmentPlan, size int64) *emailChannel {
+	email := &emailChannel{
+		recipients: recipients,
+		plan:       plan,
+		maxMB:      settings.Limits.MaxAttachmentSizeMB,
+		size:       size,
+	}
+	if settings.SMTPConfig != nil {
+		email.mailer = mail.NewMailer(*settings.SMTPConfig)
+	}
+	return email
+}
+
+// Type returns the target type
+func (e *emailChannel) Type() string {
+	return model.TargetEmail
+}
+
+// Target describes the recipients
+func (e *emailChannel) Target() string {
+	count := len(e.recipients.To) + len(e.recipients.CC) + len(e.recipients.BCC)
+	return fmt.Sprintf("%d recipient(s)", count)
+}
+
+// Deliver sends the email; oversized reports are linked rather than attached
+func (e *emailChannel) Deliver(ctx context.Context, report *delivery.Report) error {
+	if e.mailer == nil {
+		return fmt.Errorf("SMTP not configured")
+	}
+
+	body := report.Body
+	if e.plan.mode == model.DeliveryLink {
+		body += downloadLinkNotice(report.ArtifactURL, e.size, e.maxMB)
+	}
+	return e.mailer.SendReport(e.recipients, report.Subject, body, e.plan.attachments)
+}
@@ -0,0 +1,197 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/delivery"
+	"github.com/yourusername/scheduled-reports-app/pkg/mail"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/store"
+)
+
+// TestPlanDelivery tests that oversized reports are compressed, or linked when compression is not enough
+func TestPlanDelivery(t *testing.T) {
+	const mb = 1024 * 1024
+
+	// Repetitive data compresses well, random data does not
+	compressible := bytes.Repeat([]byte("grafana "), 2*mb/8)
+	incompressible := make([]byte, 2*mb)
+	if _, err := rand.Read(incompressible); err != nil {
+		t.Fatalf("rand.Read() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		data      []byte
+		maxMB     int
+		wantMode  string
+		wantFiles int
+	}{
+		{name: "no limit", data: incompressible, maxMB: 0, wantMode: model.DeliveryAttachment, wantFiles: 2},
+		{name: "fits", data: compressible, maxMB: 5, wantMode: model.DeliveryAttachment, wantFiles: 2},
+		{name: "fits compressed", data: compressible, maxMB: 1, wantMode: model.DeliveryCompressed, wantFiles: 1},
+		{name: "too large", data: incompressible, maxMB: 1, wantMode: model.DeliveryLink, wantFiles: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachments := []mail.Attachment{
+				{Filename: "report.pdf", ContentType: model.ContentTypePDF, Data: tt.data},
+				{Filename: "data.csv", ContentType: model.ContentTypeCSV, Data: []byte("a,b\n1,2\n")},
+			}
+
+			plan, err := planDelivery(attachments, tt.maxMB, "report")
+			if err != nil {
+				t.Fatalf("planDelivery() error = %v", err)
+			}
+			if plan.mode != tt.wantMode {
+				t.Errorf("mode = %q, want %q", plan.mode, tt.wantMode)
+			}
+			if len(plan.attachments) != tt.wantFiles {
+				t.Fatalf("attachments = %d, want %d", len(plan.attachments), tt.wantFiles)
+			}
+			if tt.wantMode == model.DeliveryCompressed && plan.attachments[0].Filename != "report.zip" {
+				t.Errorf("compressed filename = %q, want report.zip", plan.attachments[0].Filename)
+			}
+		})
+	}
+}
+
+func TestDownloadLinkNotice(t *testing.T) {
+	url := artifactURL("http://grafana:3000/", 42)
+	if url != "http://grafana:3000/api/plugins/"+pluginID+"/resources/api/runs/42/artifact" {
+		t.Errorf("artifactURL() = %q", url)
+	}
+
+	notice := downloadLinkNotice(url, 30*1024*1024, 25)
+	if !strings.Contains(notice, "30.0 MB") || !strings.Contains(notice, "25 MB") || !strings.Contains(notice, url) {
+		t.Errorf("downloadLinkNotice() = %q, want size, limit and link", notice)
+	}
+}
+
+// fakeChannel is a delivery channel returning a fixed error
+type fakeChannel struct {
+	targetType string
+	err        error
+	failures   int // Deliveries failing with err before succeeding; all fail when 0
+	delivered  int
+}
+
+func (f *fakeChannel) Type() string   { return f.targetType }
+func (f *fakeChannel) Target() string { return "fake" }
+func (f *fakeChannel) Deliver(ctx context.Context, report *delivery.Report) error {
+	f.delivered++
+	if f.failures > 0 && f.delivered > f.failures {
+		return nil
+	}
+	return f.err
+}
+
+// TestDeliverRecordsEachChannel tests that one failing channel doesn't stop the others and every outcome is recorded
+func TestDeliverRecordsEachChannel(t *testing.T) {
+	scheduler := &Scheduler{}
+	run := &model.Run{ScheduleID: 1}
+	email := &fakeChannel{targetType: model.TargetEmail, err: errors.New("mailbox unavailable")}
+	slack := &fakeChannel{targetType: model.TargetSlack}
+
+	scheduler.deliver(context.Background(), run, &delivery.Report{Run: run}, []deliveryTarget{{channel: email}, {channel: slack}})
+
+	if email.delivered != 1 || slack.delivered != 1 {
+		t.Errorf("delivered email=%d slack=%d, want one attempt each", email.delivered, slack.delivered)
+	}
+	if len(run.Deliveries) != 2 {
+		t.Fatalf("Deliveries = %+v, want 2 results", run.Deliveries)
+	}
+	if run.Deliveries[0].Success || run.Deliveries[0].Error != "mailbox unavailable" {
+		t.Errorf("email result = %+v, want failure", run.Deliveries[0])
+	}
+	if !run.Deliveries[1].Success || run.Deliveries[1].Type != model.TargetSlack {
+		t.Errorf("slack result = %+v, want success", run.Deliveries[1])
+	}
+	if run.EmailSent || run.EmailError != "mailbox unavailable" {
+		t.Errorf("EmailSent = %v, EmailError = %q, want the email failure", run.EmailSent, run.EmailError)
+	}
+}
+
+// TestDeliverRetries tests that failed deliveries are retried up to the target's limit and attempts are recorded
+func TestDeliverRetries(t *testing.T) {
+	defer func(delay time.Duration) { deliveryRetryDelay = delay }(deliveryRetryDelay)
+	deliveryRetryDelay = time.Millisecond
+
+	scheduler := &Scheduler{}
+	run := &model.Run{ScheduleID: 1}
+	flaky := &fakeChannel{targetType: model.TargetSFTP, err: errors.New("connection reset"), failures: 2}
+	down := &fakeChannel{targetType: model.TargetShare, err: errors.New("share not mounted")}
+
+	scheduler.deliver(context.Background(), run, &delivery.Report{Run: run}, []deliveryTarget{{channel: flaky, retries: 3}, {channel: down, retries: 1}})
+
+	if flaky.delivered != 3 || !run.Deliveries[0].Success || run.Deliveries[0].Attempts != 3 {
+		t.Errorf("flaky: delivered %d times, result %+v, want success on the third attempt", flaky.delivered, run.Deliveries[0])
+	}
+	if down.delivered != 2 || run.Deliveries[1].Success || run.Deliveries[1].Attempts != 2 || run.Deliveries[1].Error != "share not mounted" {
+		t.Errorf("down: delivered %d times, result %+v, want failure after 2 attempts", down.delivered, run.Deliveries[1])
+	}
+	if run.EmailSent || run.EmailError != "" {
+		t.Errorf("EmailSent = %v, EmailError = %q, want email untouched", run.EmailSent, run.EmailError)
+	}
+}
+
+// TestSignedArtifactURL tests that signed artifact links verify only for their org and run until they expire
+func TestSignedArtifactURL(t *testing.T) {
+	dbPath := "test_artifact_links.db"
+	defer os.Remove(dbPath)
+
+	st, err := store.NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+	scheduler := NewScheduler(st, "http://localhost:3000", "", 1)
+
+	link, err := url.Parse(scheduler.SignedArtifactURL("https://grafana.example.com", 1, 42))
+	if err != nil {
+		t.Fatalf("SignedArtifactURL() is not a URL: %v", err)
+	}
+	if !strings.HasSuffix(link.Path, "/api/runs/42/artifact") {
+		t.Errorf("link path = %q, want the run's artifact", link.Path)
+	}
+	expires, signature := link.Query().Get("expires"), link.Query().Get("signature")
+	if expiresAt, _ := strconv.ParseInt(expires, 10, 64); time.Until(time.Unix(expiresAt, 0)) > ArtifactLinkTTL {
+		t.Errorf("expires = %q, want within %v", expires, ArtifactLinkTTL)
+	}
+
+	if !scheduler.VerifyArtifactLink(1, 42, expires, signature) {
+		t.Error("VerifyArtifactLink() = false for the signed link")
+	}
+	key, err := st.SigningKey(artifactLinkKey)
+	if err != nil {
+		t.Fatalf("SigningKey() error = %v", err)
+	}
+	past := time.Now().Add(-time.Minute).Unix()
+	tests := []struct {
+		name               string
+		orgID, runID       int64
+		expires, signature string
+	}{
+		{"another org", 2, 42, expires, signature},
+		{"another run", 1, 43, expires, signature},
+		{"extended expiry", 1, 42, expires + "0", signature},
+		{"tampered signature", 1, 42, expires, strings.Repeat("0", len(signature))},
+		{"expired", 1, 42, strconv.FormatInt(past, 10), artifactLinkSignature(key, 1, 42, past)},
+		{"unsigned", 1, 42, "", ""},
+	}
+	for _, tt := range tests {
+		if scheduler.VerifyArtifactLink(tt.orgID, tt.runID, tt.expires, tt.signature) {
+			t.Errorf("%s: VerifyArtifactLink() = true, want false", tt.name)
+		}
+	}
+}
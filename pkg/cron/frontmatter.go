@@ -0,0 +1,79 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
+	"github.com/yourusername/scheduled-reports-app/pkg/render"
+)
+
+// wantsFrontMatter reports whether the template adds a cover page or table of contents to PDF reports
+func wantsFrontMatter(tmpl *model.TemplateConfig) bool {
+	return tmpl != nil && (tmpl.CoverPage || tmpl.TableOfContents)
+}
+
+// composeReport merges rendered dashboards into one PDF behind a cover page (with the given
+// subtitle) and/or a table of contents, using the template's page size, orientation and margins
+func composeReport(ctx context.Context, renderer render.Backend, schedule *model.Schedule, opts render.Options, sections []pdf.Section, subtitle string, cover, contents bool) (*artifact, error) {
+	front := pdf.FrontMatter{Contents: contents}
+	if cover {
+		front.Cover = reportCover(ctx, renderer, schedule, opts, subtitle, time.Now())
+	}
+
+	pdfOpts := pdf.Options{Title: schedule.Name}
+	if opts.Template != nil {
+		pdfOpts.Orientation = opts.Template.Orientation
+		pdfOpts.PageSize = opts.Template.PageSize
+		pdfOpts.Margins = opts.Template.Margins
+	}
+
+	data, err := pdf.NewGenerator().Combine(sections, front, pdfOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose report: %w", err)
+	}
+	return &artifact{data: data, contentType: model.ContentTypePDF, pages: pdf.PageCount(data)}, nil
+}
+
+// reportCover builds the cover page: schedule name, subtitle, the time range resolved to absolute
+// dates and the generation time in the schedule's timezone, and the template logo if it loads
+func reportCover(ctx context.Context, renderer render.Backend, schedule *model.Schedule, opts render.Options, subtitle string, now time.Time) *pdf.Cover {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now = now.In(loc)
+
+	cover := &pdf.Cover{
+		Title:    schedule.Name,
+		Subtitle: subtitle,
+		Details: []string{
+			"Time range: " + timeRangeText(schedule.RangeFrom, schedule.RangeTo, now, loc),
+			"Generated " + now.Format("2006-01-02 15:04 MST"),
+		},
+	}
+
+	if opts.Template != nil && opts.Template.LogoURL != "" {
+		logo, err := renderer.FetchLogo(ctx, opts.Template.LogoURL)
+		if err != nil {
+			log.Printf("WARNING: Failed to load template logo %s for the cover page: %v", opts.Template.LogoURL, err)
+		} else {
+			cover.Logo = logo
+		}
+	}
+	return cover
+}
+
+// timeRangeText describes a dashboard time range with absolute dates, falling back to the raw
+// expressions when they cannot be resolved
+func timeRangeText(from, to string, now time.Time, loc *time.Location) string {
+	start, end, err := grafana.ResolveTimeRange(from, to, now, loc)
+	if err != nil {
+		return fmt.Sprintf("%s to %s", from, to)
+	}
+	return fmt.Sprintf("%s to %s", start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04 MST"))
+}
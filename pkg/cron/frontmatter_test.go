@@ -0,0 +1,103 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
+	"github.com/yourusername/scheduled-reports-app/pkg/render"
+)
+
+func TestRenderArtifactFrontMatter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 200))); err != nil {
+		t.Fatal(err)
+	}
+	dashboard, err := pdf.NewGenerator().Generate([][]byte{buf.Bytes(), buf.Bytes()}, pdf.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		template  *model.TemplateConfig
+		wantPages int
+		wantLinks int
+	}{
+		{"no template", nil, 2, 0},
+		{"template without front matter", &model.TemplateConfig{PageSize: "A4"}, 2, 0},
+		{"cover page", &model.TemplateConfig{CoverPage: true}, 3, 0},
+		// Dashboard line plus two rows, each linked by title and page number
+		{"table of contents", &model.TemplateConfig{TableOfContents: true}, 3, 6},
+		{"both", &model.TemplateConfig{CoverPage: true, TableOfContents: true, LogoURL: "/public/logo.png"}, 4, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fakeBackend{pdf: dashboard, contents: []pdf.Entry{
+				{Title: "Traffic", Page: 1, Y: 10},
+				{Title: "Errors", Page: 2, Y: 10},
+			}}
+			schedule := &model.Schedule{Name: "Daily", DashboardUID: "abc", RangeFrom: "now-1d/d", RangeTo: "now-1d/d"}
+
+			report, err := renderArtifact(context.Background(), backend, schedule, render.Options{Template: tt.template})
+			if err != nil {
+				t.Fatalf("renderArtifact() error = %v", err)
+			}
+			if report.pages != tt.wantPages {
+				t.Errorf("pages = %d, want %d", report.pages, tt.wantPages)
+			}
+			if links := bytes.Count(report.data, []byte("/Subtype /Link")); links != tt.wantLinks {
+				t.Errorf("links = %d, want %d", links, tt.wantLinks)
+			}
+		})
+	}
+}
+
+func TestReportCover(t *testing.T) {
+	now := time.Date(2024, 3, 14, 7, 30, 0, 0, time.UTC)
+	schedule := &model.Schedule{Name: "Weekly", Timezone: "UTC", RangeFrom: "now-7d/d", RangeTo: "now-1d/d"}
+
+	// A logo that fails to load leaves the cover without one
+	backend := &fakeBackend{}
+	opts := render.Options{Template: &model.TemplateConfig{LogoURL: "/public/missing.png"}}
+	cover := reportCover(context.Background(), backend, schedule, opts, "Overview", now)
+	if cover.Title != "Weekly" || cover.Subtitle != "Overview" {
+		t.Errorf("cover = %q / %q, want Weekly / Overview", cover.Title, cover.Subtitle)
+	}
+	if cover.Logo != nil {
+		t.Error("cover has a logo although it failed to load")
+	}
+	want := []string{"Time range: 2024-03-07 00:00 to 2024-03-13 23:59 UTC", "Generated 2024-03-14 07:30 UTC"}
+	if len(cover.Details) != 2 || cover.Details[0] != want[0] || cover.Details[1] != want[1] {
+		t.Errorf("details = %q, want %q", cover.Details, want)
+	}
+
+	backend.logo = []byte("logo")
+	if cover := reportCover(context.Background(), backend, schedule, opts, "", now); string(cover.Logo) != "logo" {
+		t.Errorf("logo = %q, want the fetched logo", cover.Logo)
+	}
+	if len(backend.logos) != 2 || backend.logos[0] != "/public/missing.png" {
+		t.Errorf("fetched logos = %v", backend.logos)
+	}
+}
+
+func TestTimeRangeText(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	now := time.Date(2024, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	if got, want := timeRangeText("now-6h", "now", now, berlin), "2024-03-14 05:00 to 2024-03-14 11:00 CET"; got != want {
+		t.Errorf("timeRangeText() = %q, want %q", got, want)
+	}
+	if got, want := timeRangeText("now-6h", "tomorrow", now, berlin), "now-6h to tomorrow"; got != want {
+		t.Errorf("timeRangeText() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package cron
+
+import "sync"
+
+// limiter is a counting semaphore whose capacity can be changed while slots are held.
+// Shrinking the limit never interrupts running work; new acquisitions wait until the
+// number of active slots drops below the new limit.
+type limiter struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+}
+
+// newLimiter creates a limiter allowing up to limit concurrent holders (minimum 1)
+func newLimiter(limit int) *limiter {
+	l := &limiter{limit: max(limit, 1)}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire blocks until a slot is available and takes it
+func (l *limiter) acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+}
+
+// release frees a slot taken by acquire
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.cond.Signal()
+}
+
+// resize changes the number of concurrent holders allowed (minimum 1)
+func (l *limiter) resize(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = max(limit, 1)
+	// Several waiters may fit under a larger limit
+	l.cond.Broadcast()
+}
+
+// stats returns the current limit and number of held slots
+func (l *limiter) stats() (limit, active int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit, l.active
+}
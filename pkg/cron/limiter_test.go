@@ -0,0 +1,123 @@
+package cron
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/store"
+)
+
+// waitForActive polls until the limiter holds the expected number of slots
+func waitForActive(t *testing.T, l *limiter, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, active := l.stats(); active == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	_, active := l.stats()
+	t.Fatalf("active slots = %d, want %d", active, want)
+}
+
+// TestLimiterBlocksAndResizes tests that acquisitions wait at the limit and resume when it grows
+func TestLimiterBlocksAndResizes(t *testing.T) {
+	l := newLimiter(1)
+	l.acquire()
+
+	acquired := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			l.acquire()
+			acquired <- struct{}{}
+		}()
+	}
+
+	select {
+	case <-acquired:
+		t.Fatal("acquire() succeeded beyond the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Growing the limit lets both waiters in
+	l.resize(3)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-acquired:
+		case <-time.After(2 * time.Second):
+			t.Fatal("waiter not released after resize()")
+		}
+	}
+	waitForActive(t, l, 3)
+
+	// Shrinking keeps running holders but blocks new ones until enough are released
+	l.resize(1)
+	go func() {
+		l.acquire()
+		acquired <- struct{}{}
+	}()
+	l.release()
+	l.release()
+	select {
+	case <-acquired:
+		t.Fatal("acquire() succeeded while active slots were at the reduced limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+	l.release()
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiter not released after slots dropped below the limit")
+	}
+
+	if limit, _ := newLimiter(0).stats(); limit != 1 {
+		t.Errorf("newLimiter(0) limit = %d, want 1", limit)
+	}
+}
+
+// TestOrgLimiterFromSettings tests that org limits come from settings and follow settings changes
+func TestOrgLimiterFromSettings(t *testing.T) {
+	dbPath := "test_org_limits.db"
+	defer os.Remove(dbPath)
+
+	st, err := store.NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	settings := &model.Settings{
+		OrgID:  1,
+		Limits: model.Limits{MaxConcurrentRenders: 2},
+	}
+	if err := st.UpsertSettings(settings); err != nil {
+		t.Fatalf("Failed to create settings: %v", err)
+	}
+
+	scheduler := NewScheduler(st, "http://localhost:3000", "/tmp/artifacts", 10)
+
+	if limit, _ := scheduler.orgLimiter(1).stats(); limit != 2 {
+		t.Errorf("org 1 limit = %d, want 2", limit)
+	}
+	// Orgs without settings use the default
+	if limit, _ := scheduler.orgLimiter(2).stats(); limit != defaultMaxConcurrentRenders {
+		t.Errorf("org 2 limit = %d, want %d", limit, defaultMaxConcurrentRenders)
+	}
+	if scheduler.orgLimiter(1) != scheduler.orgLimiter(1) {
+		t.Error("orgLimiter() returned a different limiter for the same org")
+	}
+
+	settings.Limits.MaxConcurrentRenders = 4
+	if err := st.UpsertSettings(settings); err != nil {
+		t.Fatalf("Failed to update settings: %v", err)
+	}
+	if err := scheduler.ClearRendererCache(1); err != nil {
+		t.Fatalf("ClearRendererCache() error = %v", err)
+	}
+	if limit, _ := scheduler.orgLimiter(1).stats(); limit != 4 {
+		t.Errorf("org 1 limit after settings change = %d, want 4", limit)
+	}
+}
@@ -0,0 +1,154 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/delivery"
+	"github.com/yourusername/scheduled-reports-app/pkg/mail"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// Redeliver resends a completed run's stored artifact to the given deliveries of the run, without
+// rendering again. Targets are taken from the schedule's current configuration, so corrected
+// settings (e.g. a fixed Slack channel) apply. Each delivery is attempted once, without the
+// target's retries, so the caller gets the outcome promptly. The outcomes are saved and returned.
+func (s *Scheduler) Redeliver(ctx context.Context, schedule *model.Schedule, run *model.Run, deliveries model.DeliveryResults) (model.DeliveryResults, error) {
+	settings, err := s.getCachedSettings(run.OrgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	if settings == nil {
+		return nil, fmt.Errorf("no settings configured for org %d", run.OrgID)
+	}
+
+	// Reports of a bursting schedule go to the recipients of their value
+	if run.BurstValue != "" {
+		var value *model.BurstValue
+		if schedule.Burst != nil {
+			value = schedule.Burst.Find(run.BurstValue)
+		}
+		if value == nil {
+			return nil, fmt.Errorf("burst value '%s' is no longer configured on the schedule", run.BurstValue)
+		}
+		schedule = burstSchedule(schedule, *value)
+	}
+
+	file, err := s.loadArtifact(ctx, schedule, run)
+	if err != nil {
+		return nil, err
+	}
+
+	grafanaURL := s.grafanaURL
+	if settings.RendererConfig.GrafanaURL != "" {
+		grafanaURL = settings.RendererConfig.GrafanaURL
+	}
+	vars := templateVars(schedule, run)
+	report := &delivery.Report{
+		Schedule:          schedule,
+		Run:               run,
+		Subject:           mail.InterpolateTemplate(schedule.EmailSubject, vars),
+		Body:              mail.InterpolateTemplate(schedule.EmailBody, vars),
+		File:              file,
+		ArtifactURL:       artifactURL(grafanaURL, run.ID),
+		SignedArtifactURL: s.SignedArtifactURL(grafanaURL, run.OrgID, run.ID),
+	}
+
+	results := make(model.DeliveryResults, 0, len(deliveries))
+	for _, previous := range deliveries {
+		target, err := s.redeliveryTarget(settings, schedule, run, file, previous)
+		if err != nil {
+			results = append(results, deliveryResult(previous.Index, previous.Type, previous.Target, 0, err))
+			continue
+		}
+
+		log.Printf("Redelivering run %d via %s to %s...", run.ID, target.channel.Type(), target.channel.Target())
+		err = target.channel.Deliver(ctx, report)
+		if err != nil {
+			log.Printf("Failed to redeliver run %d via %s: %v", run.ID, target.channel.Type(), err)
+		}
+		results = append(results, deliveryResult(target.index, target.channel.Type(), target.channel.Target(), 1, err))
+	}
+	s.recordDeliveries(run, results)
+
+	// The email outcome is also kept in the run's original fields
+	for _, result := range results {
+		if result.Type != model.TargetEmail {
+			continue
+		}
+		run.EmailSent = result.Success
+		run.EmailError = result.Error
+		if err := s.store.UpdateRun(run); err != nil {
+			log.Printf("WARNING: Failed to update run record with redelivery status: %v", err)
+		}
+	}
+	return results, nil
+}
+
+// redeliveryTarget creates the channel for a previous delivery from the schedule's current configuration
+func (s *Scheduler) redeliveryTarget(settings *model.Settings, schedule *model.Schedule, run *model.Run, file mail.Attachment, previous model.DeliveryResult) (*deliveryTarget, error) {
+	if previous.Index == model.EmailTargetIndex {
+		if len(schedule.Recipients.To) == 0 {
+			return nil, fmt.Errorf("schedule has no email recipients")
+		}
+		plan, err := planDelivery([]mail.Attachment{file}, settings.Limits.MaxAttachmentSizeMB, artifactBaseName(schedule, run))
+		if err != nil {
+			return nil, err
+		}
+		email := newEmailChannel(settings, schedule.Recipients, plan, int64(len(file.Data)))
+		return &deliveryTarget{index: model.EmailTargetIndex, channel: email}, nil
+	}
+
+	if previous.Index < 0 || previous.Index >= len(schedule.Targets) || schedule.Targets[previous.Index].Type != previous.Type {
+		return nil, fmt.Errorf("%s target is no longer configured on the schedule", previous.Type)
+	}
+	target := schedule.Targets[previous.Index]
+	channel, err := delivery.New(target, delivery.Options{SharePaths: s.sharePaths})
+	if err != nil {
+		return nil, err
+	}
+	return &deliveryTarget{index: previous.Index, channel: channel}, nil
+}
+
+// loadArtifact reads a run's stored artifact from its storage backend
+func (s *Scheduler) loadArtifact(ctx context.Context, schedule *model.Schedule, run *model.Run) (mail.Attachment, error) {
+	if len(run.ArtifactData) == 0 && run.StorageKey == "" {
+		return mail.Attachment{}, fmt.Errorf("run %d has no stored artifact", run.ID)
+	}
+
+	artifactStore, err := s.ArtifactStore(run.OrgID, run.StorageBackend)
+	if err != nil {
+		return mail.Attachment{}, fmt.Errorf("failed to open artifact storage: %w", err)
+	}
+	reader, _, err := artifactStore.Open(ctx, run)
+	if err != nil {
+		return mail.Attachment{}, err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return mail.Attachment{}, fmt.Errorf("failed to read artifact: %w", err)
+	}
+
+	// Runs created before image formats existed have no content type and are PDFs
+	contentType := run.ContentType
+	if contentType == "" {
+		contentType = model.ContentTypePDF
+	}
+	return mail.Attachment{
+		Filename:    artifactBaseName(schedule, run) + model.ArtifactExtension(contentType),
+		ContentType: contentType,
+		Data:        data,
+	}, nil
+}
+
+// artifactBaseName names redelivered files after the schedule and the run's start time, like rendered reports
+func artifactBaseName(schedule *model.Schedule, run *model.Run) string {
+	name := fmt.Sprintf("%s-%s", schedule.Name, run.StartedAt.Format("2006-01-02-150405"))
+	if run.BurstValue != "" {
+		name += "-" + sanitizeFilename(run.BurstValue)
+	}
+	return name
+}
@@ -0,0 +1,117 @@
+package cron
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/store"
+)
+
+// TestRedeliver tests that a run's stored artifact is resent to failed targets and the outcome replaces the failure
+func TestRedeliver(t *testing.T) {
+	dbPath := "test_redeliver.db"
+	defer os.Remove(dbPath)
+
+	st, err := store.NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	if err := st.UpsertSettings(&model.Settings{OrgID: 1}); err != nil {
+		t.Fatalf("Failed to create settings: %v", err)
+	}
+
+	shareDir := t.TempDir()
+	schedule := &model.Schedule{
+		OrgID:        1,
+		Name:         "Finance",
+		DashboardUID: "abc",
+		RangeFrom:    "now-1d",
+		RangeTo:      "now",
+		IntervalType: "daily",
+		Timezone:     "UTC",
+		Targets: model.DeliveryTargets{
+			{Type: model.TargetShare, Share: &model.ShareTarget{Path: shareDir + "/{{schedule.name}}/"}, Retries: model.MaxDeliveryRetries},
+			{Type: model.TargetSlack, Slack: &model.SlackTarget{Token: "xoxb-1", Channel: "C1"}},
+		},
+		EmailSubject: "Report",
+		EmailBody:    "Body",
+		OwnerUserID:  1,
+	}
+	if err := st.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+
+	run := &model.Run{ScheduleID: schedule.ID, OrgID: 1, StartedAt: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), Status: "completed"}
+	if err := st.CreateRun(run); err != nil {
+		t.Fatalf("CreateRun() error = %v", err)
+	}
+	run.ArtifactData = []byte("%PDF-1.4")
+	run.ContentType = model.ContentTypePDF
+	if err := st.UpdateRun(run); err != nil {
+		t.Fatalf("UpdateRun() error = %v", err)
+	}
+
+	// The share wasn't mounted when the run was delivered
+	failed := model.DeliveryResult{Index: 0, Type: model.TargetShare, Target: "share", Error: "share not mounted", Attempts: 1, DeliveredAt: time.Now()}
+	if err := st.SaveDelivery(1, run.ID, &failed); err != nil {
+		t.Fatalf("SaveDelivery() error = %v", err)
+	}
+
+	scheduler := NewScheduler(st, "http://localhost:3000", "", 1)
+	scheduler.SetSharePaths([]string{shareDir})
+
+	stored, err := st.GetRun(1, run.ID)
+	if err != nil {
+		t.Fatalf("GetRun() error = %v", err)
+	}
+	results, err := scheduler.Redeliver(context.Background(), schedule, stored, model.DeliveryResults{failed})
+	if err != nil {
+		t.Fatalf("Redeliver() error = %v", err)
+	}
+	if len(results) != 1 || !results[0].Success || results[0].ID != failed.ID {
+		t.Fatalf("Redeliver() = %+v, want a successful delivery replacing %d", results, failed.ID)
+	}
+
+	data, err := os.ReadFile(filepath.Join(shareDir, "Finance", "Finance-2024-03-01-080000.pdf"))
+	if err != nil || string(data) != "%PDF-1.4" {
+		t.Errorf("redelivered file = %q, %v; want the stored artifact", data, err)
+	}
+
+	deliveries, err := st.ListDeliveries(1, run.ID)
+	if err != nil || len(deliveries) != 1 || !deliveries[0].Success {
+		t.Errorf("ListDeliveries() = %+v, %v; want the successful redelivery", deliveries, err)
+	}
+	updated, err := st.GetRun(1, run.ID)
+	if err != nil {
+		t.Fatalf("GetRun() error = %v", err)
+	}
+	if len(updated.Deliveries) != 1 || !updated.Deliveries[0].Success || string(updated.ArtifactData) != "%PDF-1.4" {
+		t.Errorf("run deliveries = %+v, want the redelivery recorded and the artifact kept", updated.Deliveries)
+	}
+
+	// Manual redelivery is attempted once, without the target's retries and their backoff
+	scheduler.SetSharePaths(nil)
+	results, err = scheduler.Redeliver(context.Background(), schedule, updated, model.DeliveryResults{failed})
+	if err != nil {
+		t.Fatalf("Redeliver() error = %v", err)
+	}
+	if results[0].Success || results[0].Attempts != 1 {
+		t.Errorf("Redeliver() to a failing target = %+v, want one failed attempt", results[0])
+	}
+
+	// Targets removed from the schedule can't be redelivered
+	schedule.Targets = schedule.Targets[1:]
+	results, err = scheduler.Redeliver(context.Background(), schedule, updated, model.DeliveryResults{failed})
+	if err != nil {
+		t.Fatalf("Redeliver() error = %v", err)
+	}
+	if results[0].Success || results[0].Error == "" {
+		t.Errorf("Redeliver() to a removed target = %+v, want an error", results[0])
+	}
+}
@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+)
+
+// retentionCronExpr runs the retention job daily at 03:30, outside typical report hours
+const retentionCronExpr = "0 30 3 * * *"
+
+// applyRetention removes artifacts of runs older than each org's Limits.RetentionDays,
+// then vacuums the database to return the freed space to the filesystem
+func (s *Scheduler) applyRetention() {
+	allSettings, err := s.store.ListSettings()
+	if err != nil {
+		log.Printf("[RETENTION] ERROR: Failed to list settings: %v", err)
+		return
+	}
+
+	var totalRuns int
+	var totalBytes int64
+	for _, settings := range allSettings {
+		days := settings.Limits.RetentionDays
+		if days <= 0 {
+			continue
+		}
+
+		cutoff := time.Now().AddDate(0, 0, -days)
+		result, err := s.store.PruneArtifacts(settings.OrgID, cutoff, settings.Limits.KeepLastRuns)
+		if err != nil {
+			log.Printf("[RETENTION] ERROR: Failed to prune artifacts for org %d: %v", settings.OrgID, err)
+			continue
+		}
+
+		for _, run := range result.External {
+			artifactStore, err := s.ArtifactStore(run.OrgID, run.StorageBackend)
+			if err == nil {
+				err = artifactStore.Delete(s.baseCtx, run)
+			}
+			if err != nil {
+				log.Printf("[RETENTION] WARNING: Failed to delete %s artifact %s of run %d: %v", run.StorageBackend, run.StorageKey, run.ID, err)
+			}
+		}
+		for _, path := range result.LegacyPaths {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("[RETENTION] WARNING: Failed to delete artifact file %s: %v", path, err)
+			}
+		}
+
+		if result.Runs > 0 {
+			log.Printf("[RETENTION] Org %d: removed artifacts of %d run(s) older than %d day(s) (%d bytes)",
+				settings.OrgID, result.Runs, days, result.Bytes)
+		}
+		totalRuns += result.Runs
+		totalBytes += result.Bytes
+	}
+
+	if totalRuns == 0 {
+		log.Printf("[RETENTION] No expired artifacts")
+		return
+	}
+
+	reclaimed, err := s.store.Vacuum()
+	if err != nil {
+		log.Printf("[RETENTION] ERROR: Vacuum failed: %v", err)
+		return
+	}
+	log.Printf("[RETENTION] Removed artifacts of %d run(s) (%d bytes); vacuum reclaimed %d bytes on disk", totalRuns, totalBytes, reclaimed)
+}
@@ -3,48 +3,64 @@ package cron
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/gorhill/cronexpr"
 	"github.com/robfig/cron/v3"
+	"github.com/yourusername/scheduled-reports-app/pkg/delivery"
 	"github.com/yourusername/scheduled-reports-app/pkg/mail"
 	"github.com/yourusername/scheduled-reports-app/pkg/model"
 	"github.com/yourusername/scheduled-reports-app/pkg/render"
+	"github.com/yourusername/scheduled-reports-app/pkg/storage"
 	"github.com/yourusername/scheduled-reports-app/pkg/store"
 )
 
+// defaultMaxConcurrentRenders applies to orgs whose settings do not set Limits.MaxConcurrentRenders
+const defaultMaxConcurrentRenders = 5
+
 // Scheduler handles report scheduling
 type Scheduler struct {
 	store         *store.Store
 	cron          *cron.Cron
 	grafanaURL    string
 	artifactsPath string
-	workerPool    chan struct{}
+	sharePaths    []string                  // Directories share delivery targets may write to
+	renderLimit   *limiter                  // Global cap on concurrent executions across all orgs
+	orgLimits     map[int64]*limiter        // Per-org limits from Limits.MaxConcurrentRenders
+	limitsMutex   sync.Mutex                // Protects orgLimits
 	baseCtx       context.Context           // Context with Grafana config for background jobs
 	renderers     map[int64]render.Backend  // Per-org renderer instances for browser reuse
+	rendererMutex sync.Mutex                // Protects renderers
 	settingsCache map[int64]*model.Settings // Per-org settings cache to reduce DB reads
 	cacheMutex    sync.RWMutex              // Protects settingsCache
 }
 
-// NewScheduler creates a new scheduler instance
+// NewScheduler creates a new scheduler instance.
+// maxConcurrent caps concurrent executions across all orgs; each org is further limited
+// by its Limits.MaxConcurrentRenders setting.
 func NewScheduler(st *store.Store, grafanaURL, artifactsPath string, maxConcurrent int) *Scheduler {
 	return &Scheduler{
 		store:         st,
 		cron:          cron.New(cron.WithSeconds()),
 		grafanaURL:    grafanaURL,
 		artifactsPath: artifactsPath,
-		workerPool:    make(chan struct{}, maxConcurrent),
+		renderLimit:   newLimiter(maxConcurrent),
+		orgLimits:     make(map[int64]*limiter),
 		baseCtx:       context.Background(), // Will be updated when plugin starts
 		renderers:     make(map[int64]render.Backend),
 		settingsCache: make(map[int64]*model.Settings),
 	}
 }
 
+// SetSharePaths sets the directories share delivery targets may write to
+func (s *Scheduler) SetSharePaths(paths []string) {
+	s.sharePaths = paths
+}
+
 // SetContext sets the base context for the scheduler (should be called on plugin initialization)
 func (s *Scheduler) SetContext(ctx context.Context) {
 	s.baseCtx = ctx
@@ -59,6 +75,11 @@ func (s *Scheduler) Start() error {
 		return fmt.Errorf("failed to add cron job: %w", err)
 	}
 
+	// Remove expired artifacts once a day
+	if _, err := s.cron.AddFunc(retentionCronExpr, s.applyRetention); err != nil {
+		return fmt.Errorf("failed to add retention job: %w", err)
+	}
+
 	s.cron.Start()
 	log.Printf("Scheduler started with cron expression '%s' (entry ID: %d)", cronExpr, entryID)
 	log.Printf("Scheduler will check for due schedules every minute")
@@ -72,11 +93,14 @@ func (s *Scheduler) Stop() {
 	s.cron.Stop()
 
 	// Close all browser instances
+	s.rendererMutex.Lock()
 	for orgID, renderer := range s.renderers {
 		if err := renderer.Close(); err != nil {
 			log.Printf("Failed to close renderer for org %d: %v", orgID, err)
 		}
+		delete(s.renderers, orgID)
 	}
+	s.rendererMutex.Unlock()
 
 	log.Println("Scheduler stopped and browsers closed")
 }
@@ -111,6 +135,57 @@ func (s *Scheduler) getCachedSettings(orgID int64) (*model.Settings, error) {
 	return settings, nil
 }
 
+// orgLimiter returns the concurrency limiter for an organization, creating it from settings on first use
+func (s *Scheduler) orgLimiter(orgID int64) *limiter {
+	s.limitsMutex.Lock()
+	l, exists := s.orgLimits[orgID]
+	s.limitsMutex.Unlock()
+	if exists {
+		return l
+	}
+
+	// Read settings without holding the lock; another goroutine may create the limiter meanwhile
+	limit := s.orgConcurrency(orgID)
+
+	s.limitsMutex.Lock()
+	defer s.limitsMutex.Unlock()
+	if l, exists := s.orgLimits[orgID]; exists {
+		return l
+	}
+	l = newLimiter(limit)
+	s.orgLimits[orgID] = l
+	log.Printf("[LIMITS] Org %d limited to %d concurrent render(s)", orgID, limit)
+	return l
+}
+
+// orgConcurrency returns the configured concurrent render limit of an organization
+func (s *Scheduler) orgConcurrency(orgID int64) int {
+	settings, err := s.getCachedSettings(orgID)
+	if err != nil {
+		log.Printf("[LIMITS] WARNING: Failed to load settings for org %d, using default concurrency: %v", orgID, err)
+		return defaultMaxConcurrentRenders
+	}
+	if settings == nil || settings.Limits.MaxConcurrentRenders <= 0 {
+		return defaultMaxConcurrentRenders
+	}
+	return settings.Limits.MaxConcurrentRenders
+}
+
+// refreshOrgLimit resizes an existing org limiter to the current settings.
+// Running executions keep their slots; queued ones start as soon as they fit the new limit.
+func (s *Scheduler) refreshOrgLimit(orgID int64) {
+	s.limitsMutex.Lock()
+	l, exists := s.orgLimits[orgID]
+	s.limitsMutex.Unlock()
+	if !exists {
+		return
+	}
+
+	limit := s.orgConcurrency(orgID)
+	l.resize(limit)
+	log.Printf("[LIMITS] Org %d concurrency limit updated to %d", orgID, limit)
+}
+
 // checkDueSchedules checks for schedules that are due and executes them
 func (s *Scheduler) checkDueSchedules() {
 	log.Printf("[CRON] Checking for due schedules at %s", time.Now().Format(time.RFC3339))
@@ -131,34 +206,44 @@ func (s *Scheduler) checkDueSchedules() {
 		log.Printf("[CRON] Processing schedule ID=%d, Name='%s', NextRunAt=%v",
 			schedule.ID, schedule.Name, schedule.NextRunAt)
 
+		// Relative time ranges are resolved against the time the run was due, not when it starts
+		firedAt := time.Now()
+		if schedule.NextRunAt != nil {
+			firedAt = *schedule.NextRunAt
+		}
+
 		// Update next run time immediately to prevent duplicate execution
 		nextRun := s.calculateNextRun(schedule)
 		schedule.NextRunAt = &nextRun
 		log.Printf("[CRON] Updated schedule ID=%d next run to: %s", schedule.ID, nextRun.Format(time.RFC3339))
 
-		if err := s.store.UpdateSchedule(schedule); err != nil {
+		if err := s.store.SetNextRun(schedule.OrgID, schedule.ID, nextRun); err != nil {
 			log.Printf("[CRON] ERROR: Failed to update schedule %d next run time: %v", schedule.ID, err)
 			continue
 		}
 
 		// Execute in worker pool
 		log.Printf("[CRON] Triggering execution for schedule ID=%d", schedule.ID)
-		go s.executeSchedule(schedule)
+		go s.executeSchedule(schedule, firedAt)
 	}
 }
 
 // ExecuteSchedule executes a schedule immediately (for manual runs)
 func (s *Scheduler) ExecuteSchedule(schedule *model.Schedule) {
-	go s.executeSchedule(schedule)
+	go s.executeSchedule(schedule, time.Now())
 }
 
-// executeSchedule executes a single schedule
-func (s *Scheduler) executeSchedule(schedule *model.Schedule) {
+// executeSchedule executes a single schedule, with its time range resolved at firedAt
+func (s *Scheduler) executeSchedule(schedule *model.Schedule, firedAt time.Time) {
 	log.Printf("[EXECUTE] Starting execution for schedule ID=%d, Name='%s'", schedule.ID, schedule.Name)
 
-	// Acquire worker slot
-	s.workerPool <- struct{}{}
-	defer func() { <-s.workerPool }()
+	// Acquire the org slot before the global one, so runs queued behind their org's limit
+	// never hold global capacity that other orgs could use
+	orgLimit := s.orgLimiter(schedule.OrgID)
+	orgLimit.acquire()
+	defer orgLimit.release()
+	s.renderLimit.acquire()
+	defer s.renderLimit.release()
 
 	log.Printf("[EXECUTE] Acquired worker slot for schedule ID=%d", schedule.ID)
 
@@ -169,6 +254,10 @@ func (s *Scheduler) executeSchedule(schedule *model.Schedule) {
 		StartedAt:  time.Now(),
 		Status:     "running",
 	}
+	report, from, to, resolveErr := resolveSchedule(schedule, firedAt)
+	if resolveErr == nil && !from.IsZero() {
+		run.RangeFrom, run.RangeTo = &from, &to
+	}
 
 	if err := s.store.CreateRun(run); err != nil {
 		log.Printf("[EXECUTE] ERROR: Failed to create run record for schedule ID=%d: %v", schedule.ID, err)
@@ -177,10 +266,39 @@ func (s *Scheduler) executeSchedule(schedule *model.Schedule) {
 
 	log.Printf("[EXECUTE] Created run record ID=%d for schedule ID=%d", run.ID, schedule.ID)
 
-	// Execute with retries
+	var err error
+	switch {
+	case resolveErr != nil:
+		err = fmt.Errorf("failed to resolve time range: %w", resolveErr)
+		s.finishRun(schedule, run, err)
+	case report.Burst != nil:
+		err = s.executeBurst(report, run)
+		s.finishRun(report, run, err)
+	default:
+		err = s.runReport(report, run)
+	}
+
+	// Schedules whose owner lost access to a dashboard stay disabled until someone fixes them
+	disable := errors.Is(err, ErrNoDashboardAccess)
+	if disable {
+		log.Printf("[EXECUTE] Disabling schedule ID=%d: %v", schedule.ID, err)
+	}
+
+	// Only the run bookkeeping is written, as the schedule may have been edited while it ran
+	if err := s.store.RecordScheduleRun(schedule.OrgID, schedule.ID, run.StartedAt, disable); err != nil {
+		log.Printf("Failed to update schedule last run time: %v", err)
+	}
+}
+
+// runReport renders and delivers one report for a run (with retries), records the outcome and returns its error
+func (s *Scheduler) runReport(schedule *model.Schedule, run *model.Run) error {
 	err := s.executeWithRetry(schedule, run, 3)
+	s.finishRun(schedule, run, err)
+	return err
+}
 
-	// Update run record
+// finishRun records the final status of a run
+func (s *Scheduler) finishRun(schedule *model.Schedule, run *model.Run, err error) {
 	now := time.Now()
 	run.FinishedAt = &now
 
@@ -188,19 +306,13 @@ func (s *Scheduler) executeSchedule(schedule *model.Schedule) {
 		run.Status = "failed"
 		run.ErrorText = err.Error()
 		log.Printf("Schedule %d execution failed: %v", schedule.ID, err)
-	} else {
+	} else if run.Status != "skipped" {
 		run.Status = "completed"
 	}
 
 	if err := s.store.UpdateRun(run); err != nil {
 		log.Printf("Failed to update run record: %v", err)
 	}
-
-	// Update schedule last run time
-	schedule.LastRunAt = &run.StartedAt
-	if err := s.store.UpdateSchedule(schedule); err != nil {
-		log.Printf("Failed to update schedule last run time: %v", err)
-	}
 }
 
 // executeWithRetry executes a schedule with retry logic
@@ -220,6 +332,11 @@ func (s *Scheduler) executeWithRetry(schedule *model.Schedule, run *model.Run, m
 			return nil
 		}
 
+		// Retrying doesn't bring back access to a dashboard
+		if errors.Is(err, ErrNoDashboardAccess) {
+			return err
+		}
+
 		lastErr = err
 		log.Printf("Schedule %d execution attempt %d failed: %v", schedule.ID, attempt+1, err)
 	}
@@ -250,33 +367,100 @@ func (s *Scheduler) executeScheduleOnce(schedule *model.Schedule, run *model.Run
 		log.Printf("DEBUG: Using default Grafana URL: %s", grafanaURL)
 	}
 
+	// Reports only include dashboards their owner can still view
+	if err := s.checkDashboardAccess(ctx, schedule, settings, grafanaURL); err != nil {
+		return err
+	}
+
+	// Exception reports are only rendered and sent when their condition is met
+	if schedule.Condition != nil {
+		met, reason, err := s.checkCondition(ctx, schedule, settings, grafanaURL)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate condition: %w", err)
+		}
+		if !met {
+			log.Printf("Skipping schedule %d: %s", schedule.ID, reason)
+			run.Status = "skipped"
+			run.ErrorText = reason
+			return nil
+		}
+		log.Printf("Condition met for schedule %d: %s", schedule.ID, reason)
+	}
+
 	log.Printf("DEBUG: Rendering with grafanaURL=%s using Chromium backend (managed service account)", grafanaURL)
 
 	// Get or create renderer for this org (reuse renderer instance)
-	renderer, exists := s.renderers[schedule.OrgID]
-	if !exists {
-		// Create new renderer
-		var err error
-		renderer, err = render.NewBackend(grafanaURL, settings.RendererConfig)
+	renderer, err := s.orgRenderer(schedule.OrgID, grafanaURL, settings.RendererConfig)
+	if err != nil {
+		return err
+	}
+
+	// Interpolation variables shared by the report template and the email
+	vars := templateVars(schedule, run)
+
+	// Load the report template (page layout and branding) if one is assigned
+	renderOpts := render.Options{Vars: vars}
+	if schedule.TemplateID != nil {
+		template, err := s.store.GetTemplate(schedule.OrgID, *schedule.TemplateID)
 		if err != nil {
-			return fmt.Errorf("failed to create renderer: %w", err)
+			return fmt.Errorf("failed to load template %d: %w", *schedule.TemplateID, err)
 		}
-		s.renderers[schedule.OrgID] = renderer
-		log.Printf("Created new Chromium renderer for org %d with URL %s", schedule.OrgID, grafanaURL)
+		renderOpts.Template = &template.Config
+		log.Printf("Applying template '%s' (ID=%d) to schedule %d", template.Name, template.ID, schedule.ID)
 	}
 
-	// Render dashboard (token will be retrieved from context inside renderer)
-	renderedData, err := renderer.RenderDashboard(ctx, schedule)
-	if err != nil {
-		return fmt.Errorf("failed to render dashboard: %w", err)
+	baseName := fmt.Sprintf("%s-%s", schedule.Name, time.Now().Format("2006-01-02-150405"))
+	if run.BurstValue != "" {
+		baseName += "-" + sanitizeFilename(run.BurstValue)
 	}
+	var attachments []mail.Attachment
 
-	run.RenderedPages = 1
+	// Render dashboard in the schedule's format (token will be retrieved from context inside renderer)
+	if !schedule.DataOnly {
+		report, err := renderArtifact(ctx, renderer, schedule, renderOpts)
+		if err != nil {
+			return fmt.Errorf("failed to render dashboard: %w", err)
+		}
+		run.RenderedPages = report.pages
+		attachments = append(attachments, mail.Attachment{
+			Filename:    baseName + model.ArtifactExtension(report.contentType),
+			ContentType: report.contentType,
+			Data:        report.data,
+		})
+		log.Printf("DEBUG: Rendered %s report (%d bytes, %d page(s))", report.contentType, len(report.data), report.pages)
+	}
 
-	// Generate PDF (always PDF format)
-	reportData := renderedData
-	filename := fmt.Sprintf("%s-%s.pdf", schedule.Name, time.Now().Format("2006-01-02-150405"))
-	log.Printf("DEBUG: Using PDF directly from Chromium backend (%d bytes)", len(reportData))
+	// Attach panel query results as CSV or XLSX
+	if schedule.DataFormat != "" {
+		files, err := s.exportData(ctx, schedule, settings, grafanaURL, baseName)
+		if err != nil {
+			return fmt.Errorf("failed to export panel data: %w", err)
+		}
+		attachments = append(attachments, files...)
+	}
+
+	if len(attachments) == 0 {
+		return fmt.Errorf("schedule produced no report: data_only is set without a data_format")
+	}
+
+	// Fit the files into the org's attachment size limit
+	maxAttachmentMB := settings.Limits.MaxAttachmentSizeMB
+	plan, err := planDelivery(attachments, maxAttachmentMB, baseName)
+	if err != nil {
+		return err
+	}
+
+	// The rendered report is the run artifact; data-only runs store their data files (zipped when several),
+	// as do runs whose email links to the artifact instead of attaching it
+	artifactFile := attachments[0]
+	if schedule.DataOnly || plan.mode == model.DeliveryLink {
+		artifactFile, err = bundleAttachments(attachments, baseName)
+		if err != nil {
+			return err
+		}
+	}
+	reportData := artifactFile.Data
+	run.ContentType = artifactFile.ContentType
 
 	run.Bytes = int64(len(reportData))
 
@@ -284,9 +468,8 @@ func (s *Scheduler) executeScheduleOnce(schedule *model.Schedule, run *model.Run
 	checksum := fmt.Sprintf("%x", sha256.Sum256(reportData))
 	run.Checksum = checksum
 
-	// Save artifact directly to database as BLOB
-	run.ArtifactData = reportData
-	log.Printf("Report saved to database (%d bytes, checksum=%s)", len(reportData), checksum)
+	// Save artifact to the org's storage backend, keeping it in the database if that fails
+	s.saveArtifact(ctx, settings, run, reportData)
 
 	// Update run record with artifact data BEFORE sending email
 	// This makes the download button available immediately in the UI
@@ -297,53 +480,189 @@ func (s *Scheduler) executeScheduleOnce(schedule *model.Schedule, run *model.Run
 		log.Printf("Run record updated with artifact data (download now available in UI)")
 	}
 
-	// Send email (optional - report is already saved to database)
-	if settings.SMTPConfig == nil {
-		log.Printf("SMTP not configured for org %d - report saved to database (available for download)", schedule.OrgID)
-		run.EmailSent = false
-		run.EmailError = "SMTP not configured"
-		// Update run with email status
-		if err := s.store.UpdateRun(run); err != nil {
-			log.Printf("WARNING: Failed to update run record with email status: %v", err)
+	// Interpolate template variables
+	report := &delivery.Report{
+		Schedule:          schedule,
+		Run:               run,
+		Subject:           mail.InterpolateTemplate(schedule.EmailSubject, vars),
+		Body:              mail.InterpolateTemplate(schedule.EmailBody, vars),
+		File:              artifactFile,
+		ArtifactURL:       artifactURL(grafanaURL, run.ID),
+		SignedArtifactURL: s.SignedArtifactURL(grafanaURL, run.OrgID, run.ID),
+	}
+
+	// Email the recipients (when there are any, or when email is the only channel) and deliver to the other targets
+	var targets []deliveryTarget
+	run.Deliveries = nil
+	if len(schedule.Recipients.To) > 0 || len(schedule.Targets) == 0 {
+		email := newEmailChannel(settings, schedule.Recipients, plan, attachmentsSize(attachments))
+		if email.mailer != nil {
+			// Oversized reports are linked rather than attached
+			run.DeliveryMode = plan.mode
+			switch plan.mode {
+			case model.DeliveryCompressed:
+				log.Printf("Report for schedule %d exceeds %d MB, sending compressed archive (%d bytes)", schedule.ID, maxAttachmentMB, len(plan.attachments[0].Data))
+			case model.DeliveryLink:
+				log.Printf("Report for schedule %d exceeds %d MB even when compressed, sending download link", schedule.ID, maxAttachmentMB)
+			}
 		}
-		return nil // Report generation succeeded, email delivery is optional
+		targets = append(targets, deliveryTarget{index: model.EmailTargetIndex, channel: email})
+	}
+	for i, target := range schedule.Targets {
+		channel, err := delivery.New(target, delivery.Options{SharePaths: s.sharePaths})
+		if err != nil {
+			run.Deliveries = append(run.Deliveries, deliveryResult(i, target.Type, "", 0, err))
+			continue
+		}
+		targets = append(targets, deliveryTarget{index: i, channel: channel, retries: target.Retries})
+	}
+
+	// Delivery failures are recorded on the run but don't fail it - the report is available for download
+	s.deliver(ctx, run, report, targets)
+	s.recordDeliveries(run, run.Deliveries)
+	if err := s.store.UpdateRun(run); err != nil {
+		log.Printf("WARNING: Failed to update run record with delivery status: %v", err)
 	}
+	return nil
+}
 
-	smtpConfig := *settings.SMTPConfig
-	mailer := mail.NewMailer(smtpConfig)
+// deliveryTarget is a channel and how often a failed delivery to it is retried
+type deliveryTarget struct {
+	index   int // Position in Schedule.Targets, or model.EmailTargetIndex
+	channel delivery.Channel
+	retries int
+}
 
-	// Interpolate template variables
+// deliveryRetryDelay is the base delay between delivery attempts; retry n waits n² times as long
+var deliveryRetryDelay = 5 * time.Second
+
+// deliver sends the report to every target and records each outcome on the run
+func (s *Scheduler) deliver(ctx context.Context, run *model.Run, report *delivery.Report, targets []deliveryTarget) {
+	for _, target := range targets {
+		channel := target.channel
+		log.Printf("Delivering report for schedule %d via %s to %s...", run.ScheduleID, channel.Type(), channel.Target())
+		attempts, err := deliverWithRetry(ctx, channel, report, target.retries)
+		if err != nil {
+			log.Printf("Failed to deliver report for schedule %d via %s after %d attempt(s): %v - report saved (available for download)", run.ScheduleID, channel.Type(), attempts, err)
+		} else {
+			log.Printf("Delivered report for schedule %d via %s to %s", run.ScheduleID, channel.Type(), channel.Target())
+		}
+		run.Deliveries = append(run.Deliveries, deliveryResult(target.index, channel.Type(), channel.Target(), attempts, err))
+
+		// The email outcome is also kept in the original fields
+		if channel.Type() == model.TargetEmail {
+			run.EmailSent = err == nil
+			run.EmailError = ""
+			if err != nil {
+				run.EmailError = err.Error()
+			}
+		}
+	}
+}
+
+// deliverWithRetry delivers the report, retrying failures with backoff.
+// It returns the number of attempts made and the last error.
+func deliverWithRetry(ctx context.Context, channel delivery.Channel, report *delivery.Report, retries int) (int, error) {
+	var err error
+	attempt := 0
+	for attempt < retries+1 {
+		if attempt > 0 {
+			backoff := time.Duration(attempt*attempt) * deliveryRetryDelay
+			log.Printf("Retrying %s delivery for schedule %d (attempt %d/%d) after %v: %v", channel.Type(), report.Run.ScheduleID, attempt+1, retries+1, backoff, err)
+			select {
+			case <-ctx.Done():
+				return attempt, err
+			case <-time.After(backoff):
+			}
+		}
+		attempt++
+		if err = channel.Deliver(ctx, report); err == nil {
+			break
+		}
+	}
+	return attempt, err
+}
+
+// recordDeliveries saves delivery outcomes to the deliveries table, assigning their IDs
+func (s *Scheduler) recordDeliveries(run *model.Run, results model.DeliveryResults) {
+	for i := range results {
+		if err := s.store.SaveDelivery(run.OrgID, run.ID, &results[i]); err != nil {
+			log.Printf("WARNING: Failed to record %s delivery for run %d: %v", results[i].Type, run.ID, err)
+		}
+	}
+}
+
+// deliveryResult records the outcome of one delivery
+func deliveryResult(index int, targetType, target string, attempts int, err error) model.DeliveryResult {
+	result := model.DeliveryResult{
+		Index:       index,
+		Type:        targetType,
+		Target:      target,
+		Success:     err == nil,
+		Attempts:    attempts,
+		DeliveredAt: time.Now(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// ArtifactStore returns the artifact store for a backend of an org.
+// An empty backend selects the database, where runs created before storage backends existed keep their artifacts.
+func (s *Scheduler) ArtifactStore(orgID int64, backend string) (storage.ArtifactStore, error) {
+	var config model.StorageConfig
+	if backend == model.StorageS3 {
+		settings, err := s.getCachedSettings(orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get settings: %w", err)
+		}
+		if settings != nil {
+			config = settings.Storage
+		}
+	}
+	return storage.New(backend, config, s.artifactsPath)
+}
+
+// saveArtifact stores the report in the backend selected in the org's settings.
+// The database is used as a fallback so the report stays downloadable when the backend is unavailable.
+func (s *Scheduler) saveArtifact(ctx context.Context, settings *model.Settings, run *model.Run, data []byte) {
+	backend := settings.Storage.Backend
+	artifactStore, err := s.ArtifactStore(run.OrgID, backend)
+	if err == nil {
+		err = artifactStore.Save(ctx, run, data)
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to store artifact in %s storage for run %d, keeping it in the database: %v", backend, run.ID, err)
+		storage.Database{}.Save(ctx, run, data)
+	}
+	log.Printf("Report saved to %s storage (%d bytes, checksum=%s)", run.StorageBackend, len(data), run.Checksum)
+}
+
+// templateVars returns the {{placeholder}} values available to email and report templates.
+// The time range is the one resolved for the run, in the schedule's timezone.
+func templateVars(schedule *model.Schedule, run *model.Run) map[string]string {
 	vars := map[string]string{
 		"schedule.name":   schedule.Name,
 		"dashboard.title": schedule.DashboardTitle,
-		"timerange":       fmt.Sprintf("%s to %s", schedule.RangeFrom, schedule.RangeTo),
+		"timerange":       run.TimeRangeText(schedule.Timezone),
+		"timerange.from":  "",
+		"timerange.to":    "",
 		"run.started_at":  run.StartedAt.Format(time.RFC1123),
+		"burst.value":     run.BurstValue,
 	}
-
-	subject := mail.InterpolateTemplate(schedule.EmailSubject, vars)
-	body := mail.InterpolateTemplate(schedule.EmailBody, vars)
-
-	// Try to send email, but don't fail the entire run if it fails
-	log.Printf("Attempting to send email for schedule %d to %d recipient(s)...", schedule.ID, len(schedule.Recipients.To))
-	if err := mailer.SendReport(schedule.Recipients, subject, body, reportData, filename); err != nil {
-		log.Printf("Failed to send email for schedule %d: %v - report saved to %s (available for download)", schedule.ID, err, artifactPath)
-		run.EmailSent = false
-		run.EmailError = err.Error()
-		// Update run with email failure status
-		if err := s.store.UpdateRun(run); err != nil {
-			log.Printf("WARNING: Failed to update run record with email error: %v", err)
-		}
-		return nil // Report generation succeeded, email delivery failed but report is available
+	if run.RangeFrom == nil {
+		vars["timerange"] = fmt.Sprintf("%s to %s", schedule.RangeFrom, schedule.RangeTo)
+		return vars
 	}
 
-	log.Printf("Email sent successfully for schedule %d to %d recipient(s)", schedule.ID, len(schedule.Recipients.To))
-	run.EmailSent = true
-	run.EmailError = "" // Clear any previous error
-	// Update run with email success status
-	if err := s.store.UpdateRun(run); err != nil {
-		log.Printf("WARNING: Failed to update run record with email success: %v", err)
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
 	}
-	return nil
+	vars["timerange.from"] = run.RangeFrom.In(loc).Format(time.RFC3339)
+	vars["timerange.to"] = run.RangeTo.In(loc).Format(time.RFC3339)
+	return vars
 }
 
 // CalculateNextRun calculates the next run time for a schedule (exported for use in handlers)
@@ -397,12 +716,36 @@ func (s *Scheduler) calculateNextRun(schedule *model.Schedule) time.Time {
 }
 
 // ClearRendererCache closes and removes renderer instances for the given org ID
-// This forces new renderers to be created with updated settings on next render
+// This forces new renderers to be created with updated settings on next render,
+// and applies the org's updated concurrency limit
 func (s *Scheduler) ClearRendererCache(orgID int64) error {
-	s.cacheMutex.Lock()
-	defer s.cacheMutex.Unlock()
+	s.clearCaches(orgID)
+	s.refreshOrgLimit(orgID)
+	return nil
+}
 
+// orgRenderer returns the renderer of an org, creating it on first use. Schedules of different
+// orgs run concurrently, so the renderers are only accessed under rendererMutex.
+func (s *Scheduler) orgRenderer(orgID int64, grafanaURL string, config model.RendererConfig) (render.Backend, error) {
+	s.rendererMutex.Lock()
+	defer s.rendererMutex.Unlock()
+
+	if renderer, exists := s.renderers[orgID]; exists {
+		return renderer, nil
+	}
+	renderer, err := render.NewBackend(grafanaURL, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create renderer: %w", err)
+	}
+	s.renderers[orgID] = renderer
+	log.Printf("Created new Chromium renderer for org %d with URL %s", orgID, grafanaURL)
+	return renderer, nil
+}
+
+// clearCaches drops the cached renderer and settings of an org
+func (s *Scheduler) clearCaches(orgID int64) {
 	// Close existing renderer if it exists
+	s.rendererMutex.Lock()
 	if renderer, exists := s.renderers[orgID]; exists {
 		if err := renderer.Close(); err != nil {
 			log.Printf("Warning: Failed to close renderer for org %d: %v", orgID, err)
@@ -410,10 +753,11 @@ func (s *Scheduler) ClearRendererCache(orgID int64) error {
 		delete(s.renderers, orgID)
 		log.Printf("Cleared renderer cache for org %d", orgID)
 	}
+	s.rendererMutex.Unlock()
 
 	// Also clear settings cache to force reload
+	s.cacheMutex.Lock()
 	delete(s.settingsCache, orgID)
+	s.cacheMutex.Unlock()
 	log.Printf("Cleared settings cache for org %d", orgID)
-
-	return nil
 }
@@ -286,3 +286,48 @@ func TestSettingsCachePerOrg(t *testing.T) {
 		t.Errorf("Expected smtp2.example.com, got %s", cachedSettings2.SMTPConfig.Host)
 	}
 }
+
+// TestRenderersConcurrentOrgs tests that schedules of two orgs can get and clear their renderers
+// concurrently; run with -race to detect unguarded access to the renderer cache
+func TestRenderersConcurrentOrgs(t *testing.T) {
+	dbPath := "test_renderers_concurrent.db"
+	defer os.Remove(dbPath)
+
+	st, err := store.NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	scheduler := NewScheduler(st, "http://localhost:3000", "/tmp/artifacts", 10)
+
+	// Renderers only launch a browser when rendering, so creating them is cheap
+	var wg sync.WaitGroup
+	for _, orgID := range []int64{1, 2} {
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(orgID int64, i int) {
+				defer wg.Done()
+				if _, err := scheduler.orgRenderer(orgID, "http://localhost:3000", model.RendererConfig{}); err != nil {
+					t.Errorf("orgRenderer(%d) error = %v", orgID, err)
+				}
+				if i%5 == 0 {
+					scheduler.ClearRendererCache(orgID)
+				}
+			}(orgID, i)
+		}
+	}
+	wg.Wait()
+
+	// Without clearing, each org keeps reusing its own renderer
+	first, _ := scheduler.orgRenderer(1, "http://localhost:3000", model.RendererConfig{})
+	again, _ := scheduler.orgRenderer(1, "http://localhost:3000", model.RendererConfig{})
+	other, _ := scheduler.orgRenderer(2, "http://localhost:3000", model.RendererConfig{})
+	if first != again {
+		t.Error("Expected org 1 to reuse its renderer")
+	}
+	if first == other {
+		t.Error("Expected different renderers for different orgs")
+	}
+	scheduler.Stop()
+}
@@ -0,0 +1,62 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// resolveSchedule returns a copy of the schedule with its time range, and those of its dashboards,
+// resolved at the given time in the schedule's timezone and written as epoch milliseconds, so every
+// part of the report covers the same window however late it runs. from and to are the schedule's
+// resolved range; they are zero when the schedule leaves the range to the dashboard.
+func resolveSchedule(schedule *model.Schedule, at time.Time) (resolved *model.Schedule, from, to time.Time, err error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	copied := *schedule
+	copied.RangeFrom, copied.RangeTo, from, to, err = resolveRange(schedule.RangeFrom, schedule.RangeTo, at, loc)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	if len(schedule.Dashboards) > 0 {
+		copied.Dashboards = make(model.DashboardRefs, len(schedule.Dashboards))
+		for i, ref := range schedule.Dashboards {
+			ref.RangeFrom, ref.RangeTo, _, _, err = resolveRange(ref.RangeFrom, ref.RangeTo, at, loc)
+			if err != nil {
+				return nil, time.Time{}, time.Time{}, fmt.Errorf("dashboard %s: %w", ref.UID, err)
+			}
+			copied.Dashboards[i] = ref
+		}
+	}
+	return &copied, from, to, nil
+}
+
+// resolveRange resolves a time range to epoch milliseconds. An empty range is returned unchanged.
+func resolveRange(fromExpr, toExpr string, at time.Time, loc *time.Location) (string, string, time.Time, time.Time, error) {
+	if fromExpr == "" && toExpr == "" {
+		return "", "", time.Time{}, time.Time{}, nil
+	}
+	from, to, err := grafana.ResolveTimeRange(fromExpr, toExpr, at, loc)
+	if err != nil {
+		return "", "", time.Time{}, time.Time{}, err
+	}
+	if to.Before(from) {
+		return "", "", time.Time{}, time.Time{}, fmt.Errorf("time range ends before it starts (%s to %s)", fromExpr, toExpr)
+	}
+	return strconv.FormatInt(from.UnixMilli(), 10), strconv.FormatInt(to.UnixMilli(), 10), from, to, nil
+}
+
+// ValidateTimeRanges checks that the time ranges of a schedule and of its dashboards can be resolved
+func ValidateTimeRanges(schedule *model.Schedule) error {
+	if _, _, _, err := resolveSchedule(schedule, time.Now()); err != nil {
+		return fmt.Errorf("invalid time range: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package cron
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+func TestResolveSchedule(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// Due on Monday at midnight Berlin time, even if the run starts later
+	firedAt := time.Date(2024, 3, 11, 0, 0, 0, 0, berlin)
+	ms := func(t time.Time) string { return strconv.FormatInt(t.UnixMilli(), 10) }
+
+	schedule := &model.Schedule{
+		Timezone:  "Europe/Berlin",
+		RangeFrom: "now-1w/w",
+		RangeTo:   "now-1w/w",
+		Dashboards: model.DashboardRefs{
+			{UID: "a"},
+			{UID: "b", RangeFrom: "now-1M/M", RangeTo: "now-1M/M"},
+		},
+	}
+	resolved, from, to, err := resolveSchedule(schedule, firedAt)
+	if err != nil {
+		t.Fatalf("resolveSchedule() error = %v", err)
+	}
+
+	wantFrom := time.Date(2024, 3, 4, 0, 0, 0, 0, berlin)
+	wantTo := time.Date(2024, 3, 10, 23, 59, 59, int(999*time.Millisecond), berlin)
+	if !from.Equal(wantFrom) || !to.Equal(wantTo) {
+		t.Errorf("range = %v to %v, want the previous week %v to %v", from, to, wantFrom, wantTo)
+	}
+	if resolved.RangeFrom != ms(wantFrom) || resolved.RangeTo != ms(wantTo) {
+		t.Errorf("resolved range = %s to %s, want epoch milliseconds", resolved.RangeFrom, resolved.RangeTo)
+	}
+	if resolved.Dashboards[0].RangeFrom != "" {
+		t.Errorf("dashboard without its own range got %q", resolved.Dashboards[0].RangeFrom)
+	}
+	if got, want := resolved.Dashboards[1].RangeFrom, ms(time.Date(2024, 2, 1, 0, 0, 0, 0, berlin)); got != want {
+		t.Errorf("dashboard range start = %s, want %s", got, want)
+	}
+
+	// The schedule itself keeps its relative range
+	if schedule.RangeFrom != "now-1w/w" || schedule.Dashboards[1].RangeFrom != "now-1M/M" {
+		t.Errorf("schedule was modified: %+v", schedule)
+	}
+
+	// No range leaves it to the dashboard
+	resolved, from, _, err = resolveSchedule(&model.Schedule{}, firedAt)
+	if err != nil || !from.IsZero() || resolved.RangeFrom != "" {
+		t.Errorf("empty range: resolved %q, from %v, err %v", resolved.RangeFrom, from, err)
+	}
+}
+
+func TestValidateTimeRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *model.Schedule
+		wantErr  bool
+	}{
+		{"relative", &model.Schedule{RangeFrom: "now-7d", RangeTo: "now"}, false},
+		{"previous quarter", &model.Schedule{RangeFrom: "now-1Q/Q", RangeTo: "now-1Q/Q"}, false},
+		{"absolute", &model.Schedule{RangeFrom: "2024-01-01", RangeTo: "2024-01-31 23:59"}, false},
+		{"empty", &model.Schedule{}, false},
+		{"invalid", &model.Schedule{RangeFrom: "last week", RangeTo: "now"}, true},
+		{"missing end", &model.Schedule{RangeFrom: "now-7d"}, true},
+		{"reversed", &model.Schedule{RangeFrom: "now", RangeTo: "now-7d"}, true},
+		{"invalid dashboard range", &model.Schedule{
+			RangeFrom: "now-7d", RangeTo: "now",
+			Dashboards: model.DashboardRefs{{UID: "a", RangeFrom: "now-1x", RangeTo: "now"}},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTimeRanges(tt.schedule); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTimeRanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateVarsTimeRange(t *testing.T) {
+	from := time.Date(2024, 3, 3, 23, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 10, 22, 59, 59, 0, time.UTC)
+	schedule := &model.Schedule{Timezone: "Europe/Berlin", RangeFrom: "1709506800000", RangeTo: "1710111599999"}
+	run := &model.Run{StartedAt: time.Now(), RangeFrom: &from, RangeTo: &to}
+
+	vars := templateVars(schedule, run)
+	if got, want := vars["timerange"], "2024-03-04 00:00 to 2024-03-10 23:59 CET"; got != want {
+		t.Errorf("timerange = %q, want %q", got, want)
+	}
+	if got, want := vars["timerange.from"], "2024-03-04T00:00:00+01:00"; got != want {
+		t.Errorf("timerange.from = %q, want %q", got, want)
+	}
+	if got, want := vars["timerange.to"], "2024-03-10T23:59:59+01:00"; got != want {
+		t.Errorf("timerange.to = %q, want %q", got, want)
+	}
+
+	// Runs without a resolved range show the schedule's range as entered
+	vars = templateVars(&model.Schedule{RangeFrom: "now-7d", RangeTo: "now"}, &model.Run{})
+	if vars["timerange"] != "now-7d to now" || vars["timerange.from"] != "" {
+		t.Errorf("unresolved vars = %q / %q", vars["timerange"], vars["timerange.from"])
+	}
+}
@@ -0,0 +1,87 @@
+// Package delivery sends rendered reports to destinations other than email, such as Slack, Teams, webhooks and file servers.
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/mail"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// Report is a rendered report ready to be delivered
+type Report struct {
+	Schedule          *model.Schedule
+	Run               *model.Run
+	Subject           string          // Interpolated email subject
+	Body              string          // Interpolated email body (HTML)
+	File              mail.Attachment // The run artifact
+	ArtifactURL       string          // Grafana URL downloading the artifact, for those managing the schedule and its recipients
+	SignedArtifactURL string          // ArtifactURL signed for every member of the org until it expires, for chat and webhook readers
+}
+
+// Channel delivers reports to one destination
+type Channel interface {
+	// Type returns the target type recorded on the run (email, slack, ...)
+	Type() string
+	// Target describes the destination in run history
+	Target() string
+	// Deliver sends the report
+	Deliver(ctx context.Context, report *Report) error
+}
+
+// Options configures channels beyond their target settings
+type Options struct {
+	SharePaths []string // Directories share targets may write to
+}
+
+// New creates the channel for a schedule delivery target
+func New(target model.DeliveryTarget, opts Options) (Channel, error) {
+	switch target.Type {
+	case model.TargetSlack:
+		if target.Slack == nil {
+			return nil, fmt.Errorf("slack target is not configured")
+		}
+		return NewSlack(*target.Slack), nil
+	case model.TargetTeams:
+		if target.Teams == nil {
+			return nil, fmt.Errorf("teams target is not configured")
+		}
+		return NewTeams(*target.Teams), nil
+	case model.TargetWebhook:
+		if target.Webhook == nil {
+			return nil, fmt.Errorf("webhook target is not configured")
+		}
+		return NewWebhook(*target.Webhook), nil
+	case model.TargetSFTP:
+		if target.SFTP == nil {
+			return nil, fmt.Errorf("sftp target is not configured")
+		}
+		return NewSFTP(*target.SFTP), nil
+	case model.TargetShare:
+		if target.Share == nil {
+			return nil, fmt.Errorf("share target is not configured")
+		}
+		return NewShare(*target.Share, opts.SharePaths), nil
+	default:
+		return nil, fmt.Errorf("unknown delivery target '%s'", target.Type)
+	}
+}
+
+var (
+	lineBreakTags = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</h[1-6]>`)
+	htmlTags      = regexp.MustCompile(`<[^>]*>`)
+	blankLines    = regexp.MustCompile(`\n{3,}`)
+)
+
+// plainText converts an HTML email body to plain text for chat messages
+func plainText(body string) string {
+	text := lineBreakTags.ReplaceAllString(body, "\n")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
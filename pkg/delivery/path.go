@@ -0,0 +1,52 @@
+package delivery
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/mail"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// unsafePathChars matches characters replaced in names used as path segments
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// filePath resolves a file target's path template for a report.
+// Templates ending in "/" name a directory the report file is written to under its own name.
+func filePath(template string, report *Report) string {
+	if strings.TrimSpace(template) == "" {
+		template = model.DefaultDeliveryPath
+	}
+
+	vars := map[string]string{
+		"file.name": safeName(report.File.Filename),
+		"file.ext":  path.Ext(report.File.Filename),
+	}
+	if report.Schedule != nil {
+		vars["schedule.name"] = safeName(report.Schedule.Name)
+		vars["schedule.id"] = fmt.Sprint(report.Schedule.ID)
+	}
+	if report.Run != nil {
+		started := report.Run.StartedAt.UTC()
+		vars["run.id"] = fmt.Sprint(report.Run.ID)
+		vars["run.timestamp"] = started.Format("20060102-150405")
+		vars["run.date"] = started.Format("2006-01-02")
+	}
+
+	resolved := mail.InterpolateTemplate(template, vars)
+	if strings.HasSuffix(resolved, "/") {
+		resolved += vars["file.name"]
+	}
+	return path.Clean(resolved)
+}
+
+// safeName makes a name usable as a single path segment
+func safeName(name string) string {
+	name = strings.Trim(unsafePathChars.ReplaceAllString(name, "_"), "._")
+	if name == "" {
+		return "report"
+	}
+	return name
+}
@@ -0,0 +1,145 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// SFTP uploads report files to an SFTP server
+type SFTP struct {
+	config model.SFTPTarget
+}
+
+// NewSFTP creates an SFTP channel
+func NewSFTP(config model.SFTPTarget) *SFTP {
+	return &SFTP{config: config}
+}
+
+// Type returns the target type
+func (s *SFTP) Type() string {
+	return model.TargetSFTP
+}
+
+// Target returns user@host:port
+func (s *SFTP) Target() string {
+	return fmt.Sprintf("%s@%s", s.config.Username, s.address())
+}
+
+// address returns the server's host:port
+func (s *SFTP) address() string {
+	port := s.config.Port
+	if port == 0 {
+		port = 22
+	}
+	return net.JoinHostPort(s.config.Host, strconv.Itoa(port))
+}
+
+// Deliver uploads the report file, creating missing directories
+func (s *SFTP) Deliver(ctx context.Context, report *Report) error {
+	clientConfig, err := s.clientConfig()
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: clientConfig.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address())
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", s.address(), err)
+	}
+	// Abort the transfer when the run is cancelled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, s.address(), clientConfig)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("ssh handshake with %s failed: %w", s.address(), err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	defer sshClient.Close()
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		return fmt.Errorf("failed to start sftp session: %w", err)
+	}
+	defer client.Close()
+
+	remotePath := filePath(s.config.Path, report)
+	if dir := path.Dir(remotePath); dir != "." && dir != "/" {
+		if err := client.MkdirAll(dir); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+
+	// Upload under a temporary name so consumers polling the directory never pick up a partial file
+	tmp := remotePath + ".part"
+	file, err := client.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	if _, err := file.Write(report.File.Data); err != nil {
+		file.Close()
+		client.Remove(tmp)
+		return fmt.Errorf("failed to upload %s: %w", remotePath, err)
+	}
+	if err := file.Close(); err != nil {
+		client.Remove(tmp)
+		return fmt.Errorf("failed to upload %s: %w", remotePath, err)
+	}
+	// Replace an existing file of the same name; PosixRename needs a server extension, so fall back to remove+rename
+	if err := client.PosixRename(tmp, remotePath); err != nil {
+		client.Remove(remotePath)
+		if err := client.Rename(tmp, remotePath); err != nil {
+			client.Remove(tmp)
+			return fmt.Errorf("failed to rename %s: %w", tmp, err)
+		}
+	}
+	return nil
+}
+
+// clientConfig builds the SSH configuration from the target's credentials
+func (s *SFTP) clientConfig() (*ssh.ClientConfig, error) {
+	var auth []ssh.AuthMethod
+	if s.config.PrivateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if s.config.Passphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(s.config.PrivateKey), []byte(s.config.Passphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(s.config.PrivateKey))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if s.config.Password != "" {
+		auth = append(auth, ssh.Password(s.config.Password))
+	}
+
+	// Reports are never sent to a server that isn't verified
+	if strings.TrimSpace(s.config.HostKey) == "" {
+		return nil, fmt.Errorf("no host key configured to verify the server with")
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(s.config.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid host key: %w", err)
+	}
+
+	return &ssh.ClientConfig{
+		User:            s.config.Username,
+		Auth:            auth,
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Timeout:         30 * time.Second,
+	}, nil
+}
@@ -0,0 +1,206 @@
+package delivery
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// sftpServer is an in-process SSH server with the sftp subsystem serving the local filesystem
+type sftpServer struct {
+	addr    string
+	hostKey ssh.PublicKey
+}
+
+// startSFTPServer accepts password "secret" and the given client key for user "reports"
+func startSFTPServer(t *testing.T, clientKey ssh.PublicKey) *sftpServer {
+	t.Helper()
+	_, hostPriv, _ := ed25519.GenerateKey(rand.Reader)
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "reports" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, os.ErrPermission
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "reports" && clientKey != nil && string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, os.ErrPermission
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSFTP(conn, config)
+		}
+	}()
+	return &sftpServer{addr: listener.Addr().String(), hostKey: hostSigner.PublicKey()}
+}
+
+// serveSFTP handles one SSH connection, serving sftp subsystem requests
+func serveSFTP(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+				if ok {
+					server, err := sftp.NewServer(channel)
+					if err == nil {
+						server.Serve()
+					}
+					channel.Close()
+				}
+			}
+		}()
+	}
+}
+
+func (s *sftpServer) target(root string) model.SFTPTarget {
+	host, port, _ := net.SplitHostPort(s.addr)
+	portNumber, _ := strconv.Atoi(port)
+	return model.SFTPTarget{
+		Host:     host,
+		Port:     portNumber,
+		Username: "reports",
+		HostKey:  string(ssh.MarshalAuthorizedKey(s.hostKey)),
+		Path:     root + "/{{schedule.name}}/{{run.timestamp}}{{file.ext}}",
+	}
+}
+
+func TestSFTPDeliver(t *testing.T) {
+	_, clientPriv, _ := ed25519.GenerateKey(rand.Reader)
+	clientSigner, _ := ssh.NewSignerFromKey(clientPriv)
+	block, err := ssh.MarshalPrivateKey(clientPriv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := string(pem.EncodeToMemory(block))
+
+	server := startSFTPServer(t, clientSigner.PublicKey())
+	wrongKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	wrongHostKey, _ := ssh.NewPublicKey(wrongKey)
+
+	tests := []struct {
+		name    string
+		config  func(model.SFTPTarget) model.SFTPTarget
+		wantErr string
+	}{
+		{name: "password", config: func(c model.SFTPTarget) model.SFTPTarget { c.Password = "secret"; return c }},
+		{name: "private key", config: func(c model.SFTPTarget) model.SFTPTarget { c.PrivateKey = privateKey; return c }},
+		{name: "wrong password", config: func(c model.SFTPTarget) model.SFTPTarget { c.Password = "guess"; return c }, wantErr: "handshake"},
+		{name: "host key mismatch", config: func(c model.SFTPTarget) model.SFTPTarget {
+			c.Password, c.HostKey = "secret", string(ssh.MarshalAuthorizedKey(wrongHostKey))
+			return c
+		}, wantErr: "handshake"},
+		{name: "no host key", config: func(c model.SFTPTarget) model.SFTPTarget { c.Password, c.HostKey = "secret", ""; return c }, wantErr: "no host key"},
+		{name: "invalid private key", config: func(c model.SFTPTarget) model.SFTPTarget { c.PrivateKey = "not a key"; return c }, wantErr: "invalid private key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			channel := NewSFTP(tt.config(server.target(root)))
+			err := channel.Deliver(context.Background(), testReport())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Deliver() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Deliver() error = %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(root, "Weekly", "20240301-080000.pdf"))
+			if err != nil || string(data) != "%PDF" {
+				t.Fatalf("uploaded file = %q, %v; want the report", data, err)
+			}
+			if _, err := os.Stat(filepath.Join(root, "Weekly", "20240301-080000.pdf.part")); !os.IsNotExist(err) {
+				t.Error("temporary upload file was left behind")
+			}
+		})
+	}
+}
+
+func TestFilePath(t *testing.T) {
+	report := testReport()
+	report.Schedule.Name = "Sales / EMEA: weekly"
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: "", want: "Sales_EMEA_weekly/20240301-080000.pdf"},
+		{template: "/exports/{{run.date}}/", want: "/exports/2024-03-01/report.pdf"},
+		{template: "archive/{{schedule.id}}-{{run.id}}{{file.ext}}", want: "archive/7-42.pdf"},
+	}
+	for _, tt := range tests {
+		if got := filePath(tt.template, report); got != tt.want {
+			t.Errorf("filePath(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestShareDeliver(t *testing.T) {
+	root := t.TempDir()
+	share := NewShare(model.ShareTarget{Path: root + "/finance/{{schedule.name}}/"}, []string{root})
+	if err := share.Deliver(context.Background(), testReport()); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "finance", "Weekly", "report.pdf")); err != nil || string(data) != "%PDF" {
+		t.Errorf("written file = %q, %v; want the report", data, err)
+	}
+
+	// Paths outside the allowed directories are rejected
+	outside := NewShare(model.ShareTarget{Path: filepath.Dir(root) + "/elsewhere/"}, []string{root})
+	if err := outside.Deliver(context.Background(), testReport()); err == nil || !strings.Contains(err.Error(), "GF_PLUGIN_SHARE_PATHS") {
+		t.Errorf("Deliver() outside the share paths error = %v", err)
+	}
+	if err := NewShare(model.ShareTarget{Path: root + "/x/"}, nil).Deliver(context.Background(), testReport()); err == nil {
+		t.Error("Deliver() without allowed share paths succeeded")
+	}
+}
@@ -0,0 +1,69 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// Share copies report files to a network share (SMB/NFS) mounted on the Grafana server
+type Share struct {
+	config model.ShareTarget
+	roots  []string
+}
+
+// NewShare creates a share channel writing below one of the allowed root directories
+func NewShare(config model.ShareTarget, roots []string) *Share {
+	return &Share{config: config, roots: roots}
+}
+
+// Type returns the target type
+func (s *Share) Type() string {
+	return model.TargetShare
+}
+
+// Target returns the path template
+func (s *Share) Target() string {
+	return s.config.Path
+}
+
+// Deliver writes the report file, creating missing directories
+func (s *Share) Deliver(ctx context.Context, report *Report) error {
+	target := filepath.FromSlash(filePath(s.config.Path, report))
+	if !s.allowed(target) {
+		return fmt.Errorf("%s is outside the share paths allowed by GF_PLUGIN_SHARE_PATHS", target)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	// Write to a temporary file first so consumers never pick up a partial file
+	tmp := target + ".part"
+	if err := os.WriteFile(tmp, report.File.Data, 0644); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+// allowed reports whether path is inside one of the allowed roots
+func (s *Share) allowed(path string) bool {
+	for _, root := range s.roots {
+		if root == "" {
+			continue
+		}
+		rel, err := filepath.Rel(filepath.Clean(root), path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel) {
+			return true
+		}
+	}
+	return false
+}
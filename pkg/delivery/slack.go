@@ -0,0 +1,138 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// slackAPIURL is the base URL of the Slack Web API
+const slackAPIURL = "https://slack.com/api"
+
+// Slack uploads reports to a Slack channel with a summary message, using the external upload flow
+// (files.getUploadURLExternal, upload, files.completeUploadExternal)
+type Slack struct {
+	config     model.SlackTarget
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewSlack creates a Slack channel for a bot token and channel ID
+func NewSlack(config model.SlackTarget) *Slack {
+	return &Slack{
+		config:     config,
+		apiURL:     slackAPIURL,
+		httpClient: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// Type returns the target type
+func (s *Slack) Type() string {
+	return model.TargetSlack
+}
+
+// Target returns the Slack channel ID
+func (s *Slack) Target() string {
+	return s.config.Channel
+}
+
+// slackResponse holds the fields shared by Slack Web API responses
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Deliver uploads the report file to the channel, with the subject and body as the message
+func (s *Slack) Deliver(ctx context.Context, report *Report) error {
+	// Reserve an upload URL for the file
+	var upload struct {
+		slackResponse
+		UploadURL string `json:"upload_url"`
+		FileID    string `json:"file_id"`
+	}
+	form := url.Values{
+		"filename": {report.File.Filename},
+		"length":   {strconv.Itoa(len(report.File.Data))},
+	}
+	if err := s.call(ctx, "files.getUploadURLExternal", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()), &upload); err != nil {
+		return err
+	}
+
+	// Upload the file contents
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, upload.UploadURL, bytes.NewReader(report.File.Data))
+	if err != nil {
+		return fmt.Errorf("failed to create slack upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload file to slack: %w", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to upload file to slack: %s", resp.Status)
+	}
+
+	// Share the file in the channel with the summary message
+	message := "*" + report.Subject + "*"
+	if text := plainText(report.Body); text != "" {
+		message += "\n" + text
+	}
+	complete, err := json.Marshal(map[string]interface{}{
+		"files":           []map[string]string{{"id": upload.FileID, "title": report.File.Filename}},
+		"channel_id":      s.config.Channel,
+		"initial_comment": message,
+	})
+	if err != nil {
+		return err
+	}
+	var result slackResponse
+	return s.call(ctx, "files.completeUploadExternal", "application/json; charset=utf-8", bytes.NewReader(complete), &result)
+}
+
+// call invokes a Slack Web API method and decodes the response into out, which must embed slackResponse
+func (s *Slack) call(ctx context.Context, method, contentType string, body io.Reader, out interface{ failure() error }) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiURL+"/"+method, body)
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack %s failed: %s", method, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("slack %s returned an invalid response: %w", method, err)
+	}
+	if err := out.failure(); err != nil {
+		return fmt.Errorf("slack %s failed: %w", method, err)
+	}
+	return nil
+}
+
+// failure returns the API error reported in the response, if any
+func (r *slackResponse) failure() error {
+	if r.OK {
+		return nil
+	}
+	if r.Error == "" {
+		return fmt.Errorf("unknown error")
+	}
+	return fmt.Errorf("%s", r.Error)
+}
@@ -0,0 +1,90 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/mail"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+func TestSlackDeliver(t *testing.T) {
+	var uploaded []byte
+	var complete map[string]interface{}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/upload" && r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": "invalid_auth"})
+			return
+		}
+		switch r.URL.Path {
+		case "/api/files.getUploadURLExternal":
+			r.ParseForm()
+			if r.Form.Get("filename") != "report.pdf" || r.Form.Get("length") != "4" {
+				t.Errorf("getUploadURLExternal form = %v", r.Form)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "upload_url": server.URL + "/upload", "file_id": "F123"})
+		case "/upload":
+			uploaded, _ = io.ReadAll(r.Body)
+		case "/api/files.completeUploadExternal":
+			json.NewDecoder(r.Body).Decode(&complete)
+			json.NewEncoder(w).Encode(map[string]interface{}{"ok": true})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	slack := NewSlack(model.SlackTarget{Token: "xoxb-test", Channel: "C123"})
+	slack.apiURL = server.URL + "/api"
+
+	report := &Report{
+		Subject: "Weekly sales",
+		Body:    "<p>Hello team,</p><p>Sales &amp; revenue attached.</p>",
+		File:    mail.Attachment{Filename: "report.pdf", ContentType: model.ContentTypePDF, Data: []byte("%PDF")},
+	}
+	if err := slack.Deliver(context.Background(), report); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	if string(uploaded) != "%PDF" {
+		t.Errorf("uploaded = %q, want file contents", uploaded)
+	}
+	if complete["channel_id"] != "C123" {
+		t.Errorf("completeUploadExternal channel_id = %v, want C123", complete["channel_id"])
+	}
+	want := "*Weekly sales*\nHello team,\nSales & revenue attached."
+	if complete["initial_comment"] != want {
+		t.Errorf("initial_comment = %q, want %q", complete["initial_comment"], want)
+	}
+
+	// API errors are reported
+	slack.config.Token = "wrong"
+	err := slack.Deliver(context.Background(), report)
+	if err == nil || !strings.Contains(err.Error(), "invalid_auth") {
+		t.Errorf("Deliver() with bad token error = %v, want invalid_auth", err)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		html string
+		want string
+	}{
+		{html: "Plain", want: "Plain"},
+		{html: "Line one<br>Line two<br/>", want: "Line one\nLine two"},
+		{html: "<h1>Title</h1><p>A &lt; B</p>", want: "Title\nA < B"},
+	}
+
+	for _, tt := range tests {
+		if got := plainText(tt.html); got != tt.want {
+			t.Errorf("plainText(%q) = %q, want %q", tt.html, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// Teams posts an adaptive card linking to the report to a Microsoft Teams incoming webhook
+type Teams struct {
+	config     model.TeamsTarget
+	httpClient *http.Client
+}
+
+// NewTeams creates a Teams channel for an incoming webhook URL
+func NewTeams(config model.TeamsTarget) *Teams {
+	return &Teams{
+		config:     config,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Type returns the target type
+func (t *Teams) Type() string {
+	return model.TargetTeams
+}
+
+// Target returns the webhook host; the full URL contains a secret
+func (t *Teams) Target() string {
+	return redactURL(t.config.WebhookURL)
+}
+
+// Deliver posts the card; Teams can't receive files through webhooks, so the card links to the artifact
+func (t *Teams) Deliver(ctx context.Context, report *Report) error {
+	payload, err := json.Marshal(teamsMessage(report))
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, t.httpClient, t.config.WebhookURL, payload, nil)
+}
+
+// teamsMessage builds the webhook message carrying the adaptive card
+func teamsMessage(report *Report) map[string]interface{} {
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": report.Subject, "weight": "Bolder", "size": "Medium", "wrap": true},
+	}
+	if text := plainText(report.Body); text != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": text, "wrap": true})
+	}
+	if report.Schedule != nil {
+		timeRange := report.Schedule.RangeFrom + " to " + report.Schedule.RangeTo
+		if report.Run != nil && report.Run.RangeFrom != nil {
+			timeRange = report.Run.TimeRangeText(report.Schedule.Timezone)
+		}
+		body = append(body, map[string]interface{}{
+			"type": "FactSet",
+			"facts": []map[string]string{
+				{"title": "Dashboard", "value": report.Schedule.DashboardTitle},
+				{"title": "Time range", "value": timeRange},
+			},
+		})
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+		"actions": []map[string]string{
+			{"type": "Action.OpenUrl", "title": "Download report", "url": report.SignedArtifactURL},
+		},
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+}
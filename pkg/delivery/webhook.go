@@ -0,0 +1,173 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// Webhook request headers
+const (
+	// WebhookTimestampHeader carries the Unix time the payload was signed at
+	WebhookTimestampHeader = "X-Report-Timestamp"
+	// WebhookSignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+	WebhookSignatureHeader = "X-Report-Signature"
+)
+
+// Webhook POSTs a JSON description of the report to an HTTP endpoint, optionally HMAC-signed
+type Webhook struct {
+	config     model.WebhookTarget
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewWebhook creates a webhook channel
+func NewWebhook(config model.WebhookTarget) *Webhook {
+	return &Webhook{
+		config:     config,
+		httpClient: &http.Client{Timeout: time.Minute},
+		now:        time.Now,
+	}
+}
+
+// Type returns the target type
+func (w *Webhook) Type() string {
+	return model.TargetWebhook
+}
+
+// Target returns the webhook host
+func (w *Webhook) Target() string {
+	return redactURL(w.config.URL)
+}
+
+// WebhookPayload is the JSON body posted to webhooks
+type WebhookPayload struct {
+	Event    string          `json:"event"`
+	Schedule WebhookSchedule `json:"schedule"`
+	Run      WebhookRun      `json:"run"`
+	File     WebhookFile     `json:"file"`
+}
+
+// WebhookSchedule identifies the schedule that produced the report
+type WebhookSchedule struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	DashboardUID   string `json:"dashboard_uid"`
+	DashboardTitle string `json:"dashboard_title,omitempty"`
+}
+
+// WebhookRun identifies the run that produced the report
+type WebhookRun struct {
+	ID        int64      `json:"id"`
+	StartedAt time.Time  `json:"started_at"`
+	RangeFrom *time.Time `json:"range_from,omitempty"` // Absolute time range the report covers
+	RangeTo   *time.Time `json:"range_to,omitempty"`
+}
+
+// WebhookFile describes the report file
+type WebhookFile struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	Checksum    string `json:"checksum"`          // Hex SHA-256 of the file
+	URL         string `json:"url"`               // Signed Grafana URL downloading the file
+	Content     string `json:"content,omitempty"` // Base64 file contents when the target includes content
+}
+
+// Deliver posts the payload
+func (w *Webhook) Deliver(ctx context.Context, report *Report) error {
+	payload, err := json.Marshal(w.payload(report))
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+	if w.config.Secret != "" {
+		timestamp := strconv.FormatInt(w.now().Unix(), 10)
+		headers[WebhookTimestampHeader] = timestamp
+		headers[WebhookSignatureHeader] = SignWebhook(w.config.Secret, timestamp, payload)
+	}
+	return postJSON(ctx, w.httpClient, w.config.URL, payload, headers)
+}
+
+// payload builds the webhook body for a report
+func (w *Webhook) payload(report *Report) WebhookPayload {
+	checksum := sha256.Sum256(report.File.Data)
+	payload := WebhookPayload{
+		Event: "report.completed",
+		File: WebhookFile{
+			Name:        report.File.Filename,
+			ContentType: report.File.ContentType,
+			Size:        len(report.File.Data),
+			Checksum:    hex.EncodeToString(checksum[:]),
+			URL:         report.SignedArtifactURL,
+		},
+	}
+	if report.Schedule != nil {
+		payload.Schedule = WebhookSchedule{
+			ID:             report.Schedule.ID,
+			Name:           report.Schedule.Name,
+			DashboardUID:   report.Schedule.DashboardUID,
+			DashboardTitle: report.Schedule.DashboardTitle,
+		}
+	}
+	if report.Run != nil {
+		payload.Run = WebhookRun{ID: report.Run.ID, StartedAt: report.Run.StartedAt, RangeFrom: report.Run.RangeFrom, RangeTo: report.Run.RangeTo}
+	}
+	if w.config.IncludeContent {
+		payload.File.Content = base64.StdEncoding.EncodeToString(report.File.Data)
+	}
+	return payload
+}
+
+// SignWebhook returns the signature header value for a payload, for receivers to compare against
+func SignWebhook(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// postJSON posts a JSON body and fails on non-2xx responses
+func postJSON(ctx context.Context, client *http.Client, target string, payload []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", redactURL(target), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", redactURL(target), resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// redactURL returns the scheme and host of a URL, dropping paths and queries that may contain secrets
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "invalid URL"
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,140 @@
+package delivery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/mail"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// testReport returns a report with a small PDF file
+func testReport() *Report {
+	from := time.Date(2024, 2, 23, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC)
+	return &Report{
+		Schedule:          &model.Schedule{ID: 7, Name: "Weekly", DashboardUID: "abc", DashboardTitle: "Sales", RangeFrom: "now-7d/d", RangeTo: "now-1d/d", Timezone: "UTC"},
+		Run:               &model.Run{ID: 42, StartedAt: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), RangeFrom: &from, RangeTo: &to},
+		Subject:           "Weekly sales",
+		Body:              "<p>See attached.</p>",
+		File:              mail.Attachment{Filename: "report.pdf", ContentType: model.ContentTypePDF, Data: []byte("%PDF")},
+		ArtifactURL:       "https://grafana.example.com/api/plugins/app/resources/api/runs/42/artifact",
+		SignedArtifactURL: "https://grafana.example.com/api/plugins/app/resources/api/runs/42/artifact?expires=1709884800&signature=abc",
+	}
+}
+
+func TestWebhookDeliver(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(model.WebhookTarget{URL: server.URL + "/hooks/reports", Secret: "s3cret", IncludeContent: true})
+	webhook.now = func() time.Time { return time.Unix(1700000000, 0) }
+	if err := webhook.Deliver(context.Background(), testReport()); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if payload.Schedule.ID != 7 || payload.Run.ID != 42 || payload.File.Size != 4 {
+		t.Errorf("payload = %+v, want schedule 7, run 42, 4 bytes", payload)
+	}
+	if payload.Run.RangeFrom == nil || !payload.Run.RangeFrom.Equal(time.Date(2024, 2, 23, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("run range_from = %v, want the resolved range start", payload.Run.RangeFrom)
+	}
+	if payload.File.URL != testReport().SignedArtifactURL {
+		t.Errorf("file url = %q, want the signed artifact URL", payload.File.URL)
+	}
+	if payload.File.Checksum != "315d429b7714cedb6ad04ac31240145257692630457f3c88253c5beceac76027" {
+		t.Errorf("checksum = %q, want hex SHA-256 of the file", payload.File.Checksum)
+	}
+	if content, _ := base64.StdEncoding.DecodeString(payload.File.Content); string(content) != "%PDF" {
+		t.Errorf("content = %q, want base64 of the file", payload.File.Content)
+	}
+
+	if header.Get(WebhookTimestampHeader) != "1700000000" {
+		t.Errorf("timestamp header = %q", header.Get(WebhookTimestampHeader))
+	}
+	if want := SignWebhook("s3cret", "1700000000", body); header.Get(WebhookSignatureHeader) != want {
+		t.Errorf("signature = %q, want %q", header.Get(WebhookSignatureHeader), want)
+	}
+	if webhook.Target() != server.URL {
+		t.Errorf("Target() = %q, want the URL without path", webhook.Target())
+	}
+}
+
+func TestWebhookWithoutSecretOrContent(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+	}))
+	defer server.Close()
+
+	if err := NewWebhook(model.WebhookTarget{URL: server.URL}).Deliver(context.Background(), testReport()); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if header.Get(WebhookSignatureHeader) != "" {
+		t.Error("unsigned webhook sent a signature")
+	}
+	if strings.Contains(string(body), `"content"`) {
+		t.Error("payload includes content although IncludeContent is off")
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// Reference value computed with: printf '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	got := SignWebhook("secret", "1700000000", []byte("{}"))
+	if want := "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"; got != want {
+		t.Errorf("SignWebhook() = %q, want %q", got, want)
+	}
+}
+
+func TestTeamsDeliver(t *testing.T) {
+	var message map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&message)
+		w.Write([]byte("1"))
+	}))
+	defer server.Close()
+
+	teams := NewTeams(model.TeamsTarget{WebhookURL: server.URL + "/webhookb2/secret"})
+	if err := teams.Deliver(context.Background(), testReport()); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	encoded, _ := json.Marshal(message)
+	for _, want := range []string{`"type":"message"`, "application/vnd.microsoft.card.adaptive", `"Action.OpenUrl"`, "runs/42/artifact?expires=1709884800", "signature=abc", "Weekly sales", "2024-02-23 00:00 to 2024-02-29 23:59 UTC"} {
+		if !strings.Contains(string(encoded), want) {
+			t.Errorf("message %s missing %q", encoded, want)
+		}
+	}
+	if strings.Contains(teams.Target(), "secret") {
+		t.Errorf("Target() = %q exposes the webhook path", teams.Target())
+	}
+
+	// Non-2xx responses are errors
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Bad payload", http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	err := NewTeams(model.TeamsTarget{WebhookURL: failing.URL}).Deliver(context.Background(), testReport())
+	if err == nil || !strings.Contains(err.Error(), "Bad payload") {
+		t.Errorf("Deliver() error = %v, want the response error", err)
+	}
+}
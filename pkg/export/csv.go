@@ -0,0 +1,34 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+)
+
+// CSV writes the table as CSV with a header row
+func CSV(table Table) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(table.Columns); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) {
+				record[i] = formatCell(row[i])
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,120 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+)
+
+func testFrame() grafana.Frame {
+	var frame grafana.Frame
+	frame.Schema.Fields = []grafana.Field{
+		{Name: "Time", Type: "time"},
+		{Name: "Value", Type: "number", Labels: map[string]string{"instance": "a", "env": "prod"}},
+	}
+	frame.Data.Values = [][]interface{}{
+		{float64(1700000000000), float64(1700000060000)},
+		{float64(1234567), nil},
+	}
+	return frame
+}
+
+func TestTableFromFrame(t *testing.T) {
+	table := TableFromFrame("Requests", testFrame(), time.UTC)
+
+	wantColumns := []string{"Time", `Value{env="prod", instance="a"}`}
+	if strings.Join(table.Columns, "|") != strings.Join(wantColumns, "|") {
+		t.Errorf("Columns = %v, want %v", table.Columns, wantColumns)
+	}
+	if len(table.Rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(table.Rows))
+	}
+	if ts, ok := table.Rows[0][0].(time.Time); !ok || !ts.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("time cell = %v, want 2023-11-14T22:13:20Z", table.Rows[0][0])
+	}
+	if table.Rows[1][1] != nil {
+		t.Errorf("null cell = %v, want nil", table.Rows[1][1])
+	}
+}
+
+func TestCSV(t *testing.T) {
+	data, err := CSV(TableFromFrame("Requests", testFrame(), time.UTC))
+	if err != nil {
+		t.Fatalf("CSV() error = %v", err)
+	}
+
+	want := "Time,\"Value{env=\"\"prod\"\", instance=\"\"a\"\"}\"\n" +
+		"2023-11-14T22:13:20Z,1234567\n" +
+		"2023-11-14T22:14:20Z,\n"
+	if string(data) != want {
+		t.Errorf("CSV() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	tables := []Table{
+		TableFromFrame("Requests <5xx>", testFrame(), time.UTC),
+		{Name: "Requests <5xx>", Columns: []string{"Name"}, Rows: [][]interface{}{{"a & b"}, {true}}},
+	}
+
+	data, err := XLSX(tables)
+	if err != nil {
+		t.Fatalf("XLSX() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("XLSX output is not a ZIP package: %v", err)
+	}
+
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(content)
+
+		// Every part must be well-formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not valid XML: %v", f.Name, err)
+			}
+		}
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "xl/workbook.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Requests &lt;5xx&gt; (2)"`) {
+		t.Errorf("duplicate sheet names should be made unique: %s", parts["xl/workbook.xml"])
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet1.xml"], `<c r="B2"><v>1234567</v></c>`) {
+		t.Errorf("numbers should be stored as numeric cells: %s", parts["xl/worksheets/sheet1.xml"])
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet2.xml"], "a &amp; b") {
+		t.Errorf("text should be escaped: %s", parts["xl/worksheets/sheet2.xml"])
+	}
+}
+
+func TestColumnLetter(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for index, want := range tests {
+		if got := columnLetter(index); got != want {
+			t.Errorf("columnLetter(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
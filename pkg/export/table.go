@@ -0,0 +1,108 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
+)
+
+// Table is a tabular query result ready to be written as CSV or an XLSX sheet.
+// Cells hold float64, bool, string, time.Time or nil values.
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// TableFromFrame converts a Grafana data frame into a table.
+// Time fields (epoch milliseconds on the wire) are converted to time.Time in loc.
+func TableFromFrame(name string, frame grafana.Frame, loc *time.Location) Table {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	fields := frame.Schema.Fields
+	table := Table{Name: name, Columns: make([]string, len(fields))}
+	for i, field := range fields {
+		table.Columns[i] = columnName(field)
+	}
+
+	rowCount := 0
+	for _, values := range frame.Data.Values {
+		if len(values) > rowCount {
+			rowCount = len(values)
+		}
+	}
+
+	table.Rows = make([][]interface{}, rowCount)
+	for r := 0; r < rowCount; r++ {
+		row := make([]interface{}, len(fields))
+		for c, field := range fields {
+			if c >= len(frame.Data.Values) || r >= len(frame.Data.Values[c]) {
+				continue
+			}
+			row[c] = cellValue(field, frame.Data.Values[c][r], loc)
+		}
+		table.Rows[r] = row
+	}
+
+	return table
+}
+
+// columnName returns the display name of a field, including its labels as name{key="value"}
+func columnName(field grafana.Field) string {
+	if field.Config.DisplayName != "" {
+		return field.Config.DisplayName
+	}
+	if field.Config.DisplayNameFromDS != "" {
+		return field.Config.DisplayNameFromDS
+	}
+	if len(field.Labels) == 0 {
+		return field.Name
+	}
+
+	keys := make([]string, 0, len(field.Labels))
+	for k := range field.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%q", k, field.Labels[k])
+	}
+	return field.Name + "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// cellValue converts a JSON wire value to a table cell
+func cellValue(field grafana.Field, value interface{}, loc *time.Location) interface{} {
+	if value == nil {
+		return nil
+	}
+	if field.Type == "time" {
+		if ms, ok := value.(float64); ok {
+			return time.UnixMilli(int64(ms)).In(loc)
+		}
+	}
+	return value
+}
+
+// formatCell renders a cell as text
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,139 @@
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBody limits how much of an error response body is included in error messages
+const maxErrorBody = 512
+
+// StatusError is returned for API responses with a status outside 2xx
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is an API response with status 404 Not Found
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// Client is a minimal Grafana HTTP API client authenticated with the plugin's service account token
+type Client struct {
+	baseURL    string
+	token      string
+	orgID      int64
+	httpClient *http.Client
+}
+
+// NewClient creates a Grafana API client for the given organization
+func NewClient(baseURL, token string, orgID int64, skipTLSVerify bool) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		orgID:   orgID,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: skipTLSVerify},
+			},
+		},
+	}
+}
+
+// GetDashboard fetches a dashboard model by UID
+func (c *Client) GetDashboard(ctx context.Context, uid string) (*Dashboard, error) {
+	var resp struct {
+		Dashboard Dashboard `json:"dashboard"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/dashboards/uid/"+url.PathEscape(uid), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get dashboard %s: %w", uid, err)
+	}
+	return &resp.Dashboard, nil
+}
+
+// GetDataSourceUID resolves a data source name (used by older dashboards) to its UID
+func (c *Client) GetDataSourceUID(ctx context.Context, name string) (string, error) {
+	var resp struct {
+		UID string `json:"uid"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/datasources/name/"+url.PathEscape(name), nil, &resp); err != nil {
+		return "", fmt.Errorf("failed to look up data source %q: %w", name, err)
+	}
+	return resp.UID, nil
+}
+
+// QueryData runs queries through Grafana's data source query API (/api/ds/query)
+func (c *Client) QueryData(ctx context.Context, req QueryRequest) (*QueryResponse, error) {
+	var resp QueryResponse
+	if err := c.do(ctx, http.MethodPost, "/api/ds/query", req, &resp); err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return &resp, nil
+}
+
+// do sends an authenticated API request and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.orgID > 0 {
+		req.Header.Set("X-Grafana-Org-Id", strconv.FormatInt(c.orgID, 10))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if len(data) > maxErrorBody {
+			data = data[:maxErrorBody]
+		}
+		return &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
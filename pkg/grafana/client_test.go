@@ -0,0 +1,122 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testDashboard = `{
+	"dashboard": {
+		"uid": "abc",
+		"title": "Service",
+		"panels": [
+			{"id": 1, "type": "timeseries", "title": "Requests",
+			 "datasource": {"uid": "$ds", "type": "prometheus"},
+			 "targets": [
+				{"refId": "A", "expr": "rate(http_requests_total{env=\"$env\"}[5m])"},
+				{"refId": "B", "expr": "hidden", "hide": true}
+			 ]},
+			{"id": 2, "type": "row", "title": "Details", "collapsed": true, "panels": [
+				{"id": 3, "type": "table", "title": "Legacy", "datasource": "MySQL",
+				 "targets": [{"refId": "A", "rawSql": "SELECT 1"}]}
+			]},
+			{"id": 4, "type": "text", "title": "Notes"}
+		]
+	}
+}`
+
+func TestClientPanelQueries(t *testing.T) {
+	var gotQuery QueryRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("missing service account token, got %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Grafana-Org-Id") != "2" {
+			t.Errorf("X-Grafana-Org-Id = %q, want 2", r.Header.Get("X-Grafana-Org-Id"))
+		}
+
+		switch r.URL.Path {
+		case "/api/dashboards/uid/abc":
+			w.Write([]byte(testDashboard))
+		case "/api/datasources/name/MySQL":
+			w.Write([]byte(`{"uid": "mysql-uid"}`))
+		case "/api/ds/query":
+			if err := json.NewDecoder(r.Body).Decode(&gotQuery); err != nil {
+				t.Fatalf("invalid query body: %v", err)
+			}
+			w.Write([]byte(`{"results": {"A": {"frames": [{"schema": {"fields": [{"name": "Time", "type": "time"}, {"name": "Value", "type": "number"}]}, "data": {"values": [[1700000000000], [42]]}}]}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL+"/", "token", 2, false)
+
+	dashboard, err := client.GetDashboard(ctx, "abc")
+	if err != nil {
+		t.Fatalf("GetDashboard() error = %v", err)
+	}
+	panels := dashboard.AllPanels()
+	if len(panels) != 3 {
+		t.Fatalf("AllPanels() returned %d panels, want 3 (rows excluded, collapsed panels included)", len(panels))
+	}
+
+	vars := Variables{"ds": {"prom-uid"}, "env": {"prod"}}
+
+	queries, err := client.PanelQueries(ctx, panels[0], vars)
+	if err != nil {
+		t.Fatalf("PanelQueries() error = %v", err)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("PanelQueries() returned %d queries, want 1 (hidden targets skipped)", len(queries))
+	}
+	if queries[0]["expr"] != `rate(http_requests_total{env="prod"}[5m])` {
+		t.Errorf("expr = %v", queries[0]["expr"])
+	}
+	if ds := queries[0]["datasource"].(map[string]interface{}); ds["uid"] != "prom-uid" {
+		t.Errorf("datasource = %v, want prom-uid", ds)
+	}
+
+	legacy, ok := dashboard.FindPanel(3)
+	if !ok {
+		t.Fatal("FindPanel(3) did not find the collapsed panel")
+	}
+	queries, err = client.PanelQueries(ctx, legacy, vars)
+	if err != nil {
+		t.Fatalf("PanelQueries() error = %v", err)
+	}
+	if ds := queries[0]["datasource"].(map[string]interface{}); ds["uid"] != "mysql-uid" {
+		t.Errorf("legacy datasource = %v, want mysql-uid", ds)
+	}
+
+	resp, err := client.QueryData(ctx, QueryRequest{From: "now-1h", To: "now", Queries: queries})
+	if err != nil {
+		t.Fatalf("QueryData() error = %v", err)
+	}
+	if gotQuery.From != "now-1h" || len(gotQuery.Queries) != 1 {
+		t.Errorf("unexpected query request: %+v", gotQuery)
+	}
+	if frames := resp.Results["A"].Frames; len(frames) != 1 || len(frames[0].Schema.Fields) != 2 {
+		t.Errorf("unexpected frames: %+v", resp.Results["A"].Frames)
+	}
+}
+
+func TestClientErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Dashboard not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "token", 1, false).GetDashboard(context.Background(), "missing")
+	if err == nil {
+		t.Fatal("GetDashboard() should fail on HTTP 404")
+	}
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+}
@@ -0,0 +1,79 @@
+package grafana
+
+import (
+	"encoding/json"
+)
+
+// Dashboard is the subset of a Grafana dashboard model needed to re-run panel queries
+type Dashboard struct {
+	UID    string  `json:"uid"`
+	Title  string  `json:"title"`
+	Panels []Panel `json:"panels"`
+}
+
+// Panel is a dashboard panel with its queries
+type Panel struct {
+	ID         int64                    `json:"id"`
+	Title      string                   `json:"title"`
+	Type       string                   `json:"type"`
+	Datasource *DataSourceRef           `json:"datasource,omitempty"`
+	Targets    []map[string]interface{} `json:"targets,omitempty"`
+	MaxDataPts int64                    `json:"maxDataPoints,omitempty"`
+	Interval   string                   `json:"interval,omitempty"`
+	Panels     []Panel                  `json:"panels,omitempty"` // Panels of a collapsed row
+}
+
+// DataSourceRef identifies a data source. Older dashboards reference data sources by name,
+// which is decoded into Name.
+type DataSourceRef struct {
+	UID  string `json:"uid,omitempty"`
+	Type string `json:"type,omitempty"`
+	Name string `json:"-"`
+}
+
+// UnmarshalJSON accepts both the object form {"uid": ..., "type": ...} and the legacy name string
+func (d *DataSourceRef) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*d = DataSourceRef{Name: name}
+		return nil
+	}
+
+	type ref DataSourceRef
+	var r ref
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	*d = DataSourceRef(r)
+	return nil
+}
+
+// MixedDataSource is the UID of the pseudo data source used by panels whose queries target different data sources
+const MixedDataSource = "-- Mixed --"
+
+// AllPanels returns every panel of the dashboard in layout order, including panels nested in
+// collapsed rows. Row panels themselves are omitted.
+func (d *Dashboard) AllPanels() []Panel {
+	var panels []Panel
+	var walk func([]Panel)
+	walk = func(list []Panel) {
+		for _, p := range list {
+			if p.Type != "row" {
+				panels = append(panels, p)
+			}
+			walk(p.Panels)
+		}
+	}
+	walk(d.Panels)
+	return panels
+}
+
+// FindPanel returns the panel with the given ID, searching collapsed rows too
+func (d *Dashboard) FindPanel(id int64) (Panel, bool) {
+	for _, p := range d.AllPanels() {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Panel{}, false
+}
@@ -0,0 +1,117 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Dashboard permission levels
+const (
+	PermissionView  = 1
+	PermissionEdit  = 2
+	PermissionAdmin = 4
+)
+
+// roleRank orders the org roles by privilege; other roles (e.g. None) rank below Viewer
+var roleRank = map[string]int{"Viewer": 1, "Editor": 2, "Admin": 3}
+
+// OrgUser is a member of the client's organization
+type OrgUser struct {
+	UserID int64  `json:"userId"`
+	Login  string `json:"login"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+}
+
+// DashboardPermission is an entry of a dashboard's access list, either set on the dashboard or
+// inherited from its folders. It grants Permission to a user, a team or everyone with a role.
+type DashboardPermission struct {
+	UserID     int64  `json:"userId"`
+	TeamID     int64  `json:"teamId"`
+	Role       string `json:"role"`
+	Permission int    `json:"permission"`
+}
+
+// FindOrgUser looks up a member of the organization by login, or by user ID when login is empty.
+// It returns nil when no member matches.
+func (c *Client) FindOrgUser(ctx context.Context, login string, userID int64) (*OrgUser, error) {
+	var users []OrgUser
+	if err := c.do(ctx, http.MethodGet, "/api/org/users", nil, &users); err != nil {
+		return nil, fmt.Errorf("failed to list organization users: %w", err)
+	}
+	for i := range users {
+		if (login != "" && users[i].Login == login) || (login == "" && users[i].UserID == userID) {
+			return &users[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetDashboardPermissions fetches the access list of a dashboard
+func (c *Client) GetDashboardPermissions(ctx context.Context, uid string) ([]DashboardPermission, error) {
+	var permissions []DashboardPermission
+	if err := c.do(ctx, http.MethodGet, "/api/dashboards/uid/"+url.PathEscape(uid)+"/permissions", nil, &permissions); err != nil {
+		return nil, fmt.Errorf("failed to get permissions of dashboard %s: %w", uid, err)
+	}
+	return permissions, nil
+}
+
+// GetTeamMemberIDs fetches the user IDs of a team's members
+func (c *Client) GetTeamMemberIDs(ctx context.Context, teamID int64) ([]int64, error) {
+	var members []struct {
+		UserID int64 `json:"userId"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/teams/"+strconv.FormatInt(teamID, 10)+"/members", nil, &members); err != nil {
+		return nil, fmt.Errorf("failed to get members of team %d: %w", teamID, err)
+	}
+	ids := make([]int64, len(members))
+	for i, member := range members {
+		ids[i] = member.UserID
+	}
+	return ids, nil
+}
+
+// CanView reports whether the user can view the dashboard: org admins view every dashboard, others
+// need a view permission granted to them, one of their teams, or their role. Deleted dashboards
+// fail with an error IsNotFound reports.
+func (c *Client) CanView(ctx context.Context, user *OrgUser, uid string) (bool, error) {
+	permissions, err := c.GetDashboardPermissions(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	if user.Role == "Admin" {
+		return true, nil
+	}
+
+	var teams []int64
+	for _, p := range permissions {
+		if p.Permission < PermissionView {
+			continue
+		}
+		switch {
+		case p.UserID != 0 && p.UserID == user.UserID:
+			return true, nil
+		case p.Role != "" && roleRank[user.Role] >= roleRank[p.Role]:
+			return true, nil
+		case p.TeamID != 0:
+			teams = append(teams, p.TeamID)
+		}
+	}
+
+	// Team memberships are only looked up when no direct grant applies
+	for _, teamID := range teams {
+		members, err := c.GetTeamMemberIDs(ctx, teamID)
+		if err != nil {
+			return false, err
+		}
+		for _, id := range members {
+			if id == user.UserID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
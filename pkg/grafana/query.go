@@ -0,0 +1,124 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+)
+
+// QueryRequest is the body of a /api/ds/query request
+type QueryRequest struct {
+	From    string                   `json:"from"`
+	To      string                   `json:"to"`
+	Queries []map[string]interface{} `json:"queries"`
+}
+
+// QueryResponse is the body of a /api/ds/query response, keyed by query refId
+type QueryResponse struct {
+	Results map[string]QueryResult `json:"results"`
+}
+
+// QueryResult holds the data frames returned for a single query
+type QueryResult struct {
+	Status int     `json:"status,omitempty"`
+	Error  string  `json:"error,omitempty"`
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is a data frame in Grafana's JSON wire format
+type Frame struct {
+	Schema FrameSchema `json:"schema"`
+	Data   FrameData   `json:"data"`
+}
+
+// FrameSchema describes the fields of a data frame
+type FrameSchema struct {
+	Name   string  `json:"name,omitempty"`
+	RefID  string  `json:"refId,omitempty"`
+	Fields []Field `json:"fields"`
+}
+
+// Field describes a single column of a data frame
+type Field struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Config struct {
+		DisplayName       string `json:"displayName,omitempty"`
+		DisplayNameFromDS string `json:"displayNameFromDS,omitempty"`
+	} `json:"config"`
+}
+
+// FrameData holds column-oriented frame values; Values[i] belongs to Schema.Fields[i]
+type FrameData struct {
+	Values [][]interface{} `json:"values"`
+}
+
+// defaultMaxDataPoints is used for panels that do not configure maxDataPoints
+const defaultMaxDataPoints = 1000
+
+// PanelQueries builds /api/ds/query queries for the visible targets of a panel.
+// Template variables are substituted, data sources are resolved per target (for mixed panels),
+// and legacy data source names are resolved to UIDs.
+func (c *Client) PanelQueries(ctx context.Context, panel Panel, vars Variables) ([]map[string]interface{}, error) {
+	maxDataPoints := panel.MaxDataPts
+	if maxDataPoints <= 0 {
+		maxDataPoints = defaultMaxDataPoints
+	}
+
+	queries := make([]map[string]interface{}, 0, len(panel.Targets))
+	for i, target := range panel.Targets {
+		if hide, _ := target["hide"].(bool); hide {
+			continue
+		}
+
+		query := vars.Interpolate(target).(map[string]interface{})
+		if _, ok := query["refId"]; !ok {
+			query["refId"] = fmt.Sprintf("Q%d", i)
+		}
+
+		ds, err := c.resolveDataSource(ctx, panel, query, vars)
+		if err != nil {
+			return nil, err
+		}
+		query["datasource"] = ds
+		query["maxDataPoints"] = maxDataPoints
+		queries = append(queries, query)
+	}
+
+	return queries, nil
+}
+
+// resolveDataSource picks the data source for a query: the target's own data source for mixed
+// panels (or when set), otherwise the panel data source
+func (c *Client) resolveDataSource(ctx context.Context, panel Panel, query map[string]interface{}, vars Variables) (map[string]interface{}, error) {
+	var ref DataSourceRef
+	switch ds := query["datasource"].(type) {
+	case map[string]interface{}:
+		ref.UID, _ = ds["uid"].(string)
+		ref.Type, _ = ds["type"].(string)
+	case string:
+		ref.Name = ds
+	}
+	if ref.UID == "" && ref.Name == "" && panel.Datasource != nil {
+		ref = *panel.Datasource
+		ref.UID = vars.Replace(ref.UID)
+		ref.Name = vars.Replace(ref.Name)
+	}
+
+	if ref.UID == "" && ref.Name != "" {
+		uid, err := c.GetDataSourceUID(ctx, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		ref.UID = uid
+	}
+	if ref.UID == "" || ref.UID == MixedDataSource {
+		return nil, fmt.Errorf("panel %d: query %v has no data source", panel.ID, query["refId"])
+	}
+
+	ds := map[string]interface{}{"uid": ref.UID}
+	if ref.Type != "" {
+		ds["type"] = ref.Type
+	}
+	return ds, nil
+}
@@ -0,0 +1,166 @@
+package grafana
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// absoluteLayouts are the absolute time formats accepted in time ranges, tried in order
+var absoluteLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ResolveTimeRange resolves a dashboard time range such as "now-7d/d" to "now/d" to absolute times
+// in loc, the way Grafana does when it opens the dashboard: rounding in "from" goes to the start of
+// the unit and rounding in "to" to its end.
+func ResolveTimeRange(from, to string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := ParseTime(from, now, loc, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid range start: %w", err)
+	}
+	end, err := ParseTime(to, now, loc, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid range end: %w", err)
+	}
+	return start, end, nil
+}
+
+// ParseTime resolves a Grafana time expression to an absolute time in loc. It accepts relative
+// expressions ("now", "now-6h", "now-1M/M"), epoch milliseconds and ISO dates. roundUp selects the
+// end of the unit for "/unit" rounding; weeks start on Monday. Besides Grafana's units, Q selects
+// calendar quarters, so "now-1Q/Q" to "now-1Q/Q" is the previous full quarter.
+func ParseTime(expr string, now time.Time, loc *time.Location, roundUp bool) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return time.Time{}, fmt.Errorf("empty time expression")
+	}
+
+	if !strings.HasPrefix(expr, "now") {
+		if ms, err := strconv.ParseInt(expr, 10, 64); err == nil {
+			return time.UnixMilli(ms).In(loc), nil
+		}
+		for _, layout := range absoluteLayouts {
+			if t, err := time.ParseInLocation(layout, expr, loc); err == nil {
+				return t.In(loc), nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unrecognized time %q", expr)
+	}
+
+	t := now.In(loc)
+	ops := expr[len("now"):]
+	for ops != "" {
+		op := ops[0]
+		ops = ops[1:]
+		switch op {
+		case '/':
+			if ops == "" {
+				return time.Time{}, fmt.Errorf("missing unit after / in %q", expr)
+			}
+			unit := ops[0]
+			ops = ops[1:]
+			rounded, err := roundTime(t, unit, roundUp)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("%w in %q", err, expr)
+			}
+			t = rounded
+		case '+', '-':
+			digits := 0
+			for digits < len(ops) && ops[digits] >= '0' && ops[digits] <= '9' {
+				digits++
+			}
+			amount := 1
+			if digits > 0 {
+				amount, _ = strconv.Atoi(ops[:digits])
+			}
+			if digits == len(ops) {
+				return time.Time{}, fmt.Errorf("missing unit in %q", expr)
+			}
+			unit := ops[digits]
+			ops = ops[digits+1:]
+			if op == '-' {
+				amount = -amount
+			}
+			shifted, err := addUnits(t, unit, amount)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("%w in %q", err, expr)
+			}
+			t = shifted
+		default:
+			return time.Time{}, fmt.Errorf("unexpected %q in %q", op, expr)
+		}
+	}
+	return t, nil
+}
+
+// addUnits shifts t by amount units. Like Grafana, months, quarters and years keep the day of
+// month but clamp it to the target month, so March 31 minus a month is the last day of February.
+func addUnits(t time.Time, unit byte, amount int) (time.Time, error) {
+	switch unit {
+	case 's':
+		return t.Add(time.Duration(amount) * time.Second), nil
+	case 'm':
+		return t.Add(time.Duration(amount) * time.Minute), nil
+	case 'h':
+		return t.Add(time.Duration(amount) * time.Hour), nil
+	case 'd':
+		return t.AddDate(0, 0, amount), nil
+	case 'w':
+		return t.AddDate(0, 0, 7*amount), nil
+	case 'M':
+		return addMonths(t, amount), nil
+	case 'Q':
+		return addMonths(t, 3*amount), nil
+	case 'y':
+		return addMonths(t, 12*amount), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown unit %q", unit)
+}
+
+// addMonths shifts t by months, clamping the day to the length of the target month
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+// roundTime rounds t down to the start of the unit, or up to its last millisecond
+func roundTime(t time.Time, unit byte, up bool) (time.Time, error) {
+	loc := t.Location()
+	var start time.Time
+	switch unit {
+	case 's':
+		start = t.Truncate(time.Second)
+	case 'm':
+		start = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	case 'h':
+		start = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+	case 'd':
+		start = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	case 'w':
+		offset := (int(t.Weekday()) + 6) % 7
+		start = time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, loc)
+	case 'M':
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	case 'Q':
+		start = time.Date(t.Year(), t.Month()-(t.Month()-1)%3, 1, 0, 0, 0, 0, loc)
+	case 'y':
+		start = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Time{}, fmt.Errorf("unknown unit %q", unit)
+	}
+	if !up {
+		return start, nil
+	}
+	next, _ := addUnits(start, unit, 1)
+	return next.Add(-time.Millisecond), nil
+}
@@ -0,0 +1,84 @@
+package grafana
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// Thursday
+	now := time.Date(2024, 3, 14, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expr    string
+		roundUp bool
+		want    time.Time
+	}{
+		{"now", false, time.Date(2024, 3, 14, 11, 30, 15, 0, berlin)},
+		{"now-6h", false, time.Date(2024, 3, 14, 5, 30, 15, 0, berlin)},
+		{"now-7d/d", false, time.Date(2024, 3, 7, 0, 0, 0, 0, berlin)},
+		{"now/d", true, time.Date(2024, 3, 14, 23, 59, 59, int(999*time.Millisecond), berlin)},
+		{"now/w", false, time.Date(2024, 3, 11, 0, 0, 0, 0, berlin)},
+		{"now-1M/M", false, time.Date(2024, 2, 1, 0, 0, 0, 0, berlin)},
+		{"now-1M/M", true, time.Date(2024, 2, 29, 23, 59, 59, int(999*time.Millisecond), berlin)},
+		{"now/y", false, time.Date(2024, 1, 1, 0, 0, 0, 0, berlin)},
+		{"now-1Q/Q", false, time.Date(2023, 10, 1, 0, 0, 0, 0, berlin)},
+		{"now-1Q/Q", true, time.Date(2023, 12, 31, 23, 59, 59, int(999*time.Millisecond), berlin)},
+		{"now/Q", false, time.Date(2024, 1, 1, 0, 0, 0, 0, berlin)},
+		{"now+1h", false, time.Date(2024, 3, 14, 12, 30, 15, 0, berlin)},
+		{"1710410400000", false, time.Date(2024, 3, 14, 11, 0, 0, 0, berlin)},
+		{"2024-03-01", false, time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)},
+		{"2024-03-01T08:00:00Z", false, time.Date(2024, 3, 1, 9, 0, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.expr, now, berlin, tt.roundUp)
+		if err != nil {
+			t.Errorf("ParseTime(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q, roundUp=%v) = %v, want %v", tt.expr, tt.roundUp, got, tt.want)
+		}
+	}
+
+	// Month arithmetic clamps to the end of shorter months
+	endOfMonth := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	for expr, want := range map[string]time.Time{
+		"now-1M":   time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+		"now-1M/M": time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		"now-1y":   time.Date(2023, 3, 31, 12, 0, 0, 0, time.UTC),
+		"now+1Q":   time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC),
+	} {
+		if got, err := ParseTime(expr, endOfMonth, time.UTC, false); err != nil || !got.Equal(want) {
+			t.Errorf("ParseTime(%q) on March 31 = %v, %v, want %v", expr, got, err, want)
+		}
+	}
+
+	for _, expr := range []string{"", "yesterday", "now-", "now-5x", "now/x", "now*2"} {
+		if _, err := ParseTime(expr, now, berlin, false); err == nil {
+			t.Errorf("ParseTime(%q) expected error", expr)
+		}
+	}
+}
+
+func TestResolveTimeRange(t *testing.T) {
+	now := time.Date(2024, 3, 14, 10, 30, 0, 0, time.UTC)
+	from, to, err := ResolveTimeRange("now-1d/d", "now-1d/d", now, time.UTC)
+	if err != nil {
+		t.Fatalf("ResolveTimeRange: %v", err)
+	}
+	if want := time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("from = %v, want %v", from, want)
+	}
+	if want := time.Date(2024, 3, 13, 23, 59, 59, int(999*time.Millisecond), time.UTC); !to.Equal(want) {
+		t.Errorf("to = %v, want %v", to, want)
+	}
+
+	if _, _, err := ResolveTimeRange("now-1d", "later", now, time.UTC); err == nil {
+		t.Error("expected error for invalid range end")
+	}
+}
@@ -0,0 +1,64 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// ServiceAccountToken retrieves the plugin's token from Grafana's managed service accounts.
+// The Grafana config in the context is preferred; background jobs without it fall back to
+// the GF_PLUGIN_APP_CLIENT_SECRET environment variable.
+func ServiceAccountToken(ctx context.Context) (string, error) {
+	log.Printf("DEBUG: ========== TOKEN RETRIEVAL START ==========")
+
+	// Priority 1: Try to get token from Grafana's managed service account (preferred method)
+	// Grafana 10.3+ automatically creates a service account for the plugin based on plugin.json IAM configuration
+	cfg := backend.GrafanaConfigFromContext(ctx)
+	if cfg != nil {
+		log.Printf("DEBUG: Grafana config available in context - trying cfg.PluginAppClientSecret()")
+		token, err := cfg.PluginAppClientSecret()
+		if err != nil {
+			log.Printf("ERROR: cfg.PluginAppClientSecret() returned error: %v", err)
+		}
+		if token != "" {
+			log.Printf("SUCCESS: Retrieved token from Grafana SDK (length: %d, preview: %s...)", len(token), token[:min(20, len(token))])
+			log.Printf("DEBUG: ========== TOKEN RETRIEVAL END (via SDK) ==========")
+			return token, nil
+		}
+		log.Printf("WARNING: cfg.PluginAppClientSecret() returned empty token")
+	} else {
+		log.Printf("DEBUG: Grafana config NOT available in context (expected for background jobs)")
+	}
+
+	// Priority 2: Check environment variable GF_PLUGIN_APP_CLIENT_SECRET
+	// This is set by Grafana when the plugin starts if managed service accounts are enabled
+	log.Printf("DEBUG: Checking environment variable GF_PLUGIN_APP_CLIENT_SECRET...")
+	token := os.Getenv("GF_PLUGIN_APP_CLIENT_SECRET")
+	if token != "" {
+		log.Printf("SUCCESS: Retrieved token from GF_PLUGIN_APP_CLIENT_SECRET env var (length: %d, preview: %s...)", len(token), token[:min(20, len(token))])
+		log.Printf("DEBUG: ========== TOKEN RETRIEVAL END (via env var) ==========")
+		return token, nil
+	}
+	log.Printf("WARNING: GF_PLUGIN_APP_CLIENT_SECRET environment variable is not set or empty")
+
+	// No token available - managed service accounts not working
+	log.Printf("ERROR: ========== TOKEN RETRIEVAL FAILED - NO TOKEN FOUND ==========")
+	return "", fmt.Errorf(
+		"no service account token available\n\n" +
+			"Grafana managed service accounts are not configured correctly.\n\n" +
+			"Requirements:\n" +
+			"- Grafana 10.3 or later\n" +
+			"- Feature toggle enabled: [feature_toggles] enable = externalServiceAccounts\n" +
+			"- Plugin must be restarted after installation\n\n" +
+			"Steps to fix:\n" +
+			"1. Add to grafana.ini: [feature_toggles] enable = externalServiceAccounts\n" +
+			"2. Restart Grafana: sudo systemctl restart grafana-server\n" +
+			"3. Check Settings page (Apps → Scheduled Reports → Settings) for service account status\n\n" +
+			"The plugin.json already has IAM permissions configured, so Grafana will automatically\n" +
+			"create a service account when the feature toggle is enabled.",
+	)
+}
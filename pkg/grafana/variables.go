@@ -0,0 +1,115 @@
+package grafana
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// Variables maps dashboard template variable names to their selected values
+type Variables map[string][]string
+
+// allValue is the value Grafana stores when "All" is selected for a variable
+const allValue = "$__all"
+
+// variablePattern matches $var, ${var}, ${var:format}, [[var]] and [[var:format]]
+var variablePattern = regexp.MustCompile(`\$(\w+)|\$\{(\w+)(?::([^}]+))?\}|\[\[(\w+)(?::([^\]]+))?\]\]`)
+
+// VariablesFromSchedule collects the schedule's variable values; repeated names become multi-value variables
+func VariablesFromSchedule(schedule *model.Schedule) Variables {
+	vars := Variables{}
+	for _, v := range schedule.Variables {
+		vars[v.Name] = append(vars[v.Name], v.Value)
+	}
+	return vars
+}
+
+// Replace substitutes template variables in text. Built-in variables ($__interval, $__timeFilter, ...)
+// and unknown variables are left for the data source to handle.
+func (v Variables) Replace(text string) string {
+	if len(v) == 0 || !strings.ContainsAny(text, "$[") {
+		return text
+	}
+
+	return variablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := variablePattern.FindStringSubmatch(match)
+		name, format := groups[1], ""
+		switch {
+		case groups[2] != "":
+			name, format = groups[2], groups[3]
+		case groups[4] != "":
+			name, format = groups[4], groups[5]
+		}
+
+		values, ok := v[name]
+		if !ok || strings.HasPrefix(name, "__") {
+			return match
+		}
+		return formatValues(values, format)
+	})
+}
+
+// Interpolate substitutes template variables in every string of a decoded JSON value
+func (v Variables) Interpolate(value interface{}) interface{} {
+	switch val := value.(type) {
+	case string:
+		return v.Replace(val)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = v.Interpolate(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = v.Interpolate(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// formatValues renders variable values using a Grafana format specifier
+// (csv, pipe, regex, singlequote, doublequote, raw, glob)
+func formatValues(values []string, format string) string {
+	if len(values) == 1 && values[0] == allValue {
+		return ".*"
+	}
+	if len(values) == 1 && format != "singlequote" && format != "doublequote" && format != "regex" {
+		return values[0]
+	}
+
+	switch format {
+	case "csv", "raw":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		escaped := make([]string, len(values))
+		for i, value := range values {
+			escaped[i] = regexp.QuoteMeta(value)
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "singlequote":
+		return quoteValues(values, "'")
+	case "doublequote":
+		return quoteValues(values, `"`)
+	default:
+		return "{" + strings.Join(values, ",") + "}"
+	}
+}
+
+// quoteValues quotes each value and joins them with commas
+func quoteValues(values []string, quote string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quote + strings.ReplaceAll(value, quote, "\\"+quote) + quote
+	}
+	return strings.Join(quoted, ",")
+}
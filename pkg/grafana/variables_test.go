@@ -0,0 +1,79 @@
+package grafana
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+func TestVariablesReplace(t *testing.T) {
+	vars := Variables{
+		"env":    {"prod"},
+		"host":   {"web-1", "web-2"},
+		"region": {allValue},
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"simple", `up{env="$env"}`, `up{env="prod"}`},
+		{"braces", `${env}_total`, `prod_total`},
+		{"brackets", `[[env]]`, `prod`},
+		{"multi value defaults to glob", `$host`, `{web-1,web-2}`},
+		{"multi value regex", `host=~"${host:regex}"`, `host=~"(web-1|web-2)"`},
+		{"multi value csv", `${host:csv}`, `web-1,web-2`},
+		{"multi value pipe", `[[host:pipe]]`, `web-1|web-2`},
+		{"single quoted", `IN (${host:singlequote})`, `IN ('web-1','web-2')`},
+		{"all value", `$region`, `.*`},
+		{"builtin untouched", `rate(x[$__interval])`, `rate(x[$__interval])`},
+		{"unknown untouched", `$missing`, `$missing`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vars.Replace(tt.text); got != tt.want {
+				t.Errorf("Replace(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariablesFromSchedule(t *testing.T) {
+	schedule := &model.Schedule{
+		Variables: model.VariableList{
+			{Name: "host", Value: "a"},
+			{Name: "env", Value: "prod"},
+			{Name: "host", Value: "b"},
+		},
+	}
+
+	want := Variables{"host": {"a", "b"}, "env": {"prod"}}
+	if got := VariablesFromSchedule(schedule); !reflect.DeepEqual(got, want) {
+		t.Errorf("VariablesFromSchedule() = %v, want %v", got, want)
+	}
+}
+
+func TestVariablesInterpolateNested(t *testing.T) {
+	vars := Variables{"env": {"prod"}}
+	target := map[string]interface{}{
+		"expr":    `up{env="$env"}`,
+		"filters": []interface{}{map[string]interface{}{"value": "$env"}},
+		"hide":    false,
+		"step":    float64(15),
+	}
+
+	got := vars.Interpolate(target).(map[string]interface{})
+	if got["expr"] != `up{env="prod"}` {
+		t.Errorf("expr = %v", got["expr"])
+	}
+	filter := got["filters"].([]interface{})[0].(map[string]interface{})
+	if filter["value"] != "prod" {
+		t.Errorf("nested value = %v", filter["value"])
+	}
+	if target["expr"] != `up{env="$env"}` {
+		t.Error("Interpolate() must not modify the original target")
+	}
+}
@@ -15,6 +15,13 @@ type Mailer struct {
 	config model.SMTPConfig
 }
 
+// Attachment is a file attached to a report email
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
 // NewMailer creates a new mailer instance
 func NewMailer(config model.SMTPConfig) *Mailer {
 	return &Mailer{
@@ -23,7 +30,7 @@ func NewMailer(config model.SMTPConfig) *Mailer {
 }
 
 // SendReport sends a report via email
-func (m *Mailer) SendReport(recipients model.Recipients, subject, body string, attachment []byte, filename string) error {
+func (m *Mailer) SendReport(recipients model.Recipients, subject, body string, attachments []Attachment) error {
 	msg := gomail.NewMessage()
 
 	// Set sender
@@ -47,12 +54,24 @@ func (m *Mailer) SendReport(recipients model.Recipients, subject, body string, a
 	msg.SetHeader("Subject", subject)
 	msg.SetBody("text/html", body)
 
-	// Attach report
-	if len(attachment) > 0 {
-		msg.Attach(filename, gomail.SetCopyFunc(func(w io.Writer) error {
-			_, err := w.Write(attachment)
-			return err
-		}))
+	// Attach report files
+	for _, attachment := range attachments {
+		if len(attachment.Data) == 0 {
+			continue
+		}
+		data := attachment.Data
+		settings := []gomail.FileSetting{
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(data)
+				return err
+			}),
+		}
+		if attachment.ContentType != "" {
+			settings = append(settings, gomail.SetHeader(map[string][]string{
+				"Content-Type": {fmt.Sprintf("%s; name=%q", attachment.ContentType, attachment.Filename)},
+			}))
+		}
+		msg.Attach(attachment.Filename, settings...)
 	}
 
 	// Create dialer
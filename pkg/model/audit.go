@@ -0,0 +1,119 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// Redacted replaces the values of secrets in audit diffs
+const Redacted = "[redacted]"
+
+// AuditChange is the old and new value of a JSON path in an audit diff
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditDiff returns the JSON paths (e.g. "recipients.to") whose values differ between the JSON
+// encodings of before and after, either of which may be nil for created and deleted resources.
+// Objects are compared field by field, arrays of objects element by element (e.g. "targets.0.slack.token")
+// and other arrays as a whole. The values of the redacted paths are replaced with Redacted, so their
+// diff only records that they changed.
+func AuditDiff(before, after interface{}, redacted ...string) (json.RawMessage, error) {
+	oldValue, err := jsonValue(before)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := jsonValue(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]AuditChange)
+	diffValues("", oldValue, newValue, changes)
+	for _, path := range redacted {
+		if change, ok := changes[path]; ok {
+			changes[path] = AuditChange{Old: redact(change.Old), New: redact(change.New)}
+		}
+	}
+	return json.Marshal(changes)
+}
+
+// jsonValue returns the generic JSON representation of v
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// diffValues records the changes between two JSON values at path. Objects are descended into even
+// when the other side is missing, so every change (and every secret) is recorded at its own path.
+func diffValues(path string, oldValue, newValue interface{}, changes map[string]AuditChange) {
+	oldObject, oldIsObject := oldValue.(map[string]interface{})
+	newObject, newIsObject := newValue.(map[string]interface{})
+	if (oldIsObject || oldValue == nil) && (newIsObject || newValue == nil) && (oldIsObject || newIsObject) {
+		for key, value := range oldObject {
+			diffValues(joinPath(path, key), value, newObject[key], changes)
+		}
+		for key, value := range newObject {
+			if _, ok := oldObject[key]; !ok {
+				diffValues(joinPath(path, key), nil, value, changes)
+			}
+		}
+		return
+	}
+	oldArray, oldIsObjects := objectArray(oldValue)
+	newArray, newIsObjects := objectArray(newValue)
+	if (oldIsObjects || oldValue == nil) && (newIsObjects || newValue == nil) && (oldIsObjects || newIsObjects) {
+		for i := 0; i < len(oldArray) || i < len(newArray); i++ {
+			var oldElement, newElement interface{}
+			if i < len(oldArray) {
+				oldElement = oldArray[i]
+			}
+			if i < len(newArray) {
+				newElement = newArray[i]
+			}
+			diffValues(joinPath(path, strconv.Itoa(i)), oldElement, newElement, changes)
+		}
+		return
+	}
+	if !reflect.DeepEqual(oldValue, newValue) {
+		changes[path] = AuditChange{Old: oldValue, New: newValue}
+	}
+}
+
+// objectArray returns value as an array if it is one holding objects
+func objectArray(value interface{}) ([]interface{}, bool) {
+	array, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	for _, element := range array {
+		if _, ok := element.(map[string]interface{}); ok {
+			return array, true
+		}
+	}
+	return nil, false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// redact hides a secret value, keeping whether it was set
+func redact(value interface{}) interface{} {
+	if value == nil || value == "" {
+		return value
+	}
+	return Redacted
+}
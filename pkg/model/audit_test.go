@@ -0,0 +1,81 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAuditDiff(t *testing.T) {
+	before := &Settings{
+		OrgID:      1,
+		SMTPConfig: &SMTPConfig{Host: "smtp.example.com", Password: "old"},
+		Limits:     Limits{MaxRecipients: 10, AllowedDomains: []string{"example.com"}},
+	}
+	after := &Settings{
+		OrgID:      1,
+		SMTPConfig: &SMTPConfig{Host: "smtp.example.com", Password: "new"},
+		Limits:     Limits{MaxRecipients: 20, AllowedDomains: []string{"example.com", "example.org"}},
+		Storage:    StorageConfig{Backend: StorageS3, S3: &S3Config{Bucket: "reports", SecretAccessKey: "key"}},
+	}
+
+	diff, err := AuditDiff(before, after, "smtp_config.password", "storage.s3.secret_access_key")
+	if err != nil {
+		t.Fatalf("AuditDiff() error = %v", err)
+	}
+	var got map[string]AuditChange
+	if err := json.Unmarshal(diff, &got); err != nil {
+		t.Fatalf("AuditDiff() returned invalid JSON %s: %v", diff, err)
+	}
+
+	// The S3 config is new, so every field of it changed
+	want := map[string]AuditChange{
+		"smtp_config.password":         {Old: Redacted, New: Redacted},
+		"limits.max_recipients":        {Old: 10.0, New: 20.0},
+		"limits.allowed_domains":       {Old: []interface{}{"example.com"}, New: []interface{}{"example.com", "example.org"}},
+		"storage.backend":              {Old: nil, New: StorageS3},
+		"storage.s3.endpoint":          {Old: nil, New: ""},
+		"storage.s3.bucket":            {Old: nil, New: "reports"},
+		"storage.s3.access_key_id":     {Old: nil, New: ""},
+		"storage.s3.secret_access_key": {Old: nil, New: Redacted},
+		"storage.s3.use_path_style":    {Old: nil, New: false},
+		"storage.s3.skip_tls_verify":   {Old: nil, New: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuditDiff() = %s, want %+v", diff, want)
+	}
+
+	// Created resources diff against nothing
+	diff, err = AuditDiff(nil, &Recipients{To: []string{"a@example.com"}})
+	if err != nil {
+		t.Fatalf("AuditDiff() error = %v", err)
+	}
+	if string(diff) != `{"to":{"old":null,"new":["a@example.com"]}}` {
+		t.Errorf("AuditDiff(nil, recipients) = %s", diff)
+	}
+
+	// Arrays of objects are compared element by element, so secrets inside them can be redacted
+	diff, err = AuditDiff(
+		&Schedule{Targets: DeliveryTargets{{Type: TargetSlack, Slack: &SlackTarget{Token: "old-token", Channel: "C1"}}}},
+		&Schedule{Targets: DeliveryTargets{
+			{Type: TargetSlack, Slack: &SlackTarget{Token: "new-token", Channel: "C1"}},
+			{Type: TargetTeams, Teams: &TeamsTarget{WebhookURL: "https://example.webhook.office.com/a"}},
+		}},
+		"targets.0.slack.token", "targets.1.teams.webhook_url",
+	)
+	if err != nil {
+		t.Fatalf("AuditDiff() error = %v", err)
+	}
+	got = nil
+	if err := json.Unmarshal(diff, &got); err != nil {
+		t.Fatalf("AuditDiff() returned invalid JSON %s: %v", diff, err)
+	}
+	want = map[string]AuditChange{
+		"targets.0.slack.token":       {Old: Redacted, New: Redacted},
+		"targets.1.type":              {Old: nil, New: TargetTeams},
+		"targets.1.teams.webhook_url": {Old: nil, New: Redacted},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuditDiff(targets) = %s, want %+v", diff, want)
+	}
+}
@@ -3,35 +3,253 @@ package model
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"strconv"
 	"time"
 )
 
-// Schedule represents a scheduled report (PDF format only)
+// Schedule represents a scheduled report
 type Schedule struct {
-	ID             int64        `json:"id"`
-	OrgID          int64        `json:"org_id"`
-	Name           string       `json:"name"`
-	DashboardUID   string       `json:"dashboard_uid"`
-	DashboardTitle string       `json:"dashboard_title,omitempty"`
-	PanelIDs       IntSlice     `json:"panel_ids,omitempty"`
-	RangeFrom      string       `json:"range_from"`
-	RangeTo        string       `json:"range_to"`
-	IntervalType   string       `json:"interval_type"`
-	CronExpr       string       `json:"cron_expr,omitempty"`
-	Timezone       string       `json:"timezone"`
-	Variables      VariableList `json:"variables,omitempty"`
-	Recipients     Recipients   `json:"recipients"`
-	EmailSubject   string       `json:"email_subject"`
-	EmailBody      string       `json:"email_body"`
-	TemplateID     *int64       `json:"template_id,omitempty"`
-	Enabled        bool         `json:"enabled"`
-	LastRunAt      *time.Time   `json:"last_run_at,omitempty"`
-	NextRunAt      *time.Time   `json:"next_run_at,omitempty"`
-	OwnerUserID    int64        `json:"owner_user_id"`
-	CreatedAt      time.Time    `json:"created_at"`
-	UpdatedAt      time.Time    `json:"updated_at"`
+	ID             int64           `json:"id"`
+	OrgID          int64           `json:"org_id"`
+	Name           string          `json:"name"`
+	DashboardUID   string          `json:"dashboard_uid"`
+	DashboardTitle string          `json:"dashboard_title,omitempty"`
+	Dashboards     DashboardRefs   `json:"dashboards,omitempty"` // Combine these dashboards into one report instead of DashboardUID
+	PanelIDs       IntSlice        `json:"panel_ids,omitempty"`
+	RangeFrom      string          `json:"range_from"`
+	RangeTo        string          `json:"range_to"`
+	IntervalType   string          `json:"interval_type"`
+	CronExpr       string          `json:"cron_expr,omitempty"`
+	Timezone       string          `json:"timezone"`
+	Variables      VariableList    `json:"variables,omitempty"`
+	Recipients     Recipients      `json:"recipients"`
+	Targets        DeliveryTargets `json:"targets,omitempty"` // Destinations besides email recipients
+	EmailSubject   string          `json:"email_subject"`
+	EmailBody      string          `json:"email_body"`
+	TemplateID     *int64          `json:"template_id,omitempty"`
+	Format         string          `json:"format,omitempty"`      // "pdf" (default), "png" or "jpeg"
+	Layout         string          `json:"layout,omitempty"`      // "single" (default) or "paginated"
+	Theme          string          `json:"theme,omitempty"`       // Grafana theme: "light" (default), "dark" or a named theme
+	DataFormat     string          `json:"data_format,omitempty"` // Attach panel query results as "csv" or "xlsx"
+	DataOnly       bool            `json:"data_only,omitempty"`   // Attach only the data export, skipping the rendered report
+	Condition      *Condition      `json:"condition,omitempty"`   // Only deliver when the condition is met
+	Burst          *Burst          `json:"burst,omitempty"`       // Render one report per variable value for its own recipients
+	Enabled        bool            `json:"enabled"`
+	LastRunAt      *time.Time      `json:"last_run_at,omitempty"`
+	NextRunAt      *time.Time      `json:"next_run_at,omitempty"`
+	OwnerUserID    int64           `json:"owner_user_id"`         // Grafana user ID of the owner; 0 for schedules identified by OwnerLogin
+	OwnerLogin     string          `json:"owner_login,omitempty"` // Login of the Grafana user who created the schedule
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
 }
 
+// Report output formats
+const (
+	FormatPDF  = "pdf"
+	FormatPNG  = "png"
+	FormatJPEG = "jpeg"
+)
+
+// Data export formats
+const (
+	DataFormatCSV  = "csv"
+	DataFormatXLSX = "xlsx"
+)
+
+// Condition reducers, turning each series returned by a condition query into one value
+const (
+	ReducerLast  = "last"
+	ReducerMin   = "min"
+	ReducerMax   = "max"
+	ReducerMean  = "mean"
+	ReducerSum   = "sum"
+	ReducerCount = "count"
+)
+
+// Condition gates a schedule's reports on a data source query, e.g. "send only if the mean error
+// rate over the report's time range is above 5". The condition is met when any series returned by
+// the query, reduced to a single value, compares true against the threshold.
+type Condition struct {
+	DatasourceUID string                 `json:"datasource_uid"`
+	Query         map[string]interface{} `json:"query"`    // Query model as in a panel target; dashboard variables are substituted
+	Reducer       string                 `json:"reducer"`  // See Reducer* constants
+	Operator      string                 `json:"operator"` // >, >=, <, <=, == or !=
+	Threshold     float64                `json:"threshold"`
+}
+
+// Compare applies the condition's operator to a reduced value and the threshold
+func (c *Condition) Compare(value float64) bool {
+	switch c.Operator {
+	case ">":
+		return value > c.Threshold
+	case ">=":
+		return value >= c.Threshold
+	case "<":
+		return value < c.Threshold
+	case "<=":
+		return value <= c.Threshold
+	case "==":
+		return value == c.Threshold
+	case "!=":
+		return value != c.Threshold
+	default:
+		return false
+	}
+}
+
+// Scan implements sql.Scanner for Condition
+func (c *Condition) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// Value implements driver.Valuer for Condition
+func (c Condition) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// MaxReportDashboards caps the number of dashboards combined into one report
+const MaxReportDashboards = 20
+
+// DashboardRef is one dashboard of a multi-dashboard report. An empty time range uses the
+// schedule's; its variables are added to the schedule's, replacing variables of the same name.
+type DashboardRef struct {
+	UID       string       `json:"uid"`
+	Title     string       `json:"title,omitempty"`
+	PanelIDs  IntSlice     `json:"panel_ids,omitempty"`
+	RangeFrom string       `json:"range_from,omitempty"`
+	RangeTo   string       `json:"range_to,omitempty"`
+	Variables VariableList `json:"variables,omitempty"`
+}
+
+// DashboardRefs is the ordered list of dashboards of a multi-dashboard report
+type DashboardRefs []DashboardRef
+
+// Scan implements sql.Scanner for DashboardRefs
+func (d *DashboardRefs) Scan(value interface{}) error {
+	if value == nil {
+		*d = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, d)
+}
+
+// Value implements driver.Valuer for DashboardRefs
+func (d DashboardRefs) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(d)
+}
+
+// MaxBurstValues caps the number of reports a bursting schedule renders per run
+const MaxBurstValues = 100
+
+// Burst fans a schedule out into one report per value of a dashboard variable. Each run renders
+// the dashboard once per value, with the variable set to that value, and emails the report to the
+// value's recipients. Every report is recorded as a child run of the schedule's run.
+type Burst struct {
+	Variable string       `json:"variable"`
+	Values   []BurstValue `json:"values"`
+}
+
+// BurstValue maps a variable value to the recipients of its report
+type BurstValue struct {
+	Value      string     `json:"value"`
+	Recipients Recipients `json:"recipients"`
+}
+
+// Find returns the entry for a variable value, or nil if the value is not configured
+func (b *Burst) Find(value string) *BurstValue {
+	for i := range b.Values {
+		if b.Values[i].Value == value {
+			return &b.Values[i]
+		}
+	}
+	return nil
+}
+
+// Scan implements sql.Scanner for Burst
+func (b *Burst) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, b)
+}
+
+// Value implements driver.Valuer for Burst
+func (b Burst) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+// Artifact content types
+const (
+	ContentTypePDF  = "application/pdf"
+	ContentTypePNG  = "image/png"
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypeZIP  = "application/zip"
+	ContentTypeCSV  = "text/csv"
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// FormatContentType returns the content type produced by a report format
+func FormatContentType(format string) string {
+	switch format {
+	case FormatPNG:
+		return ContentTypePNG
+	case FormatJPEG:
+		return ContentTypeJPEG
+	default:
+		return ContentTypePDF
+	}
+}
+
+// ArtifactExtension returns the file extension (with dot) used for an artifact content type
+func ArtifactExtension(contentType string) string {
+	switch contentType {
+	case ContentTypePNG:
+		return ".png"
+	case ContentTypeJPEG:
+		return ".jpg"
+	case ContentTypeZIP:
+		return ".zip"
+	case ContentTypeCSV:
+		return ".csv"
+	case ContentTypeXLSX:
+		return ".xlsx"
+	default:
+		return ".pdf"
+	}
+}
+
+// PDF layout modes
+const (
+	// LayoutSingle prints the whole dashboard onto one page sized to its content
+	LayoutSingle = "single"
+	// LayoutPaginated captures the dashboard row by row and lays it onto standard paper pages
+	LayoutPaginated = "paginated"
+)
+
+// Grafana themes dashboards are rendered in. Other Grafana theme IDs (e.g. "tron") are accepted as well.
+const (
+	ThemeLight = "light"
+	ThemeDark  = "dark"
+)
+
 // Recipients holds email recipient information
 type Recipients struct {
 	To  []string `json:"to"`
@@ -41,32 +259,149 @@ type Recipients struct {
 
 // Run represents a report execution
 type Run struct {
-	ID            int64      `json:"id"`
-	ScheduleID    int64      `json:"schedule_id"`
-	OrgID         int64      `json:"org_id"`
-	StartedAt     time.Time  `json:"started_at"`
-	FinishedAt    *time.Time `json:"finished_at,omitempty"`
-	Status        string     `json:"status"`
-	EmailSent     bool       `json:"email_sent"`           // Tracks whether email was sent successfully
-	EmailError    string     `json:"email_error,omitempty"` // Stores email sending error if any
-	ErrorText     string     `json:"error_text,omitempty"`
-	ArtifactPath  string     `json:"artifact_path,omitempty"` // DEPRECATED: Kept for backward compatibility, use ArtifactData instead
-	ArtifactData  []byte     `json:"-"`                       // PDF content stored as BLOB (not exposed in JSON API)
-	RenderedPages int        `json:"rendered_pages"`
-	Bytes         int64      `json:"bytes"`
-	Checksum      string     `json:"checksum,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
+	ID             int64           `json:"id"`
+	ScheduleID     int64           `json:"schedule_id"`
+	OrgID          int64           `json:"org_id"`
+	StartedAt      time.Time       `json:"started_at"`
+	FinishedAt     *time.Time      `json:"finished_at,omitempty"`
+	Status         string          `json:"status"`
+	EmailSent      bool            `json:"email_sent"`            // Tracks whether email was sent successfully
+	EmailError     string          `json:"email_error,omitempty"` // Stores email sending error if any
+	ErrorText      string          `json:"error_text,omitempty"`
+	ArtifactPath   string          `json:"artifact_path,omitempty"`   // DEPRECATED: Kept for backward compatibility, use ArtifactData instead
+	ArtifactData   []byte          `json:"-"`                         // Report content stored as BLOB (not exposed in JSON API)
+	ContentType    string          `json:"content_type,omitempty"`    // Content type of the artifact (PDF when empty)
+	DeliveryMode   string          `json:"delivery_mode,omitempty"`   // How the report was delivered by email (see Delivery* constants)
+	StorageBackend string          `json:"storage_backend,omitempty"` // Backend holding the artifact (database when empty)
+	StorageKey     string          `json:"-"`                         // Location of the artifact in an external backend
+	Deliveries     DeliveryResults `json:"deliveries,omitempty"`      // Outcome of each delivery channel, kept in the deliveries table
+	ParentRunID    *int64          `json:"parent_run_id,omitempty"`   // Run of a bursting schedule this report belongs to
+	BurstValue     string          `json:"burst_value,omitempty"`     // Variable value the report was rendered for
+	RangeFrom      *time.Time      `json:"range_from,omitempty"`      // Start of the time range resolved for the run
+	RangeTo        *time.Time      `json:"range_to,omitempty"`        // End of the time range resolved for the run
+	RenderedPages  int             `json:"rendered_pages"`
+	Bytes          int64           `json:"bytes"`
+	Checksum       string          `json:"checksum,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// rangeTimeLayout formats resolved time ranges for people
+const rangeTimeLayout = "2006-01-02 15:04"
+
+// TimeRangeText formats the run's resolved time range in the timezone (UTC when it is invalid),
+// e.g. "2024-03-04 00:00 to 2024-03-10 23:59 CET". It returns "" when the run has no resolved range.
+func (r *Run) TimeRangeText(timezone string) string {
+	if r.RangeFrom == nil || r.RangeTo == nil {
+		return ""
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return r.RangeFrom.In(loc).Format(rangeTimeLayout) + " to " + r.RangeTo.In(loc).Format(rangeTimeLayout+" MST")
+}
+
+// Email delivery modes recorded on runs
+const (
+	DeliveryAttachment = "attachment" // Files attached as rendered
+	DeliveryCompressed = "compressed" // Files zipped to fit Limits.MaxAttachmentSizeMB
+	DeliveryLink       = "link"       // Files too large to attach; the email links to the artifact download
+)
+
+// Delivery target types
+const (
+	TargetEmail   = "email" // Schedule recipients; not configured as a target
+	TargetSlack   = "slack"
+	TargetTeams   = "teams"
+	TargetWebhook = "webhook"
+	TargetSFTP    = "sftp"
+	TargetShare   = "share"
+)
+
+// MaxDeliveryRetries caps DeliveryTarget.Retries
+const MaxDeliveryRetries = 5
+
+// DeliveryTarget is a destination a schedule's reports are delivered to besides email recipients
+type DeliveryTarget struct {
+	Type    string         `json:"type"`
+	Slack   *SlackTarget   `json:"slack,omitempty"`
+	Teams   *TeamsTarget   `json:"teams,omitempty"`
+	Webhook *WebhookTarget `json:"webhook,omitempty"`
+	SFTP    *SFTPTarget    `json:"sftp,omitempty"`
+	Share   *ShareTarget   `json:"share,omitempty"`
+	Retries int            `json:"retries,omitempty"` // Times a failed delivery is retried, with backoff
+}
+
+// SlackTarget uploads reports to a Slack channel
+type SlackTarget struct {
+	Token   string `json:"token"`   // Bot token (xoxb-...) with the files:write scope
+	Channel string `json:"channel"` // Channel ID (e.g. C0123456789); the bot must be a member
 }
 
+// TeamsTarget posts a card linking to the report to a Microsoft Teams incoming webhook
+type TeamsTarget struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+// WebhookTarget POSTs a JSON description of the report to an HTTP endpoint
+type WebhookTarget struct {
+	URL            string `json:"url"`
+	Secret         string `json:"secret,omitempty"`          // Signs payloads with HMAC-SHA256 when set
+	IncludeContent bool   `json:"include_content,omitempty"` // Embed the report base64-encoded besides linking it
+}
+
+// DefaultDeliveryPath is the file path template used when a file target doesn't set one
+const DefaultDeliveryPath = "{{schedule.name}}/{{run.timestamp}}{{file.ext}}"
+
+// SFTPTarget uploads report files to an SFTP server
+type SFTPTarget struct {
+	Host       string `json:"host"`
+	Port       int    `json:"port,omitempty"` // Defaults to 22
+	Username   string `json:"username"`
+	Password   string `json:"password,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"` // PEM private key; preferred over the password when both are set
+	Passphrase string `json:"passphrase,omitempty"`  // Decrypts the private key
+	HostKey    string `json:"host_key,omitempty"`    // Expected server key in authorized_keys format; required
+	Path       string `json:"path,omitempty"`        // Remote path template (DefaultDeliveryPath when empty); relative paths start at the login directory
+}
+
+// ShareTarget copies report files to a network share mounted on the Grafana server.
+// The resolved path must be inside one of the directories allowed by GF_PLUGIN_SHARE_PATHS.
+type ShareTarget struct {
+	Path string `json:"path"` // Absolute path template
+}
+
+// DeliveryTargets is a list of delivery targets stored as JSON
+type DeliveryTargets []DeliveryTarget
+
+// DeliveryResult records the outcome of delivering a run to one channel
+type DeliveryResult struct {
+	ID          int64     `json:"id,omitempty"` // Row in the deliveries table
+	Index       int       `json:"index"`        // Position of the target in Schedule.Targets; EmailTargetIndex for email
+	Type        string    `json:"type"`         // Target type (email, slack, ...)
+	Target      string    `json:"target"`       // Destination, e.g. the Slack channel
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+	Attempts    int       `json:"attempts,omitempty"` // Delivery attempts, including retries
+	DeliveredAt time.Time `json:"delivered_at"`
+}
+
+// EmailTargetIndex is the DeliveryResult.Index of the email recipients, which are not in Schedule.Targets
+const EmailTargetIndex = -1
+
+// DeliveryResults is a list of delivery results
+type DeliveryResults []DeliveryResult
+
 // Template represents a report template
 type Template struct {
-	ID        int64          `json:"id"`
-	OrgID     int64          `json:"org_id"`
-	Name      string         `json:"name"`
-	Kind      string         `json:"kind"`
-	Config    TemplateConfig `json:"config"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID         int64          `json:"id"`
+	OrgID      int64          `json:"org_id"`
+	Name       string         `json:"name"`
+	Kind       string         `json:"kind"`
+	Config     TemplateConfig `json:"config"`
+	OwnerLogin string         `json:"owner_login,omitempty"` // Login of the user who created the template
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
 }
 
 // TemplateConfig holds template configuration
@@ -78,9 +413,14 @@ type TemplateConfig struct {
 	PageSize    string   `json:"page_size,omitempty"`
 	Orientation string   `json:"orientation,omitempty"`
 	Margins     *Margins `json:"margins,omitempty"`
+	// PrintBackground overrides whether page backgrounds are printed (default: all themes but dark)
+	PrintBackground *bool `json:"print_background,omitempty"`
+	// CoverPage and TableOfContents add front matter to PDF reports; combined reports always have both
+	CoverPage       bool `json:"cover_page,omitempty"`
+	TableOfContents bool `json:"table_of_contents,omitempty"`
 }
 
-// Margins holds page margin configuration
+// Margins holds page margin configuration in millimeters
 type Margins struct {
 	Top    float64 `json:"top"`
 	Bottom float64 `json:"bottom"`
@@ -95,6 +435,7 @@ type Settings struct {
 	SMTPConfig     *SMTPConfig    `json:"smtp_config,omitempty"`
 	RendererConfig RendererConfig `json:"renderer_config"`
 	Limits         Limits         `json:"limits"`
+	Storage        StorageConfig  `json:"storage"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 }
@@ -133,10 +474,187 @@ type Limits struct {
 	MaxRecipients        int      `json:"max_recipients"`
 	MaxAttachmentSizeMB  int      `json:"max_attachment_size_mb"`
 	MaxConcurrentRenders int      `json:"max_concurrent_renders"`
-	RetentionDays        int      `json:"retention_days"`
+	RetentionDays        int      `json:"retention_days"`            // Artifacts of older runs are removed; 0 keeps them forever
+	KeepLastRuns         int      `json:"keep_last_runs,omitempty"`  // Runs per schedule that keep their artifacts regardless of age
 	AllowedDomains       []string `json:"allowed_domains,omitempty"` // If empty, all domains are allowed
 }
 
+// Artifact storage backends
+const (
+	StorageDatabase   = "database"
+	StorageFilesystem = "filesystem"
+	StorageS3         = "s3"
+)
+
+// StorageConfig selects where run artifacts are stored
+type StorageConfig struct {
+	Backend string    `json:"backend,omitempty"` // database (default), filesystem or s3
+	S3      *S3Config `json:"s3,omitempty"`
+}
+
+// S3Config holds the bucket used by the s3 storage backend (AWS or any S3-compatible service)
+type S3Config struct {
+	Endpoint        string `json:"endpoint"`         // e.g. https://s3.eu-west-1.amazonaws.com or http://minio:9000
+	Region          string `json:"region,omitempty"` // Defaults to us-east-1
+	Bucket          string `json:"bucket"`
+	Prefix          string `json:"prefix,omitempty"` // Key prefix inside the bucket
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	UsePathStyle    bool   `json:"use_path_style"` // Address the bucket in the path (MinIO and most S3-compatible services)
+	SkipTLSVerify   bool   `json:"skip_tls_verify"`
+}
+
+// SecretUnchanged is returned by the API in place of stored secrets. Sent back in an update it keeps
+// the stored secret; an empty value removes it.
+const SecretUnchanged = "__unchanged__"
+
+// Secrets returns the secrets of the settings by JSON path. They are encrypted at rest and never
+// returned by the API.
+func (s *Settings) Secrets() map[string]*string {
+	secrets := make(map[string]*string)
+	if s.SMTPConfig != nil {
+		secrets["smtp_config.password"] = &s.SMTPConfig.Password
+	}
+	if s.Storage.S3 != nil {
+		secrets["storage.s3.secret_access_key"] = &s.Storage.S3.SecretAccessKey
+	}
+	return secrets
+}
+
+// MaskSecrets replaces the secrets that are set with SecretUnchanged
+func (s *Settings) MaskSecrets() {
+	maskSecrets(s.Secrets())
+}
+
+// KeepSecrets replaces SecretUnchanged with the matching secret of the stored settings, which may be nil
+func (s *Settings) KeepSecrets(stored *Settings) {
+	var storedSecrets map[string]*string
+	if stored != nil {
+		storedSecrets = stored.Secrets()
+	}
+	keepSecrets(s.Secrets(), storedSecrets)
+}
+
+// Secrets returns the credentials of the schedule's delivery targets by JSON path (e.g.
+// "targets.0.slack.token"). Like settings secrets, they are encrypted at rest and never returned by the API.
+func (s *Schedule) Secrets() map[string]*string {
+	secrets := make(map[string]*string)
+	for i := range s.Targets {
+		prefix := "targets." + strconv.Itoa(i) + "."
+		for path, secret := range s.Targets[i].secrets() {
+			secrets[prefix+path] = secret
+		}
+	}
+	return secrets
+}
+
+// MaskSecrets replaces the target secrets that are set with SecretUnchanged
+func (s *Schedule) MaskSecrets() {
+	maskSecrets(s.Secrets())
+}
+
+// KeepSecrets replaces SecretUnchanged in the targets with the secrets of the stored schedule, which may
+// be nil. A secret is only kept when the stored target at the same position has the same type and
+// destination, so removing or reordering targets never hands a credential to another destination.
+func (s *Schedule) KeepSecrets(stored *Schedule) {
+	for i := range s.Targets {
+		target := &s.Targets[i]
+		var storedSecrets map[string]*string
+		if stored != nil && i < len(stored.Targets) {
+			previous := &stored.Targets[i]
+			if previous.Type == target.Type && previous.destination() == target.destination() {
+				storedSecrets = previous.secrets()
+			}
+		}
+		keepSecrets(target.secrets(), storedSecrets)
+	}
+}
+
+// secrets returns the credentials of the target by JSON path below it
+func (t *DeliveryTarget) secrets() map[string]*string {
+	secrets := make(map[string]*string)
+	if t.Slack != nil {
+		secrets["slack.token"] = &t.Slack.Token
+	}
+	if t.Teams != nil {
+		secrets["teams.webhook_url"] = &t.Teams.WebhookURL
+	}
+	if t.Webhook != nil {
+		secrets["webhook.secret"] = &t.Webhook.Secret
+	}
+	if t.SFTP != nil {
+		secrets["sftp.password"] = &t.SFTP.Password
+		secrets["sftp.private_key"] = &t.SFTP.PrivateKey
+		secrets["sftp.passphrase"] = &t.SFTP.Passphrase
+	}
+	return secrets
+}
+
+// destination describes where the target delivers, from its settings that aren't secret.
+// A Teams webhook URL is both, so Teams targets have none.
+func (t *DeliveryTarget) destination() string {
+	switch {
+	case t.Slack != nil:
+		return t.Slack.Channel
+	case t.Webhook != nil:
+		return t.Webhook.URL
+	case t.SFTP != nil:
+		return t.SFTP.Username + "@" + t.SFTP.Host + ":" + strconv.Itoa(t.SFTP.Port)
+	}
+	return ""
+}
+
+// maskSecrets replaces the secrets that are set with SecretUnchanged
+func maskSecrets(secrets map[string]*string) {
+	for _, secret := range secrets {
+		if *secret != "" {
+			*secret = SecretUnchanged
+		}
+	}
+}
+
+// keepSecrets replaces SecretUnchanged with the stored secret at the same path, or removes it
+func keepSecrets(secrets, stored map[string]*string) {
+	for path, secret := range secrets {
+		if *secret != SecretUnchanged {
+			continue
+		}
+		*secret = ""
+		if previous, ok := stored[path]; ok {
+			*secret = *previous
+		}
+	}
+}
+
+// Audit event actions
+const (
+	AuditScheduleCreate   = "schedule.create"
+	AuditScheduleUpdate   = "schedule.update"
+	AuditScheduleDelete   = "schedule.delete"
+	AuditScheduleRun      = "schedule.run"
+	AuditSettingsUpdate   = "settings.update"
+	AuditArtifactDownload = "run.download"
+)
+
+// Audited resource types
+const (
+	AuditResourceSchedule = "schedule"
+	AuditResourceSettings = "settings"
+	AuditResourceRun      = "run"
+)
+
+// AuditEvent records an action a user took through the API
+type AuditEvent struct {
+	ID           int64           `json:"id"`
+	OrgID        int64           `json:"org_id"`
+	UserLogin    string          `json:"user_login"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   int64           `json:"resource_id,omitempty"` // Schedule or run ID; settings have none
+	Diff         json.RawMessage `json:"diff,omitempty"`        // Changed JSON paths with their old and new values, see AuditDiff
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
 // VariableOption represents a single option for a dashboard variable
 type VariableOption struct {
 	Text  string `json:"text"`
@@ -333,3 +851,41 @@ func (l *Limits) Scan(value interface{}) error {
 func (l Limits) Value() (driver.Value, error) {
 	return json.Marshal(l)
 }
+
+// Scan implements sql.Scanner for StorageConfig
+func (c *StorageConfig) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// Value implements driver.Valuer for StorageConfig
+func (c StorageConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner for DeliveryTargets
+func (t *DeliveryTargets) Scan(value interface{}) error {
+	if value == nil {
+		*t = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+// Value implements driver.Valuer for DeliveryTargets
+func (t DeliveryTargets) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(t)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/gorhill/cronexpr"
@@ -44,6 +45,28 @@ func ValidateRecipientDomains(recipients Recipients, allowedDomains []string) er
 	return nil
 }
 
+// ValidateRecipientCount validates that the total number of To, CC and BCC recipients does not exceed maxRecipients.
+// A maxRecipients of zero or less means no limit.
+func ValidateRecipientCount(recipients Recipients, maxRecipients int) error {
+	if maxRecipients <= 0 {
+		return nil
+	}
+
+	count := 0
+	for _, list := range [][]string{recipients.To, recipients.CC, recipients.BCC} {
+		for _, email := range list {
+			if strings.TrimSpace(email) != "" {
+				count++
+			}
+		}
+	}
+
+	if count > maxRecipients {
+		return fmt.Errorf("too many recipients: %d exceeds the limit of %d", count, maxRecipients)
+	}
+	return nil
+}
+
 // extractDomain extracts the domain part from an email address
 func extractDomain(email string) string {
 	parts := strings.Split(email, "@")
@@ -92,3 +115,279 @@ func ValidateCronExpression(cronExpr string) error {
 
 	return nil
 }
+
+// Supported template page sizes and orientations
+var (
+	validPageSizes    = []string{"A3", "A4", "A5", "Letter", "Legal"}
+	validOrientations = []string{"portrait", "landscape"}
+	validTemplateKind = []string{"pdf", "html", "email"}
+	validLayouts      = []string{LayoutSingle, LayoutPaginated}
+	validFormats      = []string{FormatPDF, FormatPNG, FormatJPEG}
+	validDataFormats  = []string{DataFormatCSV, DataFormatXLSX}
+	validReducers     = []string{ReducerLast, ReducerMin, ReducerMax, ReducerMean, ReducerSum, ReducerCount}
+	validOperators    = []string{">", ">=", "<", "<=", "==", "!="}
+)
+
+// maxMarginMM is the largest margin (in millimeters) accepted on any page side
+const maxMarginMM = 100.0
+
+// ValidateScheduleFormat validates the output format of a schedule.
+// An empty format is allowed and falls back to PDF.
+func ValidateScheduleFormat(format string) error {
+	if format != "" && !containsString(validFormats, format) {
+		return fmt.Errorf("invalid format '%s'. Allowed formats: %v", format, validFormats)
+	}
+	return nil
+}
+
+// ValidateDataExport validates the data export settings of a schedule.
+// A data-only schedule must select a data format, otherwise it would produce nothing.
+func ValidateDataExport(dataFormat string, dataOnly bool) error {
+	if dataFormat != "" && !containsString(validDataFormats, dataFormat) {
+		return fmt.Errorf("invalid data format '%s'. Allowed data formats: %v", dataFormat, validDataFormats)
+	}
+	if dataOnly && dataFormat == "" {
+		return fmt.Errorf("data_only requires a data_format")
+	}
+	return nil
+}
+
+// ValidateCondition validates a schedule's delivery condition; nil means always deliver
+func ValidateCondition(condition *Condition) error {
+	if condition == nil {
+		return nil
+	}
+	if strings.TrimSpace(condition.DatasourceUID) == "" {
+		return fmt.Errorf("condition requires a data source")
+	}
+	if len(condition.Query) == 0 {
+		return fmt.Errorf("condition requires a query")
+	}
+	if !containsString(validReducers, condition.Reducer) {
+		return fmt.Errorf("invalid condition reducer '%s'. Allowed reducers: %v", condition.Reducer, validReducers)
+	}
+	if !containsString(validOperators, condition.Operator) {
+		return fmt.Errorf("invalid condition operator '%s'. Allowed operators: %v", condition.Operator, validOperators)
+	}
+	return nil
+}
+
+// ValidateDashboards validates the dashboards of a multi-dashboard report, which is always a PDF
+func ValidateDashboards(dashboards DashboardRefs, format string) error {
+	if len(dashboards) == 0 {
+		return nil
+	}
+	if len(dashboards) > MaxReportDashboards {
+		return fmt.Errorf("report has %d dashboards, maximum allowed is %d", len(dashboards), MaxReportDashboards)
+	}
+	if format != "" && format != FormatPDF {
+		return fmt.Errorf("reports with multiple dashboards must use the %s format", FormatPDF)
+	}
+	for i, dashboard := range dashboards {
+		if strings.TrimSpace(dashboard.UID) == "" {
+			return fmt.Errorf("dashboard %d: uid is required", i+1)
+		}
+		if (dashboard.RangeFrom == "") != (dashboard.RangeTo == "") {
+			return fmt.Errorf("dashboard %d: time range needs both from and to", i+1)
+		}
+	}
+	return nil
+}
+
+// ValidateBurst validates a schedule's bursting configuration; nil means one report per run.
+// Recipient domains and counts are checked per value with the recipient validators.
+func ValidateBurst(burst *Burst) error {
+	if burst == nil {
+		return nil
+	}
+	if strings.TrimSpace(burst.Variable) == "" {
+		return fmt.Errorf("burst requires a variable")
+	}
+	if len(burst.Values) == 0 {
+		return fmt.Errorf("burst requires at least one value")
+	}
+	if len(burst.Values) > MaxBurstValues {
+		return fmt.Errorf("burst has %d values, maximum allowed is %d", len(burst.Values), MaxBurstValues)
+	}
+
+	seen := make(map[string]bool)
+	for _, value := range burst.Values {
+		if value.Value == "" {
+			return fmt.Errorf("burst values must not be empty")
+		}
+		if seen[value.Value] {
+			return fmt.Errorf("burst value '%s' is listed more than once", value.Value)
+		}
+		seen[value.Value] = true
+		if len(value.Recipients.To) == 0 {
+			return fmt.Errorf("burst value '%s' has no recipients", value.Value)
+		}
+	}
+	return nil
+}
+
+// ValidateScheduleLayout validates the PDF layout mode of a schedule.
+// An empty layout is allowed and falls back to the single-page layout.
+func ValidateScheduleLayout(layout string) error {
+	if layout != "" && !containsString(validLayouts, layout) {
+		return fmt.Errorf("invalid layout '%s'. Allowed layouts: %v", layout, validLayouts)
+	}
+	return nil
+}
+
+// maxThemeLength bounds the length of a Grafana theme ID
+const maxThemeLength = 50
+
+// ValidateScheduleTheme validates the Grafana theme of a schedule: "light", "dark" or the ID of
+// another Grafana theme, which consists of lowercase letters, digits, dashes and underscores.
+// An empty theme is allowed and falls back to the light theme.
+func ValidateScheduleTheme(theme string) error {
+	if len(theme) > maxThemeLength {
+		return fmt.Errorf("theme is too long, maximum length is %d", maxThemeLength)
+	}
+	for _, c := range theme {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return fmt.Errorf("invalid theme '%s'. Use light, dark or a Grafana theme ID", theme)
+		}
+	}
+	return nil
+}
+
+// ValidateTemplate validates a report template before it is persisted.
+// Empty PageSize, Orientation and Kind are allowed and fall back to renderer defaults.
+func ValidateTemplate(template *Template) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return fmt.Errorf("template name cannot be empty")
+	}
+
+	if template.Kind != "" && !containsString(validTemplateKind, template.Kind) {
+		return fmt.Errorf("invalid template kind '%s'. Allowed kinds: %v", template.Kind, validTemplateKind)
+	}
+
+	return ValidateTemplateConfig(template.Config)
+}
+
+// ValidateTemplateConfig validates page size, orientation and margins of a template configuration
+func ValidateTemplateConfig(config TemplateConfig) error {
+	if config.PageSize != "" && !containsString(validPageSizes, config.PageSize) {
+		return fmt.Errorf("invalid page size '%s'. Allowed page sizes: %v", config.PageSize, validPageSizes)
+	}
+
+	if config.Orientation != "" && !containsString(validOrientations, config.Orientation) {
+		return fmt.Errorf("invalid orientation '%s'. Allowed orientations: %v", config.Orientation, validOrientations)
+	}
+
+	if config.Margins != nil {
+		sides := map[string]float64{
+			"top":    config.Margins.Top,
+			"bottom": config.Margins.Bottom,
+			"left":   config.Margins.Left,
+			"right":  config.Margins.Right,
+		}
+		for side, value := range sides {
+			if value < 0 || value > maxMarginMM {
+				return fmt.Errorf("invalid %s margin %.1fmm: must be between 0 and %.0fmm", side, value, maxMarginMM)
+			}
+		}
+	}
+
+	return nil
+}
+
+// containsString reports whether value is present in list
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateStorageConfig validates the artifact storage settings.
+// An empty backend selects the database.
+func ValidateStorageConfig(config StorageConfig) error {
+	switch config.Backend {
+	case "", StorageDatabase, StorageFilesystem:
+		return nil
+	case StorageS3:
+		if config.S3 == nil {
+			return fmt.Errorf("s3 storage requires bucket settings")
+		}
+		if strings.TrimSpace(config.S3.Endpoint) == "" {
+			return fmt.Errorf("s3 endpoint cannot be empty")
+		}
+		if strings.TrimSpace(config.S3.Bucket) == "" {
+			return fmt.Errorf("s3 bucket cannot be empty")
+		}
+		if config.S3.AccessKeyID == "" || config.S3.SecretAccessKey == "" {
+			return fmt.Errorf("s3 access key ID and secret access key are required")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid storage backend '%s'. Must be one of: %s, %s, %s", config.Backend, StorageDatabase, StorageFilesystem, StorageS3)
+	}
+}
+
+// ValidateDeliveryTargets validates the delivery targets of a schedule
+func ValidateDeliveryTargets(targets DeliveryTargets) error {
+	for i, target := range targets {
+		switch target.Type {
+		case TargetSlack:
+			if target.Slack == nil || strings.TrimSpace(target.Slack.Token) == "" || strings.TrimSpace(target.Slack.Channel) == "" {
+				return fmt.Errorf("target %d: slack requires a bot token and channel ID", i+1)
+			}
+		case TargetTeams:
+			if target.Teams == nil || !isHTTPURL(target.Teams.WebhookURL) {
+				return fmt.Errorf("target %d: teams requires an http(s) webhook URL", i+1)
+			}
+		case TargetWebhook:
+			if target.Webhook == nil || !isHTTPURL(target.Webhook.URL) {
+				return fmt.Errorf("target %d: webhook requires an http(s) URL", i+1)
+			}
+		case TargetSFTP:
+			if err := validateSFTPTarget(target.SFTP); err != nil {
+				return fmt.Errorf("target %d: %w", i+1, err)
+			}
+		case TargetShare:
+			if target.Share == nil || !strings.HasPrefix(target.Share.Path, "/") {
+				return fmt.Errorf("target %d: share requires an absolute path", i+1)
+			}
+			if strings.Contains(target.Share.Path, "..") {
+				return fmt.Errorf("target %d: share path must not contain '..'", i+1)
+			}
+		default:
+			return fmt.Errorf("target %d: invalid type '%s'. Must be one of: %s, %s, %s, %s, %s", i+1, target.Type, TargetSlack, TargetTeams, TargetWebhook, TargetSFTP, TargetShare)
+		}
+		if target.Retries < 0 || target.Retries > MaxDeliveryRetries {
+			return fmt.Errorf("target %d: retries must be between 0 and %d", i+1, MaxDeliveryRetries)
+		}
+	}
+	return nil
+}
+
+// validateSFTPTarget checks that an SFTP target has a server and credentials
+func validateSFTPTarget(target *SFTPTarget) error {
+	if target == nil || strings.TrimSpace(target.Host) == "" || strings.TrimSpace(target.Username) == "" {
+		return fmt.Errorf("sftp requires a host and username")
+	}
+	if target.Port < 0 || target.Port > 65535 {
+		return fmt.Errorf("sftp port must be between 1 and 65535")
+	}
+	if target.Password == "" && strings.TrimSpace(target.PrivateKey) == "" {
+		return fmt.Errorf("sftp requires a password or private key")
+	}
+	if strings.TrimSpace(target.HostKey) == "" {
+		return fmt.Errorf("sftp requires the server's host key")
+	}
+	if strings.Contains(target.Path, "..") {
+		return fmt.Errorf("sftp path must not contain '..'")
+	}
+	return nil
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
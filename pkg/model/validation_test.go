@@ -1,6 +1,10 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -150,6 +154,34 @@ func TestValidateRecipientDomains(t *testing.T) {
 	}
 }
 
+func TestValidateRecipientCount(t *testing.T) {
+	recipients := Recipients{
+		To:  []string{"a@example.com", "b@example.com"},
+		CC:  []string{"c@example.com", " "},
+		BCC: []string{"d@example.com"},
+	}
+
+	tests := []struct {
+		name          string
+		maxRecipients int
+		wantErr       bool
+	}{
+		{name: "no limit", maxRecipients: 0, wantErr: false},
+		{name: "under limit", maxRecipients: 10, wantErr: false},
+		{name: "at limit ignores blank entries", maxRecipients: 4, wantErr: false},
+		{name: "over limit counts cc and bcc", maxRecipients: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRecipientCount(recipients, tt.maxRecipients)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRecipientCount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestExtractDomain(t *testing.T) {
 	tests := []struct {
 		email          string
@@ -305,6 +337,88 @@ func TestValidateCronExpression(t *testing.T) {
 	}
 }
 
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name          string
+		template      Template
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:        "valid - defaults",
+			template:    Template{Name: "Default"},
+			expectError: false,
+		},
+		{
+			name: "valid - full configuration",
+			template: Template{
+				Name: "Branded",
+				Kind: "pdf",
+				Config: TemplateConfig{
+					PageSize:    "Letter",
+					Orientation: "portrait",
+					Margins:     &Margins{Top: 15, Bottom: 15, Left: 10, Right: 10},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:          "invalid - empty name",
+			template:      Template{Name: "  "},
+			expectError:   true,
+			errorContains: "name cannot be empty",
+		},
+		{
+			name:          "invalid - unknown kind",
+			template:      Template{Name: "Bad", Kind: "docx"},
+			expectError:   true,
+			errorContains: "invalid template kind",
+		},
+		{
+			name:          "invalid - page size",
+			template:      Template{Name: "Bad", Config: TemplateConfig{PageSize: "B5"}},
+			expectError:   true,
+			errorContains: "invalid page size",
+		},
+		{
+			name:          "invalid - orientation",
+			template:      Template{Name: "Bad", Config: TemplateConfig{Orientation: "sideways"}},
+			expectError:   true,
+			errorContains: "invalid orientation",
+		},
+		{
+			name:          "invalid - negative margin",
+			template:      Template{Name: "Bad", Config: TemplateConfig{Margins: &Margins{Top: -1}}},
+			expectError:   true,
+			errorContains: "invalid top margin",
+		},
+		{
+			name:          "invalid - margin too large",
+			template:      Template{Name: "Bad", Config: TemplateConfig{Margins: &Margins{Left: 150}}},
+			expectError:   true,
+			errorContains: "invalid left margin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplate(&tt.template)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				} else if tt.errorContains != "" && !contains(err.Error(), tt.errorContains) {
+					t.Errorf("error message '%s' does not contain '%s'", err.Error(), tt.errorContains)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsSubstring(s, substr))
@@ -318,3 +432,348 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestValidateScheduleLayout(t *testing.T) {
+	tests := []struct {
+		layout      string
+		expectError bool
+	}{
+		{layout: "", expectError: false},
+		{layout: LayoutSingle, expectError: false},
+		{layout: LayoutPaginated, expectError: false},
+		{layout: "grid", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			err := ValidateScheduleLayout(tt.layout)
+			if (err != nil) != tt.expectError {
+				t.Errorf("ValidateScheduleLayout(%q) error = %v, expectError %v", tt.layout, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateScheduleTheme(t *testing.T) {
+	tests := []struct {
+		theme       string
+		expectError bool
+	}{
+		{theme: "", expectError: false},
+		{theme: ThemeLight, expectError: false},
+		{theme: ThemeDark, expectError: false},
+		{theme: "sapphire_dusk-2", expectError: false},
+		{theme: "Dark", expectError: true},
+		{theme: "dark&orgId=2", expectError: true},
+		{theme: strings.Repeat("a", 51), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.theme, func(t *testing.T) {
+			err := ValidateScheduleTheme(tt.theme)
+			if (err != nil) != tt.expectError {
+				t.Errorf("ValidateScheduleTheme(%q) error = %v, expectError %v", tt.theme, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateScheduleFormat(t *testing.T) {
+	tests := []struct {
+		format      string
+		expectError bool
+	}{
+		{format: "", expectError: false},
+		{format: FormatPDF, expectError: false},
+		{format: FormatPNG, expectError: false},
+		{format: FormatJPEG, expectError: false},
+		{format: "gif", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			err := ValidateScheduleFormat(tt.format)
+			if (err != nil) != tt.expectError {
+				t.Errorf("ValidateScheduleFormat(%q) error = %v, expectError %v", tt.format, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateDataExport(t *testing.T) {
+	tests := []struct {
+		name        string
+		dataFormat  string
+		dataOnly    bool
+		expectError bool
+	}{
+		{name: "no export", expectError: false},
+		{name: "csv", dataFormat: DataFormatCSV, expectError: false},
+		{name: "xlsx only", dataFormat: DataFormatXLSX, dataOnly: true, expectError: false},
+		{name: "unknown format", dataFormat: "json", expectError: true},
+		{name: "data only without format", dataOnly: true, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDataExport(tt.dataFormat, tt.dataOnly)
+			if (err != nil) != tt.expectError {
+				t.Errorf("ValidateDataExport(%q, %v) error = %v, expectError %v", tt.dataFormat, tt.dataOnly, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateStorageConfig(t *testing.T) {
+	validS3 := &S3Config{Endpoint: "http://minio:9000", Bucket: "reports", AccessKeyID: "key", SecretAccessKey: "secret"}
+
+	tests := []struct {
+		name    string
+		config  StorageConfig
+		wantErr string
+	}{
+		{name: "default database", config: StorageConfig{}},
+		{name: "filesystem", config: StorageConfig{Backend: StorageFilesystem}},
+		{name: "s3", config: StorageConfig{Backend: StorageS3, S3: validS3}},
+		{name: "s3 without settings", config: StorageConfig{Backend: StorageS3}, wantErr: "bucket settings"},
+		{name: "s3 without bucket", config: StorageConfig{Backend: StorageS3, S3: &S3Config{Endpoint: "http://minio:9000", AccessKeyID: "key", SecretAccessKey: "secret"}}, wantErr: "bucket cannot be empty"},
+		{name: "s3 without credentials", config: StorageConfig{Backend: StorageS3, S3: &S3Config{Endpoint: "http://minio:9000", Bucket: "reports"}}, wantErr: "access key"},
+		{name: "unknown backend", config: StorageConfig{Backend: "ftp"}, wantErr: "invalid storage backend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStorageConfig(tt.config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateStorageConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateStorageConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSettingsSecrets(t *testing.T) {
+	stored := &Settings{
+		SMTPConfig: &SMTPConfig{Host: "smtp.example.com", Password: "smtp-secret"},
+		Storage:    StorageConfig{Backend: StorageS3, S3: &S3Config{Bucket: "reports", SecretAccessKey: "s3-secret"}},
+	}
+
+	masked := *stored
+	smtp, s3 := *stored.SMTPConfig, *stored.Storage.S3
+	masked.SMTPConfig, masked.Storage.S3 = &smtp, &s3
+	masked.MaskSecrets()
+	if masked.SMTPConfig.Password != SecretUnchanged || masked.Storage.S3.SecretAccessKey != SecretUnchanged {
+		t.Errorf("MaskSecrets() left %q, %q", masked.SMTPConfig.Password, masked.Storage.S3.SecretAccessKey)
+	}
+	if stored.SMTPConfig.Password != "smtp-secret" {
+		t.Error("MaskSecrets() changed the stored settings")
+	}
+
+	// Unset secrets stay empty so the UI can tell them apart
+	empty := &Settings{SMTPConfig: &SMTPConfig{}}
+	empty.MaskSecrets()
+	if empty.SMTPConfig.Password != "" {
+		t.Errorf("MaskSecrets() of an empty password = %q", empty.SMTPConfig.Password)
+	}
+
+	// An update keeps unchanged secrets, replaces new ones and clears removed ones
+	update := &Settings{
+		SMTPConfig: &SMTPConfig{Password: SecretUnchanged},
+		Storage:    StorageConfig{Backend: StorageS3, S3: &S3Config{SecretAccessKey: "new-secret"}},
+	}
+	update.KeepSecrets(stored)
+	if update.SMTPConfig.Password != "smtp-secret" || update.Storage.S3.SecretAccessKey != "new-secret" {
+		t.Errorf("KeepSecrets() = %q, %q", update.SMTPConfig.Password, update.Storage.S3.SecretAccessKey)
+	}
+	update = &Settings{SMTPConfig: &SMTPConfig{Password: ""}}
+	update.KeepSecrets(stored)
+	if update.SMTPConfig.Password != "" {
+		t.Errorf("KeepSecrets() of a removed password = %q", update.SMTPConfig.Password)
+	}
+
+	// The sentinel is never stored, even without previous settings
+	update = &Settings{SMTPConfig: &SMTPConfig{Password: SecretUnchanged}}
+	update.KeepSecrets(nil)
+	if update.SMTPConfig.Password != "" {
+		t.Errorf("KeepSecrets(nil) = %q, want empty", update.SMTPConfig.Password)
+	}
+}
+
+func TestScheduleSecrets(t *testing.T) {
+	newTargets := func() DeliveryTargets {
+		return DeliveryTargets{
+			{Type: TargetSlack, Slack: &SlackTarget{Token: "xoxb-a", Channel: "C1"}},
+			{Type: TargetSlack, Slack: &SlackTarget{Token: "xoxb-b", Channel: "C2"}},
+			{Type: TargetTeams, Teams: &TeamsTarget{WebhookURL: "https://example.webhook.office.com/a"}},
+			{Type: TargetWebhook, Webhook: &WebhookTarget{URL: "https://example.com/hook", Secret: "hmac"}},
+			{Type: TargetSFTP, SFTP: &SFTPTarget{Host: "sftp.example.com", Username: "reports", PrivateKey: "PEM", Passphrase: "pass"}},
+		}
+	}
+	stored := &Schedule{Targets: newTargets()}
+
+	masked := &Schedule{Targets: newTargets()}
+	masked.MaskSecrets()
+	data, _ := json.Marshal(masked.Targets)
+	for _, secret := range []string{"xoxb-a", "xoxb-b", "office.com", "hmac", "PEM", "pass\""} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("MaskSecrets() left %q in %s", secret, data)
+		}
+	}
+	if masked.Targets[4].SFTP.Password != "" || masked.Targets[0].Slack.Channel != "C1" {
+		t.Errorf("MaskSecrets() changed unset secrets or settings: %s", data)
+	}
+
+	// Sent back unchanged, every secret is kept
+	masked.KeepSecrets(stored)
+	if !reflect.DeepEqual(masked.Targets, stored.Targets) {
+		t.Errorf("KeepSecrets() = %+v, want %+v", masked.Targets, stored.Targets)
+	}
+
+	// With the first target removed, the second one's masked token must not pick up the first one's
+	update := &Schedule{Targets: newTargets()[1:]}
+	update.MaskSecrets()
+	update.KeepSecrets(stored)
+	if update.Targets[0].Slack.Token != "" {
+		t.Errorf("KeepSecrets() after removing a target gave channel C2 token %q", update.Targets[0].Slack.Token)
+	}
+
+	// The sentinel is never stored, even for new schedules
+	update = &Schedule{Targets: newTargets()}
+	update.MaskSecrets()
+	update.KeepSecrets(nil)
+	if len(update.Secrets()) == 0 {
+		t.Fatal("Secrets() returned no target secrets")
+	}
+	for path, secret := range update.Secrets() {
+		if *secret != "" {
+			t.Errorf("KeepSecrets(nil) left %s = %q", path, *secret)
+		}
+	}
+}
+
+func TestValidateDeliveryTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets DeliveryTargets
+		wantErr bool
+	}{
+		{name: "none", targets: nil, wantErr: false},
+		{name: "slack", targets: DeliveryTargets{{Type: TargetSlack, Slack: &SlackTarget{Token: "xoxb-1", Channel: "C1"}}}, wantErr: false},
+		{name: "slack without channel", targets: DeliveryTargets{{Type: TargetSlack, Slack: &SlackTarget{Token: "xoxb-1"}}}, wantErr: true},
+		{name: "slack without settings", targets: DeliveryTargets{{Type: TargetSlack}}, wantErr: true},
+		{name: "teams", targets: DeliveryTargets{{Type: TargetTeams, Teams: &TeamsTarget{WebhookURL: "https://example.webhook.office.com/webhookb2/x"}}}, wantErr: false},
+		{name: "teams without URL", targets: DeliveryTargets{{Type: TargetTeams, Teams: &TeamsTarget{}}}, wantErr: true},
+		{name: "webhook", targets: DeliveryTargets{{Type: TargetWebhook, Webhook: &WebhookTarget{URL: "https://hooks.example.com/reports", Secret: "s"}}}, wantErr: false},
+		{name: "webhook with non-HTTP URL", targets: DeliveryTargets{{Type: TargetWebhook, Webhook: &WebhookTarget{URL: "ftp://example.com"}}}, wantErr: true},
+		{name: "sftp with password", targets: DeliveryTargets{{Type: TargetSFTP, SFTP: &SFTPTarget{Host: "sftp.example.com", Username: "reports", Password: "p", HostKey: "ssh-ed25519 AAAA"}, Retries: 3}}, wantErr: false},
+		{name: "sftp without credentials", targets: DeliveryTargets{{Type: TargetSFTP, SFTP: &SFTPTarget{Host: "sftp.example.com", Username: "reports", HostKey: "ssh-ed25519 AAAA"}}}, wantErr: true},
+		{name: "sftp without host key", targets: DeliveryTargets{{Type: TargetSFTP, SFTP: &SFTPTarget{Host: "sftp.example.com", Username: "reports", Password: "p"}}}, wantErr: true},
+		{name: "sftp path escaping", targets: DeliveryTargets{{Type: TargetSFTP, SFTP: &SFTPTarget{Host: "h", Username: "u", PrivateKey: "k", HostKey: "ssh-ed25519 AAAA", Path: "../etc/{{file.name}}"}}}, wantErr: true},
+		{name: "share", targets: DeliveryTargets{{Type: TargetShare, Share: &ShareTarget{Path: "/mnt/finance/{{schedule.name}}/"}}}, wantErr: false},
+		{name: "share with relative path", targets: DeliveryTargets{{Type: TargetShare, Share: &ShareTarget{Path: "finance/"}}}, wantErr: true},
+		{name: "too many retries", targets: DeliveryTargets{{Type: TargetShare, Share: &ShareTarget{Path: "/mnt/x/"}, Retries: MaxDeliveryRetries + 1}}, wantErr: true},
+		{name: "unknown type", targets: DeliveryTargets{{Type: "pager"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDeliveryTargets(tt.targets)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDeliveryTargets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCondition(t *testing.T) {
+	query := map[string]interface{}{"expr": "sum(errors)"}
+	tests := []struct {
+		name      string
+		condition *Condition
+		wantErr   bool
+	}{
+		{name: "none", condition: nil, wantErr: false},
+		{name: "valid", condition: &Condition{DatasourceUID: "prom", Query: query, Reducer: ReducerLast, Operator: ">", Threshold: 0}, wantErr: false},
+		{name: "without data source", condition: &Condition{Query: query, Reducer: ReducerLast, Operator: ">"}, wantErr: true},
+		{name: "without query", condition: &Condition{DatasourceUID: "prom", Reducer: ReducerLast, Operator: ">"}, wantErr: true},
+		{name: "unknown reducer", condition: &Condition{DatasourceUID: "prom", Query: query, Reducer: "median", Operator: ">"}, wantErr: true},
+		{name: "unknown operator", condition: &Condition{DatasourceUID: "prom", Query: query, Reducer: ReducerMax, Operator: "=~"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCondition(tt.condition)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBurst(t *testing.T) {
+	recipients := Recipients{To: []string{"emea@example.com"}}
+	tooMany := make([]BurstValue, MaxBurstValues+1)
+	for i := range tooMany {
+		tooMany[i] = BurstValue{Value: fmt.Sprintf("v%d", i), Recipients: recipients}
+	}
+
+	tests := []struct {
+		name    string
+		burst   *Burst
+		wantErr bool
+	}{
+		{name: "none", burst: nil, wantErr: false},
+		{name: "valid", burst: &Burst{Variable: "region", Values: []BurstValue{{Value: "emea", Recipients: recipients}, {Value: "apac", Recipients: recipients}}}, wantErr: false},
+		{name: "without variable", burst: &Burst{Values: []BurstValue{{Value: "emea", Recipients: recipients}}}, wantErr: true},
+		{name: "without values", burst: &Burst{Variable: "region"}, wantErr: true},
+		{name: "empty value", burst: &Burst{Variable: "region", Values: []BurstValue{{Recipients: recipients}}}, wantErr: true},
+		{name: "duplicate value", burst: &Burst{Variable: "region", Values: []BurstValue{{Value: "emea", Recipients: recipients}, {Value: "emea", Recipients: recipients}}}, wantErr: true},
+		{name: "value without recipients", burst: &Burst{Variable: "region", Values: []BurstValue{{Value: "emea"}}}, wantErr: true},
+		{name: "too many values", burst: &Burst{Variable: "region", Values: tooMany}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBurst(tt.burst)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBurst() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDashboards(t *testing.T) {
+	tooMany := make(DashboardRefs, MaxReportDashboards+1)
+	for i := range tooMany {
+		tooMany[i] = DashboardRef{UID: fmt.Sprintf("d%d", i)}
+	}
+
+	tests := []struct {
+		name       string
+		dashboards DashboardRefs
+		format     string
+		wantErr    bool
+	}{
+		{name: "none", dashboards: nil, format: FormatPNG, wantErr: false},
+		{name: "valid", dashboards: DashboardRefs{{UID: "a"}, {UID: "b", PanelIDs: IntSlice{1, 2}, RangeFrom: "now-1d", RangeTo: "now"}}, format: FormatPDF, wantErr: false},
+		{name: "default format", dashboards: DashboardRefs{{UID: "a"}}, format: "", wantErr: false},
+		{name: "image format", dashboards: DashboardRefs{{UID: "a"}}, format: FormatPNG, wantErr: true},
+		{name: "missing uid", dashboards: DashboardRefs{{UID: "a"}, {Title: "No UID"}}, format: FormatPDF, wantErr: true},
+		{name: "half a time range", dashboards: DashboardRefs{{UID: "a", RangeFrom: "now-1d"}}, format: FormatPDF, wantErr: true},
+		{name: "too many", dashboards: tooMany, format: FormatPDF, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDashboards(tt.dashboards, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDashboards() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
+)
+
+// Section is a rendered PDF document included in a combined report
+type Section struct {
+	Title   string
+	Data    []byte
+	Entries []Entry // Rows and panels listed below the section in the table of contents
+}
+
+// Entry is a heading inside a section, such as a dashboard row or panel
+type Entry struct {
+	Title string
+	Level int     // 0 for top-level entries, 1 for entries nested below them
+	Page  int     // Page of the section the entry is on, starting at 1
+	Y     float64 // Distance from the top of the page in millimeters
+}
+
+// Cover is the content of a report's cover page
+type Cover struct {
+	Title    string
+	Subtitle string
+	Details  []string // Smaller lines below the subtitle, e.g. the generation time
+	Logo     []byte   // PNG, JPEG or GIF image drawn above the title
+}
+
+// FrontMatter selects the pages placed in front of the combined sections
+type FrontMatter struct {
+	Cover    *Cover // nil omits the cover page
+	Contents bool   // Adds a table of contents linking to sections and their entries
+}
+
+// Table of contents and cover layout in millimeters
+const (
+	tocLineHeight = 8.0
+	tocTitleSpace = 20.0
+	tocIndent     = 6.0
+	coverLogoWd   = 60.0
+	coverLogoHt   = 25.0
+)
+
+// importedSection is a section whose pages were imported as templates
+type importedSection struct {
+	title     string
+	entries   []Entry
+	templates []int
+	sizes     []gofpdf.SizeType
+	firstPage int
+}
+
+// Combine merges PDF documents into one report behind the selected front matter. The table of
+// contents links to the first page of each section and to the position of each of its entries,
+// which also become nested bookmarks. Imported pages keep their size; the cover and table of
+// contents use the page size and orientation of opts.
+func (g *Generator) Combine(sections []Section, front FrontMatter, opts Options) (data []byte, err error) {
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("no documents provided")
+	}
+
+	// The importer panics on documents it cannot parse
+	defer func() {
+		if r := recover(); r != nil {
+			data, err = nil, fmt.Errorf("failed to import PDF: %v", r)
+		}
+	}()
+
+	pdf := newDocument(Options{Title: opts.Title, Orientation: opts.Orientation, PageSize: opts.PageSize, Margins: opts.Margins})
+	importer := gofpdi.NewImporter()
+
+	imported := make([]importedSection, 0, len(sections))
+	lines := 0
+	for i, section := range sections {
+		s, err := importSection(pdf, importer, section)
+		if err != nil {
+			return nil, fmt.Errorf("section %d (%s): %w", i+1, section.Title, err)
+		}
+		imported = append(imported, s)
+		lines += 1 + len(s.entries)
+	}
+
+	// Page numbers follow the cover and the table of contents
+	_, pageHeight := pdf.GetPageSize()
+	margins := pageMargins(opts)
+	perPage := int((pageHeight - margins.Top - margins.Bottom - tocTitleSpace) / tocLineHeight)
+	if perPage < 1 {
+		perPage = 1
+	}
+	page := 1
+	if front.Cover != nil {
+		page++
+	}
+	if front.Contents {
+		page += int(math.Ceil(float64(lines) / float64(perPage)))
+	}
+	for i := range imported {
+		imported[i].firstPage = page
+		page += len(imported[i].templates)
+	}
+
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	if front.Cover != nil {
+		drawCover(pdf, tr, *front.Cover, opts)
+	}
+	if front.Contents {
+		drawContents(pdf, tr, imported, perPage, opts)
+	}
+
+	for _, s := range imported {
+		next := 0
+		for j, tpl := range s.templates {
+			size := s.sizes[j]
+			pdf.AddPageFormat("P", size)
+			if j == 0 {
+				pdf.Bookmark(tr(s.title), 0, 0)
+			}
+			for ; next < len(s.entries) && s.entries[next].Page == j+1; next++ {
+				pdf.Bookmark(tr(s.entries[next].Title), s.entries[next].Level+1, s.entries[next].Y)
+			}
+			importer.UseImportedTemplate(pdf, tpl, 0, 0, size.Wd, size.Ht)
+		}
+	}
+
+	return output(pdf)
+}
+
+// importSection imports every page of a section's PDF as a template
+func importSection(pdf *gofpdf.Fpdf, importer *gofpdi.Importer, section Section) (importedSection, error) {
+	if !bytes.HasPrefix(section.Data, []byte("%PDF")) {
+		return importedSection{}, fmt.Errorf("not a PDF document")
+	}
+
+	rs := io.ReadSeeker(bytes.NewReader(section.Data))
+	s := importedSection{title: section.Title}
+	s.templates = append(s.templates, importer.ImportPageFromStream(pdf, &rs, 1, "/MediaBox"))
+	sizes := importer.GetPageSizes()
+	for page := 2; page <= len(sizes); page++ {
+		s.templates = append(s.templates, importer.ImportPageFromStream(pdf, &rs, page, "/MediaBox"))
+	}
+	if pdf.Err() {
+		return importedSection{}, pdf.Error()
+	}
+
+	// Page sizes are reported in points
+	for page := 1; page <= len(s.templates); page++ {
+		box := sizes[page]["/MediaBox"]
+		s.sizes = append(s.sizes, gofpdf.SizeType{Wd: pdf.PointToUnitConvert(box["w"]), Ht: pdf.PointToUnitConvert(box["h"])})
+	}
+
+	s.entries = sectionEntries(section.Entries, len(s.templates))
+	return s, nil
+}
+
+// sectionEntries returns the entries in page order, clamped to the pages of the section
+func sectionEntries(entries []Entry, pages int) []Entry {
+	result := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		e.Page = min(max(e.Page, 1), pages)
+		e.Level = min(max(e.Level, 0), 1)
+		e.Y = math.Max(e.Y, 0)
+		result = append(result, e)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Page < result[j].Page })
+	return result
+}
+
+// drawCover adds the cover page with the logo above the centered title and the details below it
+func drawCover(pdf *gofpdf.Fpdf, tr func(string) string, cover Cover, opts Options) {
+	pdf.AddPage()
+	margins := pageMargins(opts)
+	pageWidth, pageHeight := pdf.GetPageSize()
+	width := pageWidth - margins.Left - margins.Right
+
+	if logoType := imageType(cover.Logo); logoType != "" {
+		imgOpts := gofpdf.ImageOptions{ImageType: logoType}
+		info := pdf.RegisterImageOptionsReader("cover_logo", imgOpts, bytes.NewReader(cover.Logo))
+		if pdf.Err() {
+			// A broken logo must not fail the report
+			pdf.ClearError()
+		} else {
+			w, h := fitImage(info, coverLogoWd, coverLogoHt)
+			pdf.ImageOptions("cover_logo", (pageWidth-w)/2, pageHeight*0.35-h-10, w, h, false, imgOpts, 0, "")
+		}
+	}
+
+	pdf.SetXY(margins.Left, pageHeight*0.35)
+	pdf.SetFont("Arial", "B", 28)
+	pdf.MultiCell(width, 12, tr(cover.Title), "", "C", false)
+
+	if cover.Subtitle != "" {
+		pdf.SetX(margins.Left)
+		pdf.SetFont("Arial", "", 16)
+		pdf.MultiCell(width, 10, tr(cover.Subtitle), "", "C", false)
+	}
+
+	pdf.Ln(6)
+	pdf.SetFont("Arial", "", 11)
+	pdf.SetTextColor(90, 90, 90)
+	for _, line := range cover.Details {
+		pdf.SetX(margins.Left)
+		pdf.MultiCell(width, 6, tr(line), "", "C", false)
+	}
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// drawContents adds the table of contents: one line per section, followed by its entries indented
+// by level. Every line links to its target, including the page number.
+func drawContents(pdf *gofpdf.Fpdf, tr func(string) string, sections []importedSection, perPage int, opts Options) {
+	margins := pageMargins(opts)
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - margins.Left - margins.Right
+
+	line := 0
+	addLine := func(title string, indent float64, page int, y float64, style, border string) {
+		if line%perPage == 0 {
+			pdf.AddPage()
+			pdf.SetXY(margins.Left, margins.Top)
+			pdf.SetFont("Arial", "B", 18)
+			pdf.CellFormat(width, tocTitleSpace-6, "Contents", "", 1, "L", false, 0, "")
+			pdf.Ln(6)
+		}
+		line++
+
+		link := pdf.AddLink()
+		pdf.SetLink(link, y, page)
+		pdf.SetFont("Arial", style, 12)
+		number := strconv.Itoa(page)
+		numberWidth := pdf.GetStringWidth(number) + 2
+		pdf.SetX(margins.Left + indent)
+		pdf.CellFormat(width-indent-numberWidth, tocLineHeight, tr(title), border, 0, "L", false, link, "")
+		pdf.CellFormat(numberWidth, tocLineHeight, number, border, 1, "R", false, link, "")
+	}
+
+	for i, s := range sections {
+		title := s.title
+		if len(sections) > 1 {
+			title = fmt.Sprintf("%d. %s", i+1, s.title)
+		}
+		addLine(title, 0, s.firstPage, 0, "B", "B")
+		for _, e := range s.entries {
+			addLine(e.Title, tocIndent*float64(e.Level+1), s.firstPage+e.Page-1, e.Y, "", "")
+		}
+	}
+}
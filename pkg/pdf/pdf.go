@@ -3,20 +3,41 @@ package pdf
 import (
 	"bytes"
 	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jung-kurt/gofpdf"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
 )
 
 // Options holds PDF generation options
 type Options struct {
 	Title       string
-	Orientation string // "P" or "L"
-	PageSize    string // "A4", "Letter"
-	Header      string
-	Footer      string
+	Orientation string         // "portrait" or "landscape" (default)
+	PageSize    string         // "A4" (default), "A3", "A5", "Letter", "Legal"
+	Header      string         // {{page}} and {{pages}} are replaced with page numbers
+	Footer      string         // {{page}} and {{pages}} are replaced with page numbers
+	Margins     *model.Margins // Page margins in millimeters (default 10mm)
+	Watermark   string         // Diagonal text drawn over every page
+	Logo        []byte         // PNG, JPEG or GIF image drawn in the header
 }
 
+// Header and footer band heights in millimeters
+const (
+	headerHeight = 12.0
+	footerHeight = 10.0
+	// sectionGap is the vertical space between images stacked by GenerateFlow
+	sectionGap = 3.0
+)
+
+// pageObjectPattern matches page objects (but not the /Pages tree) in a PDF
+var pageObjectPattern = regexp.MustCompile(`/Type\s*/Page\b`)
+
 // Generator handles PDF generation
 type Generator struct{}
 
@@ -25,12 +46,156 @@ func NewGenerator() *Generator {
 	return &Generator{}
 }
 
-// Generate creates a PDF from PNG images
+// Generate creates a PDF from PNG images, one image per page.
+// Each image is scaled to fit the printable area while keeping its aspect ratio.
 func (g *Generator) Generate(images [][]byte, opts Options) ([]byte, error) {
 	if len(images) == 0 {
 		return nil, fmt.Errorf("no images provided")
 	}
 
+	pdf := newDocument(opts)
+	left, top, width, height := contentBox(pdf, opts)
+
+	// Add each image as a page
+	for i, imgData := range images {
+		pdf.AddPage()
+
+		imgOpts := gofpdf.ImageOptions{
+			ImageType: "PNG",
+			ReadDpi:   true,
+		}
+
+		// Register and insert the image, scaled to fit the page
+		imgName := fmt.Sprintf("image_%d", i)
+		info := pdf.RegisterImageOptionsReader(imgName, imgOpts, bytes.NewReader(imgData))
+		if pdf.Err() {
+			return nil, fmt.Errorf("failed to load image %d: %w", i, pdf.Error())
+		}
+
+		imgWidth, imgHeight := fitImage(info, width, height)
+		x := left + (width-imgWidth)/2
+		pdf.ImageOptions(imgName, x, top, imgWidth, imgHeight, false, imgOpts, 0, "")
+	}
+
+	return output(pdf)
+}
+
+// Placement is the position of an image laid out by GenerateFlow
+type Placement struct {
+	Page int     // Page the image starts on, starting at 1
+	Y    float64 // Top of the image in millimeters
+}
+
+// GenerateFlow creates a PDF by stacking PNG images top to bottom across as many pages as needed.
+// Images are scaled to the printable width; an image that does not fit in the remaining space starts
+// a new page, and images taller than a whole page are sliced so nothing is cut off.
+// The placement of every image is returned alongside the document.
+func (g *Generator) GenerateFlow(images [][]byte, opts Options) ([]byte, []Placement, error) {
+	if len(images) == 0 {
+		return nil, nil, fmt.Errorf("no images provided")
+	}
+
+	pdf := newDocument(opts)
+	left, top, width, height := contentBox(pdf, opts)
+	imgOpts := gofpdf.ImageOptions{ImageType: "PNG"}
+
+	pdf.AddPage()
+	y := top
+	n := 0
+	placements := make([]Placement, len(images))
+	for i, imgData := range images {
+		cfg, err := png.DecodeConfig(bytes.NewReader(imgData))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode image %d: %w", i, err)
+		}
+		if cfg.Width == 0 || cfg.Height == 0 {
+			placements[i] = Placement{Page: pdf.PageNo(), Y: y}
+			continue
+		}
+
+		slices := [][]byte{imgData}
+		if float64(cfg.Height)*width/float64(cfg.Width) > height {
+			// Slice so every piece fills at most one page at full width
+			slices, err = sliceImage(imgData, int(height*float64(cfg.Width)/width))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to slice image %d: %w", i, err)
+			}
+		}
+
+		for k, slice := range slices {
+			imgName := fmt.Sprintf("image_%d", n)
+			n++
+			info := pdf.RegisterImageOptionsReader(imgName, imgOpts, bytes.NewReader(slice))
+			if pdf.Err() {
+				return nil, nil, fmt.Errorf("failed to load image %d: %w", i, pdf.Error())
+			}
+
+			imgWidth, imgHeight := width, 0.0
+			if w, h := info.Extent(); w > 0 {
+				imgHeight = h * width / w
+			}
+
+			if y > top && y+imgHeight > top+height {
+				pdf.AddPage()
+				y = top
+			}
+			if k == 0 {
+				placements[i] = Placement{Page: pdf.PageNo(), Y: y}
+			}
+			pdf.ImageOptions(imgName, left, y, imgWidth, imgHeight, false, imgOpts, 0, "")
+			y += imgHeight + sectionGap
+		}
+	}
+
+	data, err := output(pdf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, placements, nil
+}
+
+// PageCount returns the number of pages in a PDF document
+func PageCount(data []byte) int {
+	return len(pageObjectPattern.FindAll(data, -1))
+}
+
+// sliceImage splits a PNG into horizontal strips of at most maxHeight pixels
+func sliceImage(data []byte, maxHeight int) ([][]byte, error) {
+	if maxHeight <= 0 {
+		return nil, fmt.Errorf("invalid slice height %d", maxHeight)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, fmt.Errorf("image type %T cannot be sliced", img)
+	}
+
+	bounds := img.Bounds()
+	var slices [][]byte
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += maxHeight {
+		bottom := y + maxHeight
+		if bottom > bounds.Max.Y {
+			bottom = bounds.Max.Y
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, sub.SubImage(image.Rect(bounds.Min.X, y, bounds.Max.X, bottom))); err != nil {
+			return nil, err
+		}
+		slices = append(slices, buf.Bytes())
+	}
+
+	return slices, nil
+}
+
+// newDocument creates a gofpdf document with metadata, header, footer and watermark configured
+func newDocument(opts Options) *gofpdf.Fpdf {
 	orientation := "L"
 	if opts.Orientation == "portrait" {
 		orientation = "P"
@@ -42,73 +207,145 @@ func (g *Generator) Generate(images [][]byte, opts Options) ([]byte, error) {
 	}
 
 	pdf := gofpdf.New(orientation, "mm", pageSize, "")
+	margins := pageMargins(opts)
+	pdf.SetMargins(margins.Left, margins.Top, margins.Right)
+	pdf.SetAutoPageBreak(false, margins.Bottom)
+	pdf.AliasNbPages("{nb}")
+
+	// Core fonts only cover cp1252
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
 
 	// Set document metadata
 	pdf.SetTitle(opts.Title, true)
 	pdf.SetCreator("Grafana Reporting Plugin", true)
 	pdf.SetCreationDate(time.Now())
 
-	// Set header and footer if provided
-	if opts.Header != "" {
-		pdf.SetHeaderFunc(func() {
-			pdf.SetFont("Arial", "", 10)
-			pdf.Cell(0, 10, opts.Header)
-			pdf.Ln(12)
-		})
+	logoType := imageType(opts.Logo)
+	if logoType != "" {
+		pdf.RegisterImageOptionsReader("logo", gofpdf.ImageOptions{ImageType: logoType}, bytes.NewReader(opts.Logo))
+		if pdf.Err() {
+			// A broken logo must not fail the report
+			pdf.ClearError()
+			logoType = ""
+		}
 	}
 
-	if opts.Footer != "" {
-		pdf.SetFooterFunc(func() {
-			pdf.SetY(-15)
+	pdf.SetHeaderFunc(func() {
+		if opts.Header != "" {
+			pdf.SetXY(margins.Left, margins.Top)
+			pdf.SetFont("Arial", "", 10)
+			pdf.CellFormat(0, headerHeight-2, tr(pageText(opts.Header, pdf.PageNo())), "", 0, "L", false, 0, "")
+		}
+		if logoType != "" {
+			pageWidth, _ := pdf.GetPageSize()
+			pdf.ImageOptions("logo", pageWidth-margins.Right-40, margins.Top, 40, headerHeight-4, false,
+				gofpdf.ImageOptions{ImageType: logoType}, 0, "")
+		}
+	})
+
+	// The footer runs after the page content, so the watermark is drawn on top of it
+	pdf.SetFooterFunc(func() {
+		if opts.Watermark != "" {
+			drawWatermark(pdf, tr(opts.Watermark))
+		}
+		if opts.Footer != "" {
+			_, pageHeight := pdf.GetPageSize()
+			pdf.SetXY(margins.Left, pageHeight-margins.Bottom-footerHeight+2)
 			pdf.SetFont("Arial", "I", 8)
-			pdf.Cell(0, 10, fmt.Sprintf("%s - Page %d", opts.Footer, pdf.PageNo()))
-		})
+			pdf.CellFormat(0, footerHeight-2, tr(pageText(opts.Footer, pdf.PageNo())), "", 0, "C", false, 0, "")
+		}
+	})
+
+	return pdf
+}
+
+// contentBox returns the area available for images between header and footer
+func contentBox(pdf *gofpdf.Fpdf, opts Options) (left, top, width, height float64) {
+	margins := pageMargins(opts)
+	pageWidth, pageHeight := pdf.GetPageSize()
+
+	top = margins.Top
+	if opts.Header != "" || len(opts.Logo) > 0 {
+		top += headerHeight
+	}
+	bottom := pageHeight - margins.Bottom
+	if opts.Footer != "" {
+		bottom -= footerHeight
 	}
 
-	// Add each image as a page
-	for i, imgData := range images {
-		pdf.AddPage()
+	return margins.Left, top, pageWidth - margins.Left - margins.Right, bottom - top
+}
 
-		// Create a temporary image reader
-		reader := bytes.NewReader(imgData)
-		imgOpts := gofpdf.ImageOptions{
-			ImageType: "PNG",
-			ReadDpi:   true,
-		}
+// pageMargins returns the configured margins or the 10mm default
+func pageMargins(opts Options) model.Margins {
+	if opts.Margins != nil {
+		return *opts.Margins
+	}
+	return model.Margins{Top: 10, Bottom: 10, Left: 10, Right: 10}
+}
 
-		// Calculate image dimensions to fit page
-		var pageWidth, pageHeight float64
-		if orientation == "L" {
-			pageWidth, pageHeight = 297, 210 // A4 landscape
-		} else {
-			pageWidth, pageHeight = 210, 297 // A4 portrait
-		}
+// fitImage scales an image to fit inside the box while keeping its aspect ratio
+func fitImage(info *gofpdf.ImageInfoType, boxWidth, boxHeight float64) (float64, float64) {
+	imgWidth, imgHeight := info.Extent()
+	return fitExtent(imgWidth, imgHeight, boxWidth, boxHeight)
+}
 
-		if pageSize == "Letter" {
-			if orientation == "L" {
-				pageWidth, pageHeight = 279.4, 215.9
-			} else {
-				pageWidth, pageHeight = 215.9, 279.4
-			}
-		}
+// fitExtent scales width and height to fit inside the box while keeping the aspect ratio
+func fitExtent(imgWidth, imgHeight, boxWidth, boxHeight float64) (float64, float64) {
+	if imgWidth <= 0 || imgHeight <= 0 {
+		return boxWidth, boxHeight
+	}
 
-		// Add some margins
-		margin := 10.0
-		imgWidth := pageWidth - (2 * margin)
-		imgHeight := pageHeight - (2 * margin)
+	scale := boxWidth / imgWidth
+	if imgHeight*scale > boxHeight {
+		scale = boxHeight / imgHeight
+	}
+	return imgWidth * scale, imgHeight * scale
+}
 
-		// Register and insert the image
-		imgName := fmt.Sprintf("image_%d", i)
-		pdf.RegisterImageOptionsReader(imgName, imgOpts, reader)
-		pdf.ImageOptions(imgName, margin, margin, imgWidth, imgHeight, false, imgOpts, 0, "")
+// pageText resolves page number placeholders in header/footer text
+func pageText(text string, page int) string {
+	text = strings.ReplaceAll(text, "{{page}}", strconv.Itoa(page))
+	return strings.ReplaceAll(text, "{{pages}}", "{nb}")
+}
+
+// drawWatermark draws semi-transparent diagonal text across the current page
+func drawWatermark(pdf *gofpdf.Fpdf, text string) {
+	pageWidth, pageHeight := pdf.GetPageSize()
+
+	pdf.SetFont("Arial", "B", 60)
+	pdf.SetTextColor(128, 128, 128)
+	pdf.SetAlpha(0.18, "Normal")
+	pdf.TransformBegin()
+	pdf.TransformRotate(30, pageWidth/2, pageHeight/2)
+	textWidth := pdf.GetStringWidth(text)
+	pdf.Text(pageWidth/2-textWidth/2, pageHeight/2, text)
+	pdf.TransformEnd()
+	pdf.SetAlpha(1, "Normal")
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// imageType returns the gofpdf image type for PNG, JPEG or GIF data, or "" if unsupported
+func imageType(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return "PNG"
+	case "image/jpeg":
+		return "JPG"
+	case "image/gif":
+		return "GIF"
 	}
+	return ""
+}
 
-	// Output PDF to buffer
+// output renders the document to bytes
+func output(pdf *gofpdf.Fpdf) ([]byte, error) {
 	var buf bytes.Buffer
-	err := pdf.Output(&buf)
-	if err != nil {
+	if err := pdf.Output(&buf); err != nil {
 		return nil, fmt.Errorf("failed to generate PDF: %w", err)
 	}
-
 	return buf.Bytes(), nil
 }
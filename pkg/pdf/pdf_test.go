@@ -0,0 +1,232 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, height/2, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestGenerateOnePagePerImage(t *testing.T) {
+	images := [][]byte{testPNG(t, 400, 200), testPNG(t, 200, 400), testPNG(t, 300, 300)}
+
+	data, err := NewGenerator().Generate(images, Options{
+		Title:     "Report",
+		PageSize:  "A4",
+		Header:    "Report header",
+		Footer:    "Page {{page}} of {{pages}}",
+		Margins:   &model.Margins{Top: 15, Bottom: 15, Left: 12, Right: 12},
+		Watermark: "CONFIDENTIAL",
+		Logo:      testPNG(t, 80, 20),
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		t.Fatal("Generate() output is not a PDF")
+	}
+	if pages := PageCount(data); pages != len(images) {
+		t.Errorf("Generate() produced %d pages, want %d", pages, len(images))
+	}
+}
+
+func TestGenerateFlow(t *testing.T) {
+	tests := []struct {
+		name           string
+		images         [][]byte
+		wantPages      int
+		wantPlacements []int
+	}{
+		{
+			name:           "short rows share a page",
+			images:         [][]byte{testPNG(t, 1600, 200), testPNG(t, 1600, 200), testPNG(t, 1600, 200)},
+			wantPages:      1,
+			wantPlacements: []int{1, 1, 1},
+		},
+		{
+			name:           "rows that do not fit start a new page",
+			images:         [][]byte{testPNG(t, 1600, 700), testPNG(t, 1600, 700)},
+			wantPages:      2,
+			wantPlacements: []int{1, 2},
+		},
+		{
+			// A4 landscape printable area is about 277x190mm, so a 1600x4000 image spans four pages
+			name:           "tall image is sliced across pages",
+			images:         [][]byte{testPNG(t, 1600, 4000)},
+			wantPages:      4,
+			wantPlacements: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, placements, err := NewGenerator().GenerateFlow(tt.images, Options{Title: "Report"})
+			if err != nil {
+				t.Fatalf("GenerateFlow() error = %v", err)
+			}
+			if pages := PageCount(data); pages != tt.wantPages {
+				t.Errorf("GenerateFlow() produced %d pages, want %d", pages, tt.wantPages)
+			}
+			if len(placements) != len(tt.wantPlacements) {
+				t.Fatalf("GenerateFlow() returned %d placements, want %d", len(placements), len(tt.wantPlacements))
+			}
+			for i, p := range placements {
+				if p.Page != tt.wantPlacements[i] {
+					t.Errorf("image %d placed on page %d, want %d", i, p.Page, tt.wantPlacements[i])
+				}
+			}
+			if placements[0].Y != 10 {
+				t.Errorf("first image placed at %vmm, want the top margin", placements[0].Y)
+			}
+		})
+	}
+}
+
+func TestPageCount(t *testing.T) {
+	data := []byte("<</Type /Pages /Kids [3 0 R 4 0 R] /Count 2>>\n<</Type /Page /Parent 1 0 R>>\n<</Type/Page/Parent 1 0 R>>")
+	if got := PageCount(data); got != 2 {
+		t.Errorf("PageCount() = %d, want 2", got)
+	}
+}
+
+func TestGenerateNoImages(t *testing.T) {
+	if _, err := NewGenerator().Generate(nil, Options{}); err == nil {
+		t.Error("Generate() should fail without images")
+	}
+}
+
+func TestFitExtent(t *testing.T) {
+	tests := []struct {
+		name         string
+		imgW, imgH   float64
+		boxW, boxH   float64
+		wantW, wantH float64
+	}{
+		{"wide image limited by width", 400, 100, 200, 100, 200, 50},
+		{"tall image limited by height", 100, 400, 200, 100, 25, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h := fitExtent(tt.imgW, tt.imgH, tt.boxW, tt.boxH)
+			if w != tt.wantW || h != tt.wantH {
+				t.Errorf("fitExtent() = %vx%v, want %vx%v", w, h, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestCombine(t *testing.T) {
+	generator := NewGenerator()
+	first, err := generator.Generate([][]byte{testPNG(t, 400, 200), testPNG(t, 400, 200)}, Options{Title: "Overview"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	second, err := generator.Generate([][]byte{testPNG(t, 200, 400)}, Options{Title: "Latency", Orientation: "portrait", PageSize: "A3"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := generator.Combine(
+		[]Section{{Title: "Overview", Data: first}, {Title: "Latency", Data: second}},
+		FrontMatter{
+			Cover:    &Cover{Title: "Weekly Ops", Subtitle: "2 dashboards", Details: []string{"Generated 2024-03-01 08:00"}},
+			Contents: true,
+		},
+		Options{Title: "Weekly Ops"},
+	)
+	if err != nil {
+		t.Fatalf("Combine() error = %v", err)
+	}
+
+	// Cover, contents and the three imported pages
+	if pages := PageCount(data); pages != 5 {
+		t.Errorf("Combine() produced %d pages, want 5", pages)
+	}
+	if !bytes.Contains(data, []byte("/Outlines")) {
+		t.Error("Combine() output has no bookmarks")
+	}
+	if links := bytes.Count(data, []byte("/Subtype /Link")); links != 4 {
+		t.Errorf("Combine() output has %d links, want 4 (title and page number per section)", links)
+	}
+}
+
+func TestCombineEntries(t *testing.T) {
+	generator := NewGenerator()
+	dashboard, err := generator.Generate([][]byte{testPNG(t, 400, 200), testPNG(t, 400, 200)}, Options{Title: "Overview"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := generator.Combine(
+		[]Section{{Title: "Overview", Data: dashboard, Entries: []Entry{
+			{Title: "Latency", Level: 1, Page: 2, Y: 40},
+			{Title: "Traffic", Level: 0, Page: 1},
+			{Title: "Requests", Level: 1, Page: 1, Y: 20},
+			{Title: "Beyond the end", Page: 7},
+		}}},
+		FrontMatter{Contents: true},
+		Options{Title: "Overview"},
+	)
+	if err != nil {
+		t.Fatalf("Combine() error = %v", err)
+	}
+
+	// Contents and the two dashboard pages, without a cover
+	if pages := PageCount(data); pages != 3 {
+		t.Errorf("Combine() produced %d pages, want 3", pages)
+	}
+	if links := bytes.Count(data, []byte("/Subtype /Link")); links != 10 {
+		t.Errorf("Combine() output has %d links, want 10 (title and page number per line)", links)
+	}
+	for _, title := range []string{"Traffic", "Requests", "Latency", "Beyond the end"} {
+		if !bytes.Contains(data, []byte("/Title ("+title+")")) {
+			t.Errorf("Combine() output has no bookmark for %q", title)
+		}
+	}
+}
+
+func TestSectionEntries(t *testing.T) {
+	entries := sectionEntries([]Entry{
+		{Title: "b", Page: 2},
+		{Title: "a", Page: 0, Y: -5},
+		{Title: "c", Page: 9, Level: 3},
+		{Title: "d", Page: 2},
+	}, 3)
+
+	want := []Entry{{Title: "a", Page: 1}, {Title: "b", Page: 2}, {Title: "d", Page: 2}, {Title: "c", Page: 3, Level: 1}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("sectionEntries() = %+v, want %+v", entries, want)
+	}
+}
+
+func TestCombineInvalidSection(t *testing.T) {
+	_, err := NewGenerator().Combine([]Section{{Title: "Broken", Data: []byte("not a pdf")}}, FrontMatter{Contents: true}, Options{})
+	if err == nil {
+		t.Error("Combine() error = nil, want an error for a section that is not a PDF")
+	}
+
+	// Truncated documents make the importer panic, which is reported as an error
+	_, err = NewGenerator().Combine([]Section{{Title: "Truncated", Data: []byte("%PDF-1.4\n1 0 obj")}}, FrontMatter{Contents: true}, Options{})
+	if err == nil {
+		t.Error("Combine() error = nil, want an error for a truncated PDF")
+	}
+}
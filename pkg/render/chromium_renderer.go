@@ -9,12 +9,13 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
-	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/yourusername/scheduled-reports-app/pkg/grafana"
 	"github.com/yourusername/scheduled-reports-app/pkg/model"
 )
 
@@ -221,59 +222,12 @@ func (r *ChromiumRenderer) getBrowser() (*rod.Browser, error) {
 
 // getServiceAccountToken retrieves the service account token from Grafana's managed service accounts
 func (r *ChromiumRenderer) getServiceAccountToken(ctx context.Context) (string, error) {
-	log.Printf("DEBUG: ========== TOKEN RETRIEVAL START ==========")
-
-	// Priority 1: Try to get token from Grafana's managed service account (preferred method)
-	// Grafana 10.3+ automatically creates a service account for the plugin based on plugin.json IAM configuration
-	cfg := backend.GrafanaConfigFromContext(ctx)
-	if cfg != nil {
-		log.Printf("DEBUG: Grafana config available in context - trying cfg.PluginAppClientSecret()")
-		token, err := cfg.PluginAppClientSecret()
-		if err != nil {
-			log.Printf("ERROR: cfg.PluginAppClientSecret() returned error: %v", err)
-		}
-		if token != "" {
-			log.Printf("SUCCESS: Retrieved token from Grafana SDK (length: %d, preview: %s...)", len(token), token[:min(20, len(token))])
-			log.Printf("DEBUG: ========== TOKEN RETRIEVAL END (via SDK) ==========")
-			return token, nil
-		}
-		log.Printf("WARNING: cfg.PluginAppClientSecret() returned empty token")
-	} else {
-		log.Printf("DEBUG: Grafana config NOT available in context (expected for background jobs)")
-	}
-
-	// Priority 2: Check environment variable GF_PLUGIN_APP_CLIENT_SECRET
-	// This is set by Grafana when the plugin starts if managed service accounts are enabled
-	log.Printf("DEBUG: Checking environment variable GF_PLUGIN_APP_CLIENT_SECRET...")
-	token := os.Getenv("GF_PLUGIN_APP_CLIENT_SECRET")
-	if token != "" {
-		log.Printf("SUCCESS: Retrieved token from GF_PLUGIN_APP_CLIENT_SECRET env var (length: %d, preview: %s...)", len(token), token[:min(20, len(token))])
-		log.Printf("DEBUG: ========== TOKEN RETRIEVAL END (via env var) ==========")
-		return token, nil
-	}
-	log.Printf("WARNING: GF_PLUGIN_APP_CLIENT_SECRET environment variable is not set or empty")
-
-	// No token available - managed service accounts not working
-	log.Printf("ERROR: ========== TOKEN RETRIEVAL FAILED - NO TOKEN FOUND ==========")
-	return "", fmt.Errorf(
-		"no service account token available\n\n" +
-			"Grafana managed service accounts are not configured correctly.\n\n" +
-			"Requirements:\n" +
-			"- Grafana 10.3 or later\n" +
-			"- Feature toggle enabled: [feature_toggles] enable = externalServiceAccounts\n" +
-			"- Plugin must be restarted after installation\n\n" +
-			"Steps to fix:\n" +
-			"1. Add to grafana.ini: [feature_toggles] enable = externalServiceAccounts\n" +
-			"2. Restart Grafana: sudo systemctl restart grafana-server\n" +
-			"3. Check Settings page (Apps → Scheduled Reports → Settings) for service account status\n\n" +
-			"The plugin.json already has IAM permissions configured, so Grafana will automatically\n" +
-			"create a service account when the feature toggle is enabled.",
-	)
+	return grafana.ServiceAccountToken(ctx)
 }
 
-// RenderDashboard renders a dashboard to PDF using Chromium
 // RenderDashboard renders a dashboard to PDF using Chromium (rod).
-func (r *ChromiumRenderer) RenderDashboard(ctx context.Context, schedule *model.Schedule) ([]byte, error) {
+// When opts.Template is set, its page size, margins, header, footer, logo and watermark are applied.
+func (r *ChromiumRenderer) RenderDashboard(ctx context.Context, schedule *model.Schedule, opts Options) (*Document, error) {
 	saToken, err := r.getServiceAccountToken(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("no service account token available: %w", err)
@@ -282,67 +236,169 @@ func (r *ChromiumRenderer) RenderDashboard(ctx context.Context, schedule *model.
 		return nil, fmt.Errorf("service account token is empty; configure it in plugin settings or enable managed service accounts")
 	}
 
-	// Build final URL and *force* orgId to avoid redirects that drop Authorization
+	// Selected panels are captured one by one and composed into a PDF, one panel per page
+	if len(schedule.PanelIDs) > 0 {
+		return r.renderPanels(ctx, schedule, opts, saToken)
+	}
+
 	dashboardURL, err := r.buildDashboardURL(schedule)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build dashboard URL: %w", err)
 	}
-	{
-		u, err := url.Parse(dashboardURL)
+
+	page, cleanup, err := r.openPage(saToken, r.config.ViewportWidth, r.config.ViewportHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	if err := r.loadDashboard(page, dashboardURL); err != nil {
+		return nil, err
+	}
+
+	// Paginated layout lays row-sized screenshots onto standard pages instead of printing one tall page
+	if schedule.Layout == model.LayoutPaginated {
+		return r.renderPaginated(ctx, page, schedule, opts, saToken)
+	}
+
+	// Apply template branding that has to live inside the page itself
+	applyTemplateWatermark(page, opts.Template)
+	var logoDataURI string
+	if opts.Template != nil && opts.Template.LogoURL != "" {
+		logoDataURI, err = r.fetchLogoDataURI(ctx, opts.Template.LogoURL, saToken)
 		if err != nil {
-			return nil, fmt.Errorf("invalid dashboard URL: %w", err)
+			log.Printf("WARNING: Failed to load template logo %s: %v", opts.Template.LogoURL, err)
 		}
-		q := u.Query()
-		if q.Get("kiosk") == "" {
-			q.Set("kiosk", "true")
-		}
-		if q.Get("theme") == "" {
-			q.Set("theme", "light")
-		}
-		u.RawQuery = q.Encode()
-		dashboardURL = u.String()
 	}
 
+	// STEP 6: Get final content dimensions
+	log.Printf("DEBUG: Calculating final content dimensions...")
+
+	// Get actual rendered content size using JavaScript
+	contentWidthPx := float64(r.config.ViewportWidth)   // Fallback
+	contentHeightPx := float64(r.config.ViewportHeight) // Fallback
+
+	widthResult, err := page.Eval(`() => {
+		// Get the maximum of scroll width, offset width, and client width
+		const body = document.body;
+		const html = document.documentElement;
+		return Math.max(
+			body.scrollWidth, body.offsetWidth,
+			html.clientWidth, html.scrollWidth, html.offsetWidth
+		);
+	}`)
+	if err == nil {
+		contentWidthPx = widthResult.Value.Num()
+	} else {
+		log.Printf("WARNING: Failed to get content width: %v", err)
+	}
+
+	finalHeightResult, err := page.Eval(`() => {
+		// Get the maximum of scroll height, offset height, and client height
+		const body = document.body;
+		const html = document.documentElement;
+		return Math.max(
+			body.scrollHeight, body.offsetHeight,
+			html.clientHeight, html.scrollHeight, html.offsetHeight
+		);
+	}`)
+	if err == nil {
+		contentHeightPx = finalHeightResult.Value.Num()
+	} else {
+		log.Printf("WARNING: Failed to get content height: %v", err)
+	}
+
+	log.Printf("DEBUG: Final content dimensions: %.0fpx x %.0fpx", contentWidthPx, contentHeightPx)
+
+	// STEP 7: Generate PDF with full content capture
+	// Without a template, zero margins capture the exact content dimensions
+	var headerHTML, footerHTML string
+	if opts.Template != nil {
+		headerHTML = buildHeaderHTML(opts.Template, logoDataURI, opts.Vars)
+		footerHTML = buildFooterHTML(opts.Template, opts.Vars)
+	}
+	printParams := buildPrintParams(contentWidthPx, contentHeightPx, opts.Template, headerHTML, footerHTML)
+	printParams.PrintBackground = printBackground(schedule, opts.Template)
+
+	log.Printf("DEBUG: PDF dimensions: %.2f\" x %.2f\" (scale %.2f, header/footer: %v)",
+		*printParams.PaperWidth, *printParams.PaperHeight, *printParams.Scale, printParams.DisplayHeaderFooter)
+
+	// Locate rows and panels on the printed pages for the table of contents
+	outline, err := dashboardOutline(page)
+	if err != nil {
+		log.Printf("WARNING: %v", err)
+	}
+	contents := outlineEntries(outline, func(top float64) (int, float64) {
+		return printPosition(top, printParams)
+	})
+
+	stream, err := page.PDF(printParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	log.Printf("DEBUG: PDF generated successfully")
+
+	pdf, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF stream: %w", err)
+	}
+	if len(pdf) < 5 || string(pdf[:5]) != "%PDF-" {
+		return nil, fmt.Errorf("output is not a PDF (got %d bytes)", len(pdf))
+	}
+	return &Document{Data: pdf, Contents: contents}, nil
+}
+
+// openPage creates a browser tab with the service account token and viewport configured.
+// The returned cleanup function must be called to close the tab.
+func (r *ChromiumRenderer) openPage(saToken string, width, height int) (*rod.Page, func(), error) {
 	browser, err := r.getBrowser()
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize browser: %w", err)
+		return nil, nil, fmt.Errorf("failed to initialize browser: %w", err)
 	}
 
 	page, err := browser.Page(proto.TargetCreateTarget{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create page: %w", err)
+		return nil, nil, fmt.Errorf("failed to create page: %w", err)
 	}
-	defer page.Close()
 
 	// Set global headers BEFORE any navigation. Key/value pairs, flat slice.
 	kv := []string{"Authorization", "Bearer " + saToken}
-	cleanup, err := page.SetExtraHeaders(kv)
+	restoreHeaders, err := page.SetExtraHeaders(kv)
 	if err != nil {
-		return nil, fmt.Errorf("failed to set global headers: %w", err)
+		page.Close()
+		return nil, nil, fmt.Errorf("failed to set global headers: %w", err)
+	}
+	cleanup := func() {
+		restoreHeaders()
+		page.Close()
 	}
-	defer cleanup()
 
-	// Set viewport to configured dimensions
+	// Set viewport to requested dimensions
 	if err := page.SetViewport(
 		&proto.EmulationSetDeviceMetricsOverride{
-			Width:             r.config.ViewportWidth,
-			Height:            r.config.ViewportHeight,
+			Width:             width,
+			Height:            height,
 			DeviceScaleFactor: r.config.DeviceScaleFactor,
 			Mobile:            false,
 		},
 	); err != nil {
-		return nil, fmt.Errorf("failed to set viewport: %w", err)
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to set viewport: %w", err)
 	}
 
 	// Timeout wrapper
-	page = page.Timeout(time.Duration(r.config.TimeoutMS) * time.Millisecond)
+	return page.Timeout(time.Duration(r.config.TimeoutMS) * time.Millisecond), cleanup, nil
+}
 
+// loadDashboard navigates to a dashboard or panel URL and waits until its panels finished loading
+func (r *ChromiumRenderer) loadDashboard(page *rod.Page, pageURL string) error {
 	// Navigate
-	if err := page.Navigate(dashboardURL); err != nil {
-		return nil, fmt.Errorf("failed to navigate to dashboard: %w", err)
+	if err := page.Navigate(pageURL); err != nil {
+		return fmt.Errorf("failed to navigate to dashboard: %w", err)
 	}
 	if err := page.WaitLoad(); err != nil {
-		return nil, fmt.Errorf("failed to wait for page load: %w", err)
+		return fmt.Errorf("failed to wait for page load: %w", err)
 	}
 
 	// Wait for panels to exist (not fatal if it races)
@@ -494,105 +550,17 @@ func (r *ChromiumRenderer) RenderDashboard(ctx context.Context, schedule *model.
 		time.Sleep(time.Duration(r.config.DelayMS) * time.Millisecond)
 	}
 
-	// STEP 6: Get final content dimensions
-	log.Printf("DEBUG: Calculating final content dimensions...")
-
-	// Get actual rendered content size using JavaScript
-	contentWidthPx := float64(r.config.ViewportWidth)   // Fallback
-	contentHeightPx := float64(r.config.ViewportHeight) // Fallback
-
-	widthResult, err := page.Eval(`() => {
-		// Get the maximum of scroll width, offset width, and client width
-		const body = document.body;
-		const html = document.documentElement;
-		return Math.max(
-			body.scrollWidth, body.offsetWidth,
-			html.clientWidth, html.scrollWidth, html.offsetWidth
-		);
-	}`)
-	if err == nil {
-		contentWidthPx = widthResult.Value.Num()
-	} else {
-		log.Printf("WARNING: Failed to get content width: %v", err)
-	}
-
-	finalHeightResult, err := page.Eval(`() => {
-		// Get the maximum of scroll height, offset height, and client height
-		const body = document.body;
-		const html = document.documentElement;
-		return Math.max(
-			body.scrollHeight, body.offsetHeight,
-			html.clientHeight, html.scrollHeight, html.offsetHeight
-		);
-	}`)
-	if err == nil {
-		contentHeightPx = finalHeightResult.Value.Num()
-	} else {
-		log.Printf("WARNING: Failed to get content height: %v", err)
-	}
-
-	log.Printf("DEBUG: Final content dimensions: %.0fpx x %.0fpx", contentWidthPx, contentHeightPx)
-
-	// Convert actual content dimensions to inches (Chrome uses 96 DPI)
-	paperWidthInches := contentWidthPx / 96.0
-	paperHeightInches := contentHeightPx / 96.0
-
-	// Apply minimum dimensions (prevent tiny PDFs)
-	if paperWidthInches < 8.0 {
-		log.Printf("DEBUG: Content width %.2f\" too small, setting to 8\"", paperWidthInches)
-		paperWidthInches = 8.0
-	}
-	if paperHeightInches < 6.0 {
-		log.Printf("DEBUG: Content height %.2f\" too small, setting to 6\"", paperHeightInches)
-		paperHeightInches = 6.0
-	}
-
-	// Apply maximum dimensions (Chrome PDF has a limit of ~200 inches)
-	if paperHeightInches > 200.0 {
-		log.Printf("WARNING: Content height %.2f inches exceeds Chrome limit (200\"), capping at 200 inches", paperHeightInches)
-		log.Printf("WARNING: Some content may be cut off. Consider reducing viewport height or dashboard height.")
-		paperHeightInches = 200.0
-	}
-	if paperWidthInches > 200.0 {
-		log.Printf("WARNING: Content width %.2f inches exceeds Chrome limit (200\"), capping at 200 inches", paperWidthInches)
-		paperWidthInches = 200.0
-	}
-
-	log.Printf("DEBUG: PDF dimensions: %.2f\" x %.2f\" (%.0fpx x %.0fpx @ 96 DPI)",
-		paperWidthInches, paperHeightInches,
-		paperWidthInches*96, paperHeightInches*96)
-
-	// STEP 7: Generate PDF with full content capture
-	// Use zero margins to capture exact content dimensions
-	f := func(x float64) *float64 { return &x }
-	stream, err := page.PDF(
-		&proto.PagePrintToPDF{
-			PrintBackground:     true,
-			PreferCSSPageSize:   false, // Use our calculated dimensions
-			PaperWidth:          f(paperWidthInches),
-			PaperHeight:         f(paperHeightInches),
-			MarginTop:           f(0.0), // Zero margins for full content
-			MarginBottom:        f(0.0),
-			MarginLeft:          f(0.0),
-			MarginRight:         f(0.0),
-			DisplayHeaderFooter: false,
-			Scale:               f(1.0),
-		},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate PDF: %w", err)
-	}
-
-	log.Printf("DEBUG: PDF generated successfully")
+	return nil
+}
 
-	pdf, err := io.ReadAll(stream)
+// FetchLogo downloads a template logo, authenticating against Grafana with the service account token
+func (r *ChromiumRenderer) FetchLogo(ctx context.Context, logoURL string) ([]byte, error) {
+	saToken, err := r.getServiceAccountToken(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read PDF stream: %w", err)
-	}
-	if len(pdf) < 5 || string(pdf[:5]) != "%PDF-" {
-		return nil, fmt.Errorf("output is not a PDF (got %d bytes)", len(pdf))
+		return nil, fmt.Errorf("no service account token available: %w", err)
 	}
-	return pdf, nil
+	logo, _, err := r.fetchLogo(ctx, logoURL, saToken)
+	return logo, err
 }
 
 // Close closes the browser instance
@@ -619,6 +587,17 @@ func (r *ChromiumRenderer) Name() string {
 
 // buildDashboardURL constructs the Grafana dashboard URL
 func (r *ChromiumRenderer) buildDashboardURL(schedule *model.Schedule) (string, error) {
+	return r.buildURL(schedule, "d", nil)
+}
+
+// buildPanelURL constructs the Grafana URL rendering a single panel of the dashboard (d-solo)
+func (r *ChromiumRenderer) buildPanelURL(schedule *model.Schedule, panelID int64) (string, error) {
+	return r.buildURL(schedule, "d-solo", url.Values{"panelId": {strconv.FormatInt(panelID, 10)}})
+}
+
+// buildURL constructs a Grafana URL for the schedule's dashboard under the given route
+// ("d" for the full dashboard, "d-solo" for a single panel) with time range and variables
+func (r *ChromiumRenderer) buildURL(schedule *model.Schedule, route string, extra url.Values) (string, error) {
 	// Use configured grafanaURL
 	baseURL := r.grafanaURL
 
@@ -649,15 +628,22 @@ func (r *ChromiumRenderer) buildDashboardURL(schedule *model.Schedule) (string,
 		basePath = ""
 	}
 
-	u.Path = fmt.Sprintf("%s/d/%s", basePath, schedule.DashboardUID)
+	u.Path = fmt.Sprintf("%s/%s/%s", basePath, route, schedule.DashboardUID)
 
 	q := u.Query()
 	q.Set("from", schedule.RangeFrom)
 	q.Set("to", schedule.RangeTo)
 	q.Set("kiosk", "1") // Hide menu, header, and time picker
+	q.Set("theme", scheduleTheme(schedule))
 	//q.Set("orgId", strconv.FormatInt(schedule.OrgID, 10))
 	q.Set("tz", schedule.Timezone)
 
+	for key, values := range extra {
+		for _, value := range values {
+			q.Add(key, value)
+		}
+	}
+
 	// Add dashboard variables (using Add to support duplicate variable names)
 	for _, variable := range schedule.Variables {
 		q.Add("var-"+variable.Name, variable.Value)
@@ -0,0 +1,93 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// jpegQuality is the JPEG quality (0-100) used for image reports
+const jpegQuality = 90
+
+// RenderImages renders a dashboard to PNG or JPEG images.
+// A full-dashboard render returns a single image; with schedule.PanelIDs set, one image per panel
+// is returned in the listed order.
+func (r *ChromiumRenderer) RenderImages(ctx context.Context, schedule *model.Schedule, opts Options) ([][]byte, error) {
+	format, err := screenshotFormat(schedule.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	saToken, err := r.getServiceAccountToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("no service account token available: %w", err)
+	}
+	if saToken == "" {
+		return nil, fmt.Errorf("service account token is empty; configure it in plugin settings or enable managed service accounts")
+	}
+
+	if len(schedule.PanelIDs) > 0 {
+		images, _, err := r.capturePanels(schedule, opts, saToken, format, false)
+		return images, err
+	}
+
+	dashboardURL, err := r.buildDashboardURL(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dashboard URL: %w", err)
+	}
+
+	page, cleanup, err := r.openPage(saToken, r.config.ViewportWidth, r.config.ViewportHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	if err := r.loadDashboard(page, dashboardURL); err != nil {
+		return nil, err
+	}
+	applyTemplateWatermark(page, opts.Template)
+
+	image, err := page.Screenshot(true, screenshotParams(format))
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture dashboard: %w", err)
+	}
+
+	log.Printf("DEBUG: Captured %s image of dashboard %s (%d bytes)", format, schedule.DashboardUID, len(image))
+	return [][]byte{image}, nil
+}
+
+// screenshotFormat maps a schedule image format to the Chrome screenshot format
+func screenshotFormat(format string) (proto.PageCaptureScreenshotFormat, error) {
+	switch format {
+	case model.FormatPNG:
+		return proto.PageCaptureScreenshotFormatPng, nil
+	case model.FormatJPEG:
+		return proto.PageCaptureScreenshotFormatJpeg, nil
+	default:
+		return "", fmt.Errorf("unsupported image format '%s'", format)
+	}
+}
+
+// screenshotParams returns capture parameters for the given image format
+func screenshotParams(format proto.PageCaptureScreenshotFormat) *proto.PageCaptureScreenshot {
+	params := &proto.PageCaptureScreenshot{Format: format}
+	if format == proto.PageCaptureScreenshotFormatJpeg {
+		quality := jpegQuality
+		params.Quality = &quality
+	}
+	return params
+}
+
+// applyTemplateWatermark draws the template watermark (if any) into the page
+func applyTemplateWatermark(page *rod.Page, tmpl *model.TemplateConfig) {
+	if tmpl == nil || tmpl.Watermark == "" {
+		return
+	}
+	if err := applyWatermark(page, tmpl.Watermark); err != nil {
+		log.Printf("WARNING: Failed to apply watermark: %v", err)
+	}
+}
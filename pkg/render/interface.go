@@ -4,12 +4,35 @@ import (
 	"context"
 
 	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
 )
 
+// Options holds per-report rendering options resolved by the scheduler
+type Options struct {
+	// Template applies page layout and branding; nil renders the dashboard on a single page
+	Template *model.TemplateConfig
+	// Vars holds values for {{placeholder}} substitution in the template header and footer
+	Vars map[string]string
+}
+
+// Document is a dashboard rendered to PDF
+type Document struct {
+	Data []byte
+	// Contents locates the titled rows and panels of the dashboard in Data, for a table of contents
+	Contents []pdf.Entry
+}
+
 // Backend defines the interface for rendering backends
 type Backend interface {
 	// RenderDashboard renders a Grafana dashboard to PDF
-	RenderDashboard(ctx context.Context, schedule *model.Schedule) ([]byte, error)
+	RenderDashboard(ctx context.Context, schedule *model.Schedule, opts Options) (*Document, error)
+
+	// RenderImages renders a Grafana dashboard to images in schedule.Format (PNG or JPEG):
+	// one full-dashboard image, or one image per panel when schedule.PanelIDs is set
+	RenderImages(ctx context.Context, schedule *model.Schedule, opts Options) ([][]byte, error)
+
+	// FetchLogo downloads a template logo with the backend's Grafana credentials
+	FetchLogo(ctx context.Context, logoURL string) ([]byte, error)
 
 	// Close cleans up resources used by the backend
 	Close() error
@@ -0,0 +1,102 @@
+package render
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
+)
+
+// outlineItem is a titled row or panel of the loaded dashboard, with its top in CSS pixels
+type outlineItem struct {
+	Title string  `json:"title"`
+	Row   bool    `json:"row"`
+	Top   float64 `json:"top"`
+}
+
+// dashboardOutline lists the titled rows and panels of the loaded page from top to bottom
+func dashboardOutline(page *rod.Page) ([]outlineItem, error) {
+	result, err := page.Eval(`() => {
+		const rowPrefix = 'data-testid dashboard-row-title-';
+		const panelPrefix = 'data-testid Panel header ';
+		const scrollY = window.scrollY;
+		const items = [];
+		const seen = new Set();
+		document.querySelectorAll('[data-testid^="' + rowPrefix + '"], [data-testid^="' + panelPrefix + '"]').forEach(el => {
+			if (seen.has(el)) return;
+			seen.add(el);
+			const testID = el.getAttribute('data-testid');
+			const row = testID.startsWith(rowPrefix);
+			const title = testID.slice((row ? rowPrefix : panelPrefix).length).trim();
+			if (!title) return;
+			items.push({ title: title, row: row, top: el.getBoundingClientRect().top + scrollY });
+		});
+		items.sort((a, b) => a.top - b.top);
+		return items;
+	}`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dashboard outline: %w", err)
+	}
+
+	var items []outlineItem
+	if err := result.Value.Unmarshal(&items); err != nil {
+		return nil, fmt.Errorf("failed to read dashboard outline: %w", err)
+	}
+	return items, nil
+}
+
+// outlineEntries converts outline items into table of contents entries, locating each with position.
+// Panels are nested below the row above them; panels before the first row stay at the top level.
+func outlineEntries(items []outlineItem, position func(top float64) (int, float64)) []pdf.Entry {
+	entries := make([]pdf.Entry, 0, len(items))
+	inRow := false
+	for _, item := range items {
+		level := 0
+		if item.Row {
+			inRow = true
+		} else if inRow {
+			level = 1
+		}
+		page, y := position(item.Top)
+		entries = append(entries, pdf.Entry{Title: item.Title, Level: level, Page: page, Y: y})
+	}
+	return entries
+}
+
+// printPosition maps a position in the dashboard (CSS pixels) to a page and millimeters from the
+// top of that page in the PDF printed by Chrome with params
+func printPosition(top float64, params *proto.PagePrintToPDF) (int, float64) {
+	value := func(p *float64, fallback float64) float64 {
+		if p == nil {
+			return fallback
+		}
+		return *p
+	}
+	scale := value(params.Scale, 1)
+	marginTop := value(params.MarginTop, 0)
+	printable := value(params.PaperHeight, 0) - marginTop - value(params.MarginBottom, 0)
+
+	y := math.Max(top, 0) / 96.0 * scale
+	if printable <= 0 {
+		return 1, (marginTop + y) * mmPerInch
+	}
+	page := math.Floor(y / printable)
+	return int(page) + 1, (marginTop + y - page*printable) * mmPerInch
+}
+
+// sectionPosition maps a position in the dashboard (CSS pixels) to the placement of the captured
+// section containing it in a paginated PDF
+func sectionPosition(top float64, sections []section, placements []pdf.Placement) (int, float64) {
+	if len(sections) == 0 || len(placements) < len(sections) {
+		return 1, 0
+	}
+	index := 0
+	for i, s := range sections {
+		if s.Top <= top {
+			index = i
+		}
+	}
+	return placements[index].Page, placements[index].Y
+}
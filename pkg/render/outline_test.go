@@ -0,0 +1,78 @@
+package render
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
+)
+
+func TestOutlineEntries(t *testing.T) {
+	items := []outlineItem{
+		{Title: "Summary", Top: 0},
+		{Title: "Traffic", Row: true, Top: 300},
+		{Title: "Requests", Top: 340},
+		{Title: "Errors", Row: true, Top: 700},
+	}
+	entries := outlineEntries(items, func(top float64) (int, float64) {
+		return int(top/500) + 1, top / 10
+	})
+
+	want := []pdf.Entry{
+		{Title: "Summary", Level: 0, Page: 1, Y: 0},
+		{Title: "Traffic", Level: 0, Page: 1, Y: 30},
+		{Title: "Requests", Level: 1, Page: 1, Y: 34},
+		{Title: "Errors", Level: 0, Page: 2, Y: 70},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("outlineEntries() = %+v, want %+v", entries, want)
+	}
+}
+
+func TestPrintPosition(t *testing.T) {
+	// Without a template the dashboard is printed on one page without margins
+	single := buildPrintParams(1920, 3000, nil, "", "")
+	if page, y := printPosition(960, single); page != 1 || math.Abs(y-254) > 0.01 {
+		t.Errorf("single page: printPosition() = %d, %.2fmm, want 1, 254mm", page, y)
+	}
+
+	// A4 landscape with 10mm margins (15mm top for the logo, 12mm bottom for the footer):
+	// 1920px is scaled to the printable width and the height is paginated
+	tmpl := &model.TemplateConfig{PageSize: "A4", LogoURL: "/logo.png"}
+	params := buildPrintParams(1920, 3000, tmpl, "", "")
+	printable := (*params.PaperHeight - *params.MarginTop - *params.MarginBottom) * mmPerInch
+	pxPerPage := printable / mmPerInch * 96 / *params.Scale
+
+	if page, y := printPosition(0, params); page != 1 || math.Abs(y-15) > 0.01 {
+		t.Errorf("top: printPosition() = %d, %.2fmm, want 1, 15mm", page, y)
+	}
+	if page, y := printPosition(pxPerPage*1.5, params); page != 2 || math.Abs(y-(15+printable/2)) > 0.01 {
+		t.Errorf("second page: printPosition() = %d, %.2fmm, want 2, %.2fmm", page, y, 15+printable/2)
+	}
+}
+
+func TestSectionPosition(t *testing.T) {
+	sections := []section{{Top: 0, Bottom: 200}, {Top: 200, Bottom: 900}, {Top: 900, Bottom: 1000}}
+	placements := []pdf.Placement{{Page: 1, Y: 10}, {Page: 1, Y: 60}, {Page: 3, Y: 10}}
+
+	tests := []struct {
+		top      float64
+		wantPage int
+		wantY    float64
+	}{
+		{0, 1, 10},
+		{250, 1, 60},
+		{950, 3, 10},
+	}
+	for _, tt := range tests {
+		if page, y := sectionPosition(tt.top, sections, placements); page != tt.wantPage || y != tt.wantY {
+			t.Errorf("sectionPosition(%v) = %d, %v, want %d, %v", tt.top, page, y, tt.wantPage, tt.wantY)
+		}
+	}
+
+	if page, y := sectionPosition(100, nil, nil); page != 1 || y != 0 {
+		t.Errorf("sectionPosition() without sections = %d, %v, want 1, 0", page, y)
+	}
+}
@@ -0,0 +1,286 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/pdf"
+)
+
+// renderPanels captures each selected panel in the listed order via the d-solo route
+// and composes them into a PDF with one panel per page
+func (r *ChromiumRenderer) renderPanels(ctx context.Context, schedule *model.Schedule, opts Options, saToken string) (*Document, error) {
+	images, titles, err := r.capturePanels(schedule, opts, saToken, proto.PageCaptureScreenshotFormatPng, true)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := pdf.NewGenerator().Generate(images, r.pdfOptions(ctx, schedule, opts, saToken))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose panel PDF: %w", err)
+	}
+
+	contents := make([]pdf.Entry, 0, len(titles))
+	for i, title := range titles {
+		if title == "" {
+			title = fmt.Sprintf("Panel %d", schedule.PanelIDs[i])
+		}
+		contents = append(contents, pdf.Entry{Title: title, Page: i + 1})
+	}
+
+	log.Printf("DEBUG: Composed PDF with %d panel page(s) for schedule %d", len(images), schedule.ID)
+	return &Document{Data: data, Contents: contents}, nil
+}
+
+// capturePanels takes a screenshot of every panel listed in schedule.PanelIDs, returning the
+// panel titles (empty when the panel has none) alongside the images.
+// The viewport matches the printable area of the page so each panel fills one page.
+// With forPDF set, backgrounds are removed as for printing; otherwise the template watermark is
+// drawn into the page before each capture, as the images are delivered as they are.
+func (r *ChromiumRenderer) capturePanels(schedule *model.Schedule, opts Options, saToken string, format proto.PageCaptureScreenshotFormat, forPDF bool) ([][]byte, []string, error) {
+	width, height := panelViewport(r.config.ViewportWidth, opts.Template)
+
+	page, cleanup, err := r.openPage(saToken, width, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cleanup()
+
+	images := make([][]byte, 0, len(schedule.PanelIDs))
+	titles := make([]string, 0, len(schedule.PanelIDs))
+	for _, panelID := range schedule.PanelIDs {
+		panelURL, err := r.buildPanelURL(schedule, panelID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build URL for panel %d: %w", panelID, err)
+		}
+
+		log.Printf("DEBUG: Capturing panel %d of dashboard %s (%dx%d)", panelID, schedule.DashboardUID, width, height)
+		if err := r.loadDashboard(page, panelURL); err != nil {
+			return nil, nil, fmt.Errorf("panel %d: %w", panelID, err)
+		}
+
+		var title string
+		if outline, err := dashboardOutline(page); err != nil {
+			log.Printf("WARNING: Panel %d: %v", panelID, err)
+		} else if len(outline) > 0 {
+			title = outline[0].Title
+		}
+		titles = append(titles, title)
+
+		if forPDF {
+			applyPrintBackground(page, schedule, opts.Template)
+		} else {
+			applyTemplateWatermark(page, opts.Template)
+		}
+
+		image, err := page.Screenshot(false, screenshotParams(format))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to capture panel %d: %w", panelID, err)
+		}
+		images = append(images, image)
+	}
+
+	return images, titles, nil
+}
+
+// sectionPadding is the space in pixels kept above and below each captured dashboard section
+const sectionPadding = 4.0
+
+// section is a vertical span of the dashboard in CSS pixels
+type section struct {
+	Top    float64
+	Bottom float64
+}
+
+// renderPaginated captures the loaded dashboard as row-sized screenshots and lays them onto
+// standard paper pages, so long dashboards are never truncated
+func (r *ChromiumRenderer) renderPaginated(ctx context.Context, page *rod.Page, schedule *model.Schedule, opts Options, saToken string) (*Document, error) {
+	outline, err := dashboardOutline(page)
+	if err != nil {
+		log.Printf("WARNING: %v", err)
+	}
+
+	applyPrintBackground(page, schedule, opts.Template)
+	images, sections, err := captureSections(page)
+	if err != nil {
+		return nil, err
+	}
+
+	data, placements, err := pdf.NewGenerator().GenerateFlow(images, r.pdfOptions(ctx, schedule, opts, saToken))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose paginated PDF: %w", err)
+	}
+	contents := outlineEntries(outline, func(top float64) (int, float64) {
+		return sectionPosition(top, sections, placements)
+	})
+
+	log.Printf("DEBUG: Composed paginated PDF from %d section(s) for schedule %d", len(images), schedule.ID)
+	return &Document{Data: data, Contents: contents}, nil
+}
+
+// captureSections screenshots the dashboard one row of panels at a time, returning the captured
+// sections alongside the images. Panels placed side by side end up in the same section.
+func captureSections(page *rod.Page) ([][]byte, []section, error) {
+	result, err := page.Eval(`() => {
+		const body = document.body;
+		const html = document.documentElement;
+		const scrollY = window.scrollY;
+		const panels = Array.from(document.querySelectorAll('.react-grid-item')).map(el => {
+			const rect = el.getBoundingClientRect();
+			return { top: rect.top + scrollY, bottom: rect.bottom + scrollY };
+		});
+		return {
+			width: Math.max(body.scrollWidth, html.clientWidth, html.scrollWidth),
+			height: Math.max(body.scrollHeight, body.offsetHeight, html.clientHeight, html.scrollHeight, html.offsetHeight),
+			panels: panels,
+		};
+	}`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to measure dashboard layout: %w", err)
+	}
+
+	var layout struct {
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+		Panels []struct {
+			Top    float64 `json:"top"`
+			Bottom float64 `json:"bottom"`
+		} `json:"panels"`
+	}
+	if err := result.Value.Unmarshal(&layout); err != nil {
+		return nil, nil, fmt.Errorf("failed to read dashboard layout: %w", err)
+	}
+
+	spans := make([]section, 0, len(layout.Panels))
+	for _, p := range layout.Panels {
+		spans = append(spans, section{Top: p.Top, Bottom: p.Bottom})
+	}
+	sections := groupSections(spans, layout.Height)
+	log.Printf("DEBUG: Dashboard is %.0fpx x %.0fpx with %d panel(s) in %d section(s)",
+		layout.Width, layout.Height, len(layout.Panels), len(sections))
+
+	images := make([][]byte, 0, len(sections))
+	for i, s := range sections {
+		image, err := page.Screenshot(false, &proto.PageCaptureScreenshot{
+			Format: proto.PageCaptureScreenshotFormatPng,
+			Clip: &proto.PageViewport{
+				X:      0,
+				Y:      s.Top,
+				Width:  layout.Width,
+				Height: s.Bottom - s.Top,
+				Scale:  1,
+			},
+			CaptureBeyondViewport: true,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to capture section %d: %w", i+1, err)
+		}
+		images = append(images, image)
+	}
+
+	return images, sections, nil
+}
+
+// groupSections merges vertically overlapping panel spans into sections ordered top to bottom.
+// Without any panels the whole page becomes a single section.
+func groupSections(spans []section, pageHeight float64) []section {
+	valid := make([]section, 0, len(spans))
+	for _, s := range spans {
+		if s.Bottom > s.Top {
+			valid = append(valid, s)
+		}
+	}
+	if len(valid) == 0 {
+		if pageHeight <= 0 {
+			return nil
+		}
+		return []section{{Top: 0, Bottom: pageHeight}}
+	}
+
+	sort.Slice(valid, func(i, j int) bool { return valid[i].Top < valid[j].Top })
+
+	sections := []section{valid[0]}
+	for _, s := range valid[1:] {
+		last := &sections[len(sections)-1]
+		if s.Top < last.Bottom {
+			last.Bottom = math.Max(last.Bottom, s.Bottom)
+			continue
+		}
+		sections = append(sections, s)
+	}
+
+	for i := range sections {
+		sections[i].Top = math.Max(0, sections[i].Top-sectionPadding)
+		sections[i].Bottom += sectionPadding
+		if pageHeight > 0 {
+			sections[i].Bottom = math.Min(pageHeight, sections[i].Bottom)
+		}
+	}
+
+	return sections
+}
+
+// panelViewport returns a viewport with the aspect ratio of the printable page area
+// (A4 landscape unless the template selects another page size or orientation)
+func panelViewport(viewportWidth int, tmpl *model.TemplateConfig) (int, int) {
+	pageSize, orientation := "A4", "landscape"
+	margins := model.Margins{Top: 10, Bottom: 10, Left: 10, Right: 10}
+	if tmpl != nil {
+		if tmpl.PageSize != "" {
+			pageSize = tmpl.PageSize
+		}
+		if tmpl.Orientation != "" {
+			orientation = tmpl.Orientation
+		}
+		if tmpl.Margins != nil {
+			margins = *tmpl.Margins
+		}
+	}
+
+	width, height, _ := paperSize(pageSize, orientation)
+	printableWidth := width*mmPerInch - margins.Left - margins.Right
+	printableHeight := height*mmPerInch - margins.Top - margins.Bottom
+	if printableWidth <= 0 || printableHeight <= 0 {
+		return viewportWidth, viewportWidth * 9 / 16
+	}
+
+	return viewportWidth, int(float64(viewportWidth) * printableHeight / printableWidth)
+}
+
+// pdfOptions converts the schedule template into options for the PDF generator
+func (r *ChromiumRenderer) pdfOptions(ctx context.Context, schedule *model.Schedule, opts Options, saToken string) pdf.Options {
+	pdfOpts := pdf.Options{Title: schedule.Name}
+	tmpl := opts.Template
+	if tmpl == nil {
+		return pdfOpts
+	}
+
+	footer := tmpl.Footer
+	if footer == "" {
+		footer = defaultTemplateFooter
+	}
+
+	pdfOpts.Orientation = tmpl.Orientation
+	pdfOpts.PageSize = tmpl.PageSize
+	pdfOpts.Margins = tmpl.Margins
+	pdfOpts.Watermark = tmpl.Watermark
+	pdfOpts.Header = interpolatePlain(tmpl.Header, opts.Vars)
+	pdfOpts.Footer = interpolatePlain(footer, opts.Vars)
+
+	if tmpl.LogoURL != "" {
+		logo, _, err := r.fetchLogo(ctx, tmpl.LogoURL, saToken)
+		if err != nil {
+			log.Printf("WARNING: Failed to load template logo %s: %v", tmpl.LogoURL, err)
+		} else {
+			pdfOpts.Logo = logo
+		}
+	}
+
+	return pdfOpts
+}
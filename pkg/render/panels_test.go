@@ -0,0 +1,57 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupSections(t *testing.T) {
+	tests := []struct {
+		name       string
+		spans      []section
+		pageHeight float64
+		want       []section
+	}{
+		{
+			name:       "no panels falls back to whole page",
+			spans:      nil,
+			pageHeight: 900,
+			want:       []section{{Top: 0, Bottom: 900}},
+		},
+		{
+			name: "side by side panels share a section",
+			spans: []section{
+				{Top: 300, Bottom: 600},
+				{Top: 10, Bottom: 300},
+				{Top: 10, Bottom: 250},
+			},
+			pageHeight: 1000,
+			want: []section{
+				{Top: 6, Bottom: 304},
+				{Top: 296, Bottom: 604},
+			},
+		},
+		{
+			name: "taller neighbour extends the section",
+			spans: []section{
+				{Top: 0, Bottom: 200},
+				{Top: 100, Bottom: 500},
+				{Top: 510, Bottom: 700},
+				{Top: 50, Bottom: 50},
+			},
+			pageHeight: 702,
+			want: []section{
+				{Top: 0, Bottom: 504},
+				{Top: 506, Bottom: 702},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupSections(tt.spans, tt.pageHeight); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupSections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,13 +4,16 @@
 package render
 
 import (
+	"bytes"
 	"context"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/yourusername/scheduled-reports-app/pkg/model"
 )
 
@@ -85,11 +88,12 @@ func TestRenderDashboard_Integration(t *testing.T) {
 
 	// Render dashboard
 	ctx := context.Background()
-	imageData, err := r.RenderDashboard(ctx, schedule)
+	doc, err := r.RenderDashboard(ctx, schedule, Options{})
 
 	if err != nil {
 		t.Fatalf("RenderDashboard() error = %v", err)
 	}
+	imageData := doc.Data
 
 	// Verify PNG format
 	if len(imageData) < 8 {
@@ -227,7 +231,7 @@ func TestRenderTimeout_Integration(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	_, err := r.RenderDashboard(ctx, schedule)
+	_, err := r.RenderDashboard(ctx, schedule, Options{})
 
 	if err == nil {
 		t.Error("Expected timeout error, got nil")
@@ -282,11 +286,12 @@ func TestMultiplePagesSequential_Integration(t *testing.T) {
 		}
 
 		ctx := context.Background()
-		imageData, err := r.RenderDashboard(ctx, schedule)
+		doc, err := r.RenderDashboard(ctx, schedule, Options{})
 
 		if err != nil {
 			t.Fatalf("RenderDashboard() iteration %d error = %v", i, err)
 		}
+		imageData := doc.Data
 
 		if len(imageData) < 8 || string(imageData[1:4]) != "PNG" {
 			t.Errorf("Invalid PNG data in iteration %d", i)
@@ -345,7 +350,7 @@ func TestRenderWithVariables_Integration(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	_, err := r.RenderDashboard(ctx, schedule)
+	_, err := r.RenderDashboard(ctx, schedule, Options{})
 
 	if err != nil {
 		t.Fatalf("RenderDashboard() error = %v", err)
@@ -370,6 +375,115 @@ func TestRenderWithVariables_Integration(t *testing.T) {
 	t.Logf("Variables rendered correctly in URL: %v", receivedURL)
 }
 
+// TestPrintBackgroundLayouts_Integration tests that dark reports leave out page backgrounds in the
+// layouts composed from screenshots, unless the template prints them
+func TestPrintBackgroundLayouts_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	if !isChromiumAvailable() {
+		t.Skip("Chromium not available, skipping integration test")
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!DOCTYPE html><html><body style="margin: 0; background-color: #111217;">
+<div class="react-grid-item" style="height: 300px; background-color: #181b1f; color: #ccccdc;">Panel</div>
+</body></html>`))
+	}))
+	defer mockServer.Close()
+
+	os.Setenv("GF_PLUGIN_SA_TOKEN", "test-token")
+	defer os.Unsetenv("GF_PLUGIN_SA_TOKEN")
+
+	config := model.RendererConfig{
+		TimeoutMS:      30000,
+		ViewportWidth:  800,
+		ViewportHeight: 600,
+		Headless:       true,
+		NoSandbox:      true,
+		DisableGPU:     true,
+	}
+	r := NewChromiumRenderer(mockServer.URL, config)
+	defer r.Close()
+
+	printed := true
+	tests := []struct {
+		name      string
+		tmpl      *model.TemplateConfig
+		wantWhite bool
+	}{
+		{"dark theme", nil, true},
+		{"template prints backgrounds", &model.TemplateConfig{PrintBackground: &printed}, false},
+	}
+
+	// cornerIsWhite reports whether the top left pixel of a PNG screenshot is white
+	cornerIsWhite := func(t *testing.T, data []byte) bool {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to decode screenshot: %v", err)
+		}
+		red, green, blue, _ := img.At(2, 2).RGBA()
+		return red>>8 == 0xff && green>>8 == 0xff && blue>>8 == 0xff
+	}
+
+	for _, tt := range tests {
+		schedule := &model.Schedule{DashboardUID: "test-dash", RangeFrom: "now-1h", RangeTo: "now", OrgID: 1, Timezone: "UTC", Theme: model.ThemeDark}
+
+		t.Run("panels/"+tt.name, func(t *testing.T) {
+			panels := *schedule
+			panels.PanelIDs = []int64{1}
+			images, _, err := r.capturePanels(&panels, Options{Template: tt.tmpl}, "test-token", proto.PageCaptureScreenshotFormatPng, true)
+			if err != nil {
+				t.Fatalf("capturePanels() error = %v", err)
+			}
+			if got := cornerIsWhite(t, images[0]); got != tt.wantWhite {
+				t.Errorf("panel background white = %v, want %v", got, tt.wantWhite)
+			}
+		})
+
+		t.Run("paginated/"+tt.name, func(t *testing.T) {
+			dashboardURL, err := r.buildDashboardURL(schedule)
+			if err != nil {
+				t.Fatalf("buildDashboardURL() error = %v", err)
+			}
+			page, cleanup, err := r.openPage("test-token", config.ViewportWidth, config.ViewportHeight)
+			if err != nil {
+				t.Fatalf("openPage() error = %v", err)
+			}
+			defer cleanup()
+			if err := r.loadDashboard(page, dashboardURL); err != nil {
+				t.Fatalf("loadDashboard() error = %v", err)
+			}
+
+			// As renderPaginated does before capturing the sections
+			applyPrintBackground(page, schedule, tt.tmpl)
+			images, _, err := captureSections(page)
+			if err != nil {
+				t.Fatalf("captureSections() error = %v", err)
+			}
+			if got := cornerIsWhite(t, images[0]); got != tt.wantWhite {
+				t.Errorf("section background white = %v, want %v", got, tt.wantWhite)
+			}
+		})
+
+		t.Run("single/"+tt.name, func(t *testing.T) {
+			doc, err := r.RenderDashboard(context.Background(), schedule, Options{Template: tt.tmpl})
+			if err != nil {
+				t.Fatalf("RenderDashboard() error = %v", err)
+			}
+			if !bytes.HasPrefix(doc.Data, []byte("%PDF")) {
+				t.Errorf("RenderDashboard() did not return a PDF")
+			}
+			// Chrome leaves the backgrounds out of the printed PDF itself
+			if got := printBackground(schedule, tt.tmpl); got == tt.wantWhite {
+				t.Errorf("printBackground() = %v, want %v", got, !tt.wantWhite)
+			}
+		})
+	}
+}
+
 // Helper function to check if Chromium is available
 func isChromiumAvailable() bool {
 	// Try to find Chromium in common locations
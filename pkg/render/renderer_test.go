@@ -127,9 +127,26 @@ func TestBuildDashboardURL(t *testing.T) {
 				"from=now-7d",
 				"to=now",
 				"kiosk=1",
+				"theme=light",
 				"tz=UTC",
 			},
 		},
+		{
+			name:       "with theme",
+			grafanaURL: "http://localhost:3000",
+			schedule: &model.Schedule{
+				DashboardUID: "abc123",
+				RangeFrom:    "now-7d",
+				RangeTo:      "now",
+				OrgID:        1,
+				Timezone:     "UTC",
+				Theme:        model.ThemeDark,
+			},
+			wantErr: false,
+			contains: []string{
+				"theme=dark",
+			},
+		},
 		{
 			name:       "with variables",
 			grafanaURL: "http://localhost:3000",
@@ -394,6 +411,51 @@ func TestScheduleTimezone(t *testing.T) {
 	}
 }
 
+func TestBuildPanelURL(t *testing.T) {
+	r := NewChromiumRenderer("http://localhost:3000/grafana", model.RendererConfig{})
+	schedule := &model.Schedule{
+		DashboardUID: "abc123",
+		RangeFrom:    "now-6h",
+		RangeTo:      "now",
+		OrgID:        2,
+		Variables:    model.VariableList{{Name: "env", Value: "prod"}},
+	}
+
+	url, err := r.buildPanelURL(schedule, 7)
+	if err != nil {
+		t.Fatalf("buildPanelURL() error = %v", err)
+	}
+
+	for _, substr := range []string{"/grafana/d-solo/abc123", "panelId=7", "from=now-6h", "var-env=prod"} {
+		if !contains(url, substr) {
+			t.Errorf("buildPanelURL() = %v, should contain %v", url, substr)
+		}
+	}
+}
+
+func TestPanelViewport(t *testing.T) {
+	tests := []struct {
+		name       string
+		tmpl       *model.TemplateConfig
+		wantTaller bool
+	}{
+		{name: "default A4 landscape", tmpl: nil, wantTaller: false},
+		{name: "portrait template", tmpl: &model.TemplateConfig{PageSize: "A4", Orientation: "portrait"}, wantTaller: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := panelViewport(1600, tt.tmpl)
+			if width != 1600 {
+				t.Errorf("panelViewport() width = %d, want 1600", width)
+			}
+			if (height > width) != tt.wantTaller {
+				t.Errorf("panelViewport() = %dx%d, taller than wide = %v, want %v", width, height, height > width, tt.wantTaller)
+			}
+		})
+	}
+}
+
 // Benchmark tests
 func BenchmarkNewRenderer(b *testing.B) {
 	config := model.RendererConfig{}
@@ -441,6 +503,9 @@ func findSubstring(s, substr string) bool {
 
 // Table-driven test for localhost conversion
 func TestLocalhostConversion(t *testing.T) {
+	// Conversion only happens for Docker deployments that set GRAFANA_HOSTNAME
+	t.Setenv("GRAFANA_HOSTNAME", "grafana")
+
 	tests := []struct {
 		name     string
 		inputURL string
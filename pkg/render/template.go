@@ -0,0 +1,324 @@
+package render
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// mmPerInch converts template margins (millimeters) to Chrome print units (inches)
+const mmPerInch = 25.4
+
+// maxLogoBytes caps the size of a logo embedded into the header
+const maxLogoBytes = 2 << 20
+
+// defaultTemplateFooter is used when a template is applied without an explicit footer
+const defaultTemplateFooter = "Page {{page}} of {{pages}}"
+
+// paperSizesInches maps supported template page sizes to portrait width x height in inches
+var paperSizesInches = map[string][2]float64{
+	"A3":     {11.69, 16.54},
+	"A4":     {8.27, 11.69},
+	"A5":     {5.83, 8.27},
+	"Letter": {8.5, 11},
+	"Legal":  {8.5, 14},
+}
+
+// paperSize returns the paper width and height in inches for a page size and orientation.
+// Dashboards are wide, so landscape is used unless portrait is requested explicitly.
+func paperSize(pageSize, orientation string) (float64, float64, bool) {
+	size, ok := paperSizesInches[pageSize]
+	if !ok {
+		return 0, 0, false
+	}
+	if orientation == "portrait" {
+		return size[0], size[1], true
+	}
+	return size[1], size[0], true
+}
+
+// pageMargins returns the template margins in inches, reserving room for header and footer
+func pageMargins(tmpl *model.TemplateConfig) (top, bottom, left, right float64) {
+	m := model.Margins{Top: 10, Bottom: 10, Left: 10, Right: 10}
+	if tmpl.Margins != nil {
+		m = *tmpl.Margins
+	}
+
+	// Chrome draws header and footer inside the margin area
+	if tmpl.Header != "" || tmpl.LogoURL != "" {
+		m.Top = max(m.Top, 15)
+	}
+	m.Bottom = max(m.Bottom, 12)
+
+	return m.Top / mmPerInch, m.Bottom / mmPerInch, m.Left / mmPerInch, m.Right / mmPerInch
+}
+
+// buildPrintParams builds the Chrome print parameters for the rendered content size.
+// Without a template the whole dashboard is printed on a single page sized to the content.
+// With a template, a fixed page size paginates the content (scaled to fit the page width),
+// and margins, header and footer are applied.
+func buildPrintParams(contentWidthPx, contentHeightPx float64, tmpl *model.TemplateConfig, headerHTML, footerHTML string) *proto.PagePrintToPDF {
+	f := func(x float64) *float64 { return &x }
+
+	// Convert actual content dimensions to inches (Chrome uses 96 DPI)
+	contentWidth := contentWidthPx / 96.0
+	contentHeight := contentHeightPx / 96.0
+
+	params := &proto.PagePrintToPDF{
+		PrintBackground:   true,
+		PreferCSSPageSize: false,
+		Scale:             f(1.0),
+	}
+
+	var marginTop, marginBottom, marginLeft, marginRight float64
+	if tmpl != nil {
+		marginTop, marginBottom, marginLeft, marginRight = pageMargins(tmpl)
+		params.DisplayHeaderFooter = true
+		params.HeaderTemplate = headerHTML
+		params.FooterTemplate = footerHTML
+	}
+	params.MarginTop = f(marginTop)
+	params.MarginBottom = f(marginBottom)
+	params.MarginLeft = f(marginLeft)
+	params.MarginRight = f(marginRight)
+
+	if tmpl != nil {
+		if width, height, ok := paperSize(tmpl.PageSize, tmpl.Orientation); ok {
+			// Shrink the dashboard to the printable width, Chrome paginates the height
+			printable := width - marginLeft - marginRight
+			scale := 1.0
+			if contentWidth > printable {
+				scale = printable / contentWidth
+			}
+			params.PaperWidth = f(width)
+			params.PaperHeight = f(height)
+			params.Scale = f(max(scale, 0.1)) // Chrome rejects scales below 0.1
+			return params
+		}
+	}
+
+	paperWidth := max(contentWidth, 8.0) + marginLeft + marginRight
+	paperHeight := max(contentHeight, 6.0) + marginTop + marginBottom
+
+	// Apply maximum dimensions (Chrome PDF has a limit of ~200 inches)
+	if paperHeight > 200.0 {
+		log.Printf("WARNING: Content height %.2f inches exceeds Chrome limit (200\"), capping at 200 inches", paperHeight)
+		log.Printf("WARNING: Some content may be cut off. Consider using a template page size or reducing dashboard height.")
+		paperHeight = 200.0
+	}
+	if paperWidth > 200.0 {
+		log.Printf("WARNING: Content width %.2f inches exceeds Chrome limit (200\"), capping at 200 inches", paperWidth)
+		paperWidth = 200.0
+	}
+
+	params.PaperWidth = f(paperWidth)
+	params.PaperHeight = f(paperHeight)
+	return params
+}
+
+// scheduleTheme returns the Grafana theme the schedule is rendered in, light by default
+func scheduleTheme(schedule *model.Schedule) string {
+	if schedule.Theme == "" {
+		return model.ThemeLight
+	}
+	return schedule.Theme
+}
+
+// printBackground reports whether page backgrounds are printed in PDF reports, by Chrome or in the
+// screenshots composed into them. Dark reports skip them by default so printed copies don't waste
+// ink; the template can override this either way.
+func printBackground(schedule *model.Schedule, tmpl *model.TemplateConfig) bool {
+	if tmpl != nil && tmpl.PrintBackground != nil {
+		return *tmpl.PrintBackground
+	}
+	return scheduleTheme(schedule) != model.ThemeDark
+}
+
+// applyPrintBackground removes the page backgrounds before screenshots are composed into a PDF when
+// printBackground says they aren't printed, the way Chrome leaves them out of printed layouts.
+// Panel contents drawn as canvas, SVG or images are kept.
+func applyPrintBackground(page *rod.Page, schedule *model.Schedule, tmpl *model.TemplateConfig) {
+	if printBackground(schedule, tmpl) {
+		return
+	}
+	_, err := page.Eval(`() => {
+		const style = document.createElement('style');
+		style.setAttribute('data-report-background', 'none');
+		style.textContent =
+			'*, *::before, *::after { background-color: transparent !important; background-image: none !important; box-shadow: none !important; }' +
+			'html, body { background: #ffffff !important; }';
+		document.head.appendChild(style);
+	}`)
+	if err != nil {
+		log.Printf("WARNING: Failed to remove page backgrounds: %v", err)
+	}
+}
+
+// interpolateHeaderFooter resolves {{placeholders}} in a header/footer template.
+// Values are HTML-escaped; {{page}}, {{pages}} and {{date}} map to Chrome's print counters.
+func interpolateHeaderFooter(text string, vars map[string]string) string {
+	result := text
+	for k, v := range vars {
+		result = strings.ReplaceAll(result, "{{"+k+"}}", html.EscapeString(v))
+	}
+	result = strings.ReplaceAll(result, "{{page}}", `<span class="pageNumber"></span>`)
+	result = strings.ReplaceAll(result, "{{pages}}", `<span class="totalPages"></span>`)
+	result = strings.ReplaceAll(result, "{{date}}", `<span class="date"></span>`)
+	return result
+}
+
+// interpolatePlain resolves {{placeholders}} without escaping, leaving page number placeholders intact
+func interpolatePlain(text string, vars map[string]string) string {
+	result := text
+	for k, v := range vars {
+		result = strings.ReplaceAll(result, "{{"+k+"}}", v)
+	}
+	return result
+}
+
+// buildHeaderHTML builds the Chrome header template with optional logo
+func buildHeaderHTML(tmpl *model.TemplateConfig, logoDataURI string, vars map[string]string) string {
+	var logo string
+	if logoDataURI != "" {
+		logo = fmt.Sprintf(`<img src="%s" style="max-height:8mm;max-width:40mm;">`, logoDataURI)
+	}
+	// Chrome header templates default to a zero font size, so it must be set explicitly
+	return fmt.Sprintf(
+		`<div style="font-size:9px;width:100%%;margin:0 10mm;display:flex;justify-content:space-between;align-items:center;-webkit-print-color-adjust:exact;">`+
+			`<span>%s</span><span>%s</span></div>`,
+		interpolateHeaderFooter(tmpl.Header, vars), logo,
+	)
+}
+
+// buildFooterHTML builds the Chrome footer template, defaulting to page numbers
+func buildFooterHTML(tmpl *model.TemplateConfig, vars map[string]string) string {
+	footer := tmpl.Footer
+	if footer == "" {
+		footer = defaultTemplateFooter
+	}
+	return fmt.Sprintf(
+		`<div style="font-size:8px;width:100%%;margin:0 10mm;text-align:center;">%s</div>`,
+		interpolateHeaderFooter(footer, vars),
+	)
+}
+
+// fetchLogoDataURI downloads the template logo and returns it as a data URI.
+// Chrome header/footer templates cannot load external resources, so the image is inlined.
+func (r *ChromiumRenderer) fetchLogoDataURI(ctx context.Context, logoURL, token string) (string, error) {
+	if strings.HasPrefix(logoURL, "data:") {
+		return logoURL, nil
+	}
+
+	data, contentType, err := r.fetchLogo(ctx, logoURL, token)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("data:%s;base64,%s", contentType, base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// fetchLogo returns the template logo image and its content type.
+// Relative URLs (e.g. /public/img/logo.svg) are resolved against the Grafana URL,
+// and base64 data URIs are decoded in place.
+func (r *ChromiumRenderer) fetchLogo(ctx context.Context, logoURL, token string) ([]byte, string, error) {
+	if strings.HasPrefix(logoURL, "data:") {
+		meta, encoded, ok := strings.Cut(strings.TrimPrefix(logoURL, "data:"), ",")
+		if !ok || !strings.HasSuffix(meta, ";base64") {
+			return nil, "", fmt.Errorf("unsupported logo data URI, expected base64 encoding")
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid logo data URI: %w", err)
+		}
+		return data, strings.TrimSuffix(meta, ";base64"), nil
+	}
+
+	base, err := url.Parse(r.grafanaURL)
+	if err != nil {
+		return nil, "", err
+	}
+	ref, err := url.Parse(logoURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid logo URL: %w", err)
+	}
+	if !ref.IsAbs() {
+		ref.Path = strings.TrimSuffix(base.Path, "/") + "/" + strings.TrimPrefix(ref.Path, "/")
+		ref = base.ResolveReference(ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref.String(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	// Only send the service account token to Grafana itself
+	if ref.Host == base.Host && token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: r.config.SkipTLSVerify},
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch logo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to fetch logo: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLogoBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read logo: %w", err)
+	}
+	if len(data) > maxLogoBytes {
+		return nil, "", fmt.Errorf("logo exceeds %d bytes", maxLogoBytes)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+
+	return data, contentType, nil
+}
+
+// applyWatermark overlays the watermark text on the page.
+// Fixed-position elements are repeated on every printed page by Chrome.
+func applyWatermark(page *rod.Page, text string) error {
+	_, err := page.Eval(`(text) => {
+		const mark = document.createElement('div');
+		mark.textContent = text;
+		mark.setAttribute('data-report-watermark', 'true');
+		Object.assign(mark.style, {
+			position: 'fixed',
+			top: '50%',
+			left: '50%',
+			transform: 'translate(-50%, -50%) rotate(-30deg)',
+			fontSize: '96px',
+			fontWeight: 'bold',
+			color: 'rgba(128, 128, 128, 0.18)',
+			whiteSpace: 'nowrap',
+			pointerEvents: 'none',
+			zIndex: '2147483647',
+		});
+		document.body.appendChild(mark);
+	}`, text)
+	return err
+}
@@ -0,0 +1,204 @@
+package render
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+func TestBuildPrintParams(t *testing.T) {
+	tests := []struct {
+		name            string
+		contentWidthPx  float64
+		contentHeightPx float64
+		template        *model.TemplateConfig
+		wantWidth       float64
+		wantHeight      float64
+		wantScale       float64
+		wantMarginTop   float64
+		wantHeaderFoot  bool
+	}{
+		{
+			name:            "no template - single page sized to content",
+			contentWidthPx:  1920,
+			contentHeightPx: 2880,
+			wantWidth:       20,
+			wantHeight:      30,
+			wantScale:       1,
+		},
+		{
+			name:            "no template - minimum dimensions",
+			contentWidthPx:  200,
+			contentHeightPx: 100,
+			wantWidth:       8,
+			wantHeight:      6,
+			wantScale:       1,
+		},
+		{
+			name:            "no template - height capped",
+			contentWidthPx:  1920,
+			contentHeightPx: 96 * 500,
+			wantWidth:       20,
+			wantHeight:      200,
+			wantScale:       1,
+		},
+		{
+			name:            "A4 landscape scales content to printable width",
+			contentWidthPx:  1920,
+			contentHeightPx: 4000,
+			template: &model.TemplateConfig{
+				PageSize: "A4",
+				Margins:  &model.Margins{Top: 25.4, Bottom: 25.4, Left: 25.4, Right: 25.4},
+			},
+			wantWidth:      11.69,
+			wantHeight:     8.27,
+			wantScale:      (11.69 - 2) / 20,
+			wantMarginTop:  1,
+			wantHeaderFoot: true,
+		},
+		{
+			name:            "Letter portrait keeps small content unscaled",
+			contentWidthPx:  600,
+			contentHeightPx: 800,
+			template:        &model.TemplateConfig{PageSize: "Letter", Orientation: "portrait"},
+			wantWidth:       8.5,
+			wantHeight:      11,
+			wantScale:       1,
+			wantMarginTop:   10 / mmPerInch,
+			wantHeaderFoot:  true,
+		},
+		{
+			name:            "template without page size keeps content-sized page plus margins",
+			contentWidthPx:  960,
+			contentHeightPx: 960,
+			template: &model.TemplateConfig{
+				Header:  "ACME",
+				Margins: &model.Margins{Top: 0, Bottom: 25.4, Left: 0, Right: 0},
+			},
+			wantWidth:      10,
+			wantHeight:     10 + 15/mmPerInch + 1,
+			wantScale:      1,
+			wantMarginTop:  15 / mmPerInch, // room reserved for the header
+			wantHeaderFoot: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := buildPrintParams(tt.contentWidthPx, tt.contentHeightPx, tt.template, "<div>h</div>", "<div>f</div>")
+
+			assertClose(t, "PaperWidth", *params.PaperWidth, tt.wantWidth)
+			assertClose(t, "PaperHeight", *params.PaperHeight, tt.wantHeight)
+			assertClose(t, "Scale", *params.Scale, tt.wantScale)
+			assertClose(t, "MarginTop", *params.MarginTop, tt.wantMarginTop)
+
+			if params.DisplayHeaderFooter != tt.wantHeaderFoot {
+				t.Errorf("DisplayHeaderFooter = %v, want %v", params.DisplayHeaderFooter, tt.wantHeaderFoot)
+			}
+			if !params.PrintBackground {
+				t.Error("PrintBackground should be enabled")
+			}
+		})
+	}
+}
+
+func TestPrintBackground(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name  string
+		theme string
+		tmpl  *model.TemplateConfig
+		want  bool
+	}{
+		{"light by default", "", nil, true},
+		{"dark skips backgrounds", model.ThemeDark, nil, false},
+		{"named theme prints backgrounds", "tron", &model.TemplateConfig{}, true},
+		{"template forces backgrounds", model.ThemeDark, &model.TemplateConfig{PrintBackground: &on}, true},
+		{"template disables backgrounds", model.ThemeLight, &model.TemplateConfig{PrintBackground: &off}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := printBackground(&model.Schedule{Theme: tt.theme}, tt.tmpl); got != tt.want {
+				t.Errorf("printBackground() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterpolateHeaderFooter(t *testing.T) {
+	vars := map[string]string{
+		"schedule.name":   "Weekly <Ops>",
+		"dashboard.title": "Overview",
+	}
+
+	got := interpolateHeaderFooter("{{schedule.name}} - {{dashboard.title}} | Page {{page}} of {{pages}}", vars)
+
+	for _, want := range []string{
+		"Weekly &lt;Ops&gt;",
+		"Overview",
+		`<span class="pageNumber"></span>`,
+		`<span class="totalPages"></span>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("interpolateHeaderFooter() = %q, should contain %q", got, want)
+		}
+	}
+}
+
+func TestBuildFooterHTMLDefaultsToPageNumbers(t *testing.T) {
+	footer := buildFooterHTML(&model.TemplateConfig{}, nil)
+	if !strings.Contains(footer, `class="pageNumber"`) {
+		t.Errorf("buildFooterHTML() = %q, want default page numbers", footer)
+	}
+}
+
+func TestFetchLogoDataURI(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/grafana/public/img/logo.png" {
+			http.NotFound(w, r)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png-bytes"))
+	}))
+	defer server.Close()
+
+	r := NewChromiumRenderer(server.URL+"/grafana", model.RendererConfig{})
+
+	// Relative URLs resolve against the Grafana sub-path and carry the token
+	uri, err := r.fetchLogoDataURI(context.Background(), "/public/img/logo.png", "secret")
+	if err != nil {
+		t.Fatalf("fetchLogoDataURI() error = %v", err)
+	}
+	if uri != "data:image/png;base64,cG5nLWJ5dGVz" {
+		t.Errorf("fetchLogoDataURI() = %q", uri)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization header = %q, want service account token", gotAuth)
+	}
+
+	// Data URIs are used as-is
+	inline := "data:image/svg+xml;base64,PHN2Zy8+"
+	if uri, _ := r.fetchLogoDataURI(context.Background(), inline, "secret"); uri != inline {
+		t.Errorf("fetchLogoDataURI() = %q, want data URI unchanged", uri)
+	}
+
+	if _, err := r.fetchLogoDataURI(context.Background(), "/public/img/missing.png", "secret"); err == nil {
+		t.Error("fetchLogoDataURI() expected error for missing logo")
+	}
+}
+
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 0.001 {
+		t.Errorf("%s = %.4f, want %.4f", name, got, want)
+	}
+}
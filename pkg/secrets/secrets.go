@@ -0,0 +1,134 @@
+// Package secrets encrypts secrets stored in the database, such as SMTP passwords, with AES-256-GCM.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// prefix marks encrypted values: "enc:v1:<key id>:<base64 nonce and ciphertext>"
+const prefix = "enc:v1:"
+
+// Environment variables the keys are read from. They are read once at startup, so encrypted
+// secrets can be read from the first scheduled run on.
+const (
+	KeyEnv         = "GF_PLUGIN_SECRETS_KEY"          // Current key
+	PreviousKeyEnv = "GF_PLUGIN_SECRETS_PREVIOUS_KEY" // Key being rotated from, still used for decryption
+)
+
+// ErrNoKey is returned when an encrypted value is read without a key configured
+var ErrNoKey = errors.New("secrets key is not configured")
+
+// key is an AES-256 key and the ID recorded in the values it encrypts
+type key struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// Keyring encrypts with its current key and decrypts with the current or any previous key,
+// so secrets encrypted before a key rotation can still be read until they are re-encrypted.
+type Keyring struct {
+	current  *key
+	previous []*key
+}
+
+// NewKeyring returns a keyring encrypting with current. Keys may be any string; they are hashed to
+// AES-256 keys, so long random values (e.g. from `openssl rand -base64 32`) should be used.
+// Empty previous keys are ignored.
+func NewKeyring(current string, previous ...string) (*Keyring, error) {
+	if current == "" {
+		return nil, ErrNoKey
+	}
+	k := &Keyring{current: newKey(current)}
+	for _, p := range previous {
+		if p != "" && p != current {
+			k.previous = append(k.previous, newKey(p))
+		}
+	}
+	return k, nil
+}
+
+// FromEnv returns the keyring configured by KeyEnv and PreviousKeyEnv, or nil when KeyEnv is not set
+func FromEnv() (*Keyring, error) {
+	current := os.Getenv(KeyEnv)
+	if current == "" {
+		if os.Getenv(PreviousKeyEnv) != "" {
+			return nil, fmt.Errorf("%s is set without %s", PreviousKeyEnv, KeyEnv)
+		}
+		return nil, nil
+	}
+	return NewKeyring(current, os.Getenv(PreviousKeyEnv))
+}
+
+// newKey derives an AES-256 key from secret
+func newKey(secret string) *key {
+	sum := sha256.Sum256([]byte(secret))
+	id := sha256.Sum256(sum[:])
+	block, _ := aes.NewCipher(sum[:]) // Never fails for 32 byte keys
+	aead, _ := cipher.NewGCM(block)
+	return &key{id: hex.EncodeToString(id[:4]), aead: aead}
+}
+
+// IsEncrypted reports whether value was encrypted by a keyring
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt encrypts plaintext with the current key. Empty values are returned unchanged. Values that
+// look encrypted are encrypted too: secrets are entered by users and may start with the prefix.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return plaintext, nil
+	}
+	nonce := make([]byte, k.current.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := k.current.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + k.current.id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value encrypted with any key of the keyring. Values that are not encrypted,
+// such as secrets stored before encryption was enabled, are returned unchanged.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	id, data, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	if k == nil {
+		return "", ErrNoKey
+	}
+
+	for _, key := range append([]*key{k.current}, k.previous...) {
+		if key.id != id {
+			continue
+		}
+		sealed, err := base64.StdEncoding.DecodeString(data)
+		if err != nil || len(sealed) < key.aead.NonceSize() {
+			return "", fmt.Errorf("malformed encrypted value")
+		}
+		nonce, ciphertext := sealed[:key.aead.NonceSize()], sealed[key.aead.NonceSize():]
+		plaintext, err := key.aead.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt secret: %w", err)
+		}
+		return string(plaintext), nil
+	}
+	return "", fmt.Errorf("secret was encrypted with an unknown key (%s)", id)
+}
+
+// Current reports whether value is empty or encrypted with the current key, and so needs no re-encryption
+func (k *Keyring) Current(value string) bool {
+	return value == "" || strings.HasPrefix(value, prefix+k.current.id+":")
+}
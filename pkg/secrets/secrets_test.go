@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeyring(t *testing.T) {
+	keyring, err := NewKeyring("first key")
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	encrypted, err := keyring.Encrypt("hunter2")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(encrypted, "hunter2") {
+		t.Fatalf("Encrypt() = %q, want an encrypted value", encrypted)
+	}
+	if again, _ := keyring.Encrypt("hunter2"); again == encrypted {
+		t.Error("Encrypt() returned the same ciphertext twice, want a fresh nonce")
+	}
+	// A secret that happens to look encrypted is encrypted like any other
+	if sealed, err := keyring.Encrypt(encrypted); err != nil || sealed == encrypted {
+		t.Errorf("Encrypt(%q) = %q, %v; want it encrypted", encrypted, sealed, err)
+	} else if plaintext, err := keyring.Decrypt(sealed); err != nil || plaintext != encrypted {
+		t.Errorf("Decrypt() = %q, %v; want %q back", plaintext, err, encrypted)
+	}
+	if empty, _ := keyring.Encrypt(""); empty != "" {
+		t.Errorf("Encrypt(\"\") = %q, want empty", empty)
+	}
+	if !keyring.Current(encrypted) || keyring.Current("hunter2") {
+		t.Error("Current() should accept values encrypted with the current key only")
+	}
+
+	if plaintext, err := keyring.Decrypt(encrypted); err != nil || plaintext != "hunter2" {
+		t.Errorf("Decrypt() = %q, %v; want hunter2", plaintext, err)
+	}
+	// Secrets stored before encryption are read as they are
+	if plaintext, err := keyring.Decrypt("legacy"); err != nil || plaintext != "legacy" {
+		t.Errorf("Decrypt(plaintext) = %q, %v; want legacy", plaintext, err)
+	}
+
+	// After a rotation the previous key still decrypts, but its values are no longer current
+	rotated, err := NewKeyring("second key", "first key")
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	if plaintext, err := rotated.Decrypt(encrypted); err != nil || plaintext != "hunter2" {
+		t.Errorf("Decrypt() with the previous key = %q, %v; want hunter2", plaintext, err)
+	}
+	if rotated.Current(encrypted) {
+		t.Error("Current() = true for a value encrypted with the previous key")
+	}
+
+	// Without the key the secret can't be read
+	other, _ := NewKeyring("other key")
+	if _, err := other.Decrypt(encrypted); err == nil {
+		t.Error("Decrypt() with another key succeeded")
+	}
+	var none *Keyring
+	if _, err := none.Decrypt(encrypted); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Decrypt() without a keyring error = %v, want ErrNoKey", err)
+	}
+
+	tampered := encrypted[:len(encrypted)-4] + "AAAA"
+	if _, err := keyring.Decrypt(tampered); err == nil {
+		t.Error("Decrypt() accepted a tampered value")
+	}
+	if _, err := NewKeyring(""); !errors.Is(err, ErrNoKey) {
+		t.Errorf("NewKeyring(\"\") error = %v, want ErrNoKey", err)
+	}
+}
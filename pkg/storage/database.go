@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// Database keeps artifacts as BLOBs in the runs table (runs.artifact_data)
+type Database struct{}
+
+// Save keeps data on the run, to be written with the run record
+func (Database) Save(ctx context.Context, run *model.Run, data []byte) error {
+	run.ArtifactData = data
+	run.StorageBackend = model.StorageDatabase
+	run.StorageKey = ""
+	return nil
+}
+
+// Open streams the artifact loaded with the run
+func (Database) Open(ctx context.Context, run *model.Run) (io.ReadCloser, int64, error) {
+	if len(run.ArtifactData) == 0 {
+		return nil, 0, fmt.Errorf("artifact not found")
+	}
+	return io.NopCloser(bytes.NewReader(run.ArtifactData)), int64(len(run.ArtifactData)), nil
+}
+
+// Delete drops the artifact from the run, to be written with the run record
+func (Database) Delete(ctx context.Context, run *model.Run) error {
+	run.ArtifactData = nil
+	return nil
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// Filesystem keeps artifacts as files below a root directory
+type Filesystem struct {
+	root string
+}
+
+// NewFilesystem creates a filesystem artifact store rooted at dir
+func NewFilesystem(dir string) (*Filesystem, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("artifacts directory is not configured")
+	}
+	return &Filesystem{root: dir}, nil
+}
+
+// Save writes the artifact to <root>/org_<id>/schedule_<id>/run_<id>.<ext>
+func (f *Filesystem) Save(ctx context.Context, run *model.Run, data []byte) error {
+	key := objectKey(run)
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	// Write to a temporary file first so readers never see a partial artifact
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write artifact: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write artifact: %w", err)
+	}
+
+	run.ArtifactData = nil
+	run.StorageBackend = model.StorageFilesystem
+	run.StorageKey = key
+	return nil
+}
+
+// Open opens the artifact file of run
+func (f *Filesystem) Open(ctx context.Context, run *model.Run) (io.ReadCloser, int64, error) {
+	path, err := f.path(run.StorageKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, 0, fmt.Errorf("artifact not found")
+		}
+		return nil, 0, fmt.Errorf("failed to open artifact: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("failed to open artifact: %w", err)
+	}
+	return file, info.Size(), nil
+}
+
+// Delete removes the artifact file of run; missing files are not an error
+func (f *Filesystem) Delete(ctx context.Context, run *model.Run) error {
+	path, err := f.path(run.StorageKey)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete artifact: %w", err)
+	}
+	run.StorageKey = ""
+	return nil
+}
+
+// path resolves a key below the root directory, rejecting keys that escape it
+func (f *Filesystem) path(key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("artifact not found")
+	}
+	path := filepath.Join(f.root, filepath.FromSlash(key))
+	rel, err := filepath.Rel(f.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid artifact key '%s'", key)
+	}
+	return path, nil
+}
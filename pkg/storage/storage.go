@@ -0,0 +1,45 @@
+// Package storage persists report artifacts in the database, a local directory or an S3-compatible bucket.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// ArtifactStore stores the artifact of a run.
+// Save records where the artifact was stored on the run (StorageBackend and StorageKey,
+// or ArtifactData for the database backend); callers persist the run afterwards.
+type ArtifactStore interface {
+	// Save stores data as the artifact of run
+	Save(ctx context.Context, run *model.Run, data []byte) error
+	// Open streams the artifact of run and returns its size in bytes (-1 when unknown)
+	Open(ctx context.Context, run *model.Run) (io.ReadCloser, int64, error)
+	// Delete removes the artifact of run
+	Delete(ctx context.Context, run *model.Run) error
+}
+
+// New returns the artifact store for a backend.
+// root is the directory used by the filesystem backend; config supplies the S3 bucket.
+func New(backend string, config model.StorageConfig, root string) (ArtifactStore, error) {
+	switch backend {
+	case "", model.StorageDatabase:
+		return Database{}, nil
+	case model.StorageFilesystem:
+		return NewFilesystem(root)
+	case model.StorageS3:
+		if config.S3 == nil {
+			return nil, fmt.Errorf("s3 storage is not configured")
+		}
+		return NewS3(*config.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend '%s'", backend)
+	}
+}
+
+// objectKey returns the backend-independent key of a run's artifact
+func objectKey(run *model.Run) string {
+	return fmt.Sprintf("org_%d/schedule_%d/run_%d%s", run.OrgID, run.ScheduleID, run.ID, model.ArtifactExtension(run.ContentType))
+}
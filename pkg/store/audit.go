@@ -0,0 +1,136 @@
+package store
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+)
+
+// auditTimeFormat stores audit timestamps in UTC, so they sort and compare as text
+const auditTimeFormat = "2006-01-02 15:04:05"
+
+// AuditFilter selects the audit events of an org. Empty fields match every event.
+type AuditFilter struct {
+	OrgID        int64
+	UserLogin    string
+	Action       string
+	ResourceType string
+	ResourceID   int64
+	From         time.Time // Inclusive
+	To           time.Time // Exclusive
+	Limit        int
+	Offset       int
+}
+
+// CreateAuditEvent records an audit event (queued for serialized execution)
+func (s *Store) CreateAuditEvent(event *model.AuditEvent) error {
+	return s.writeQueue.enqueue(opCreateAuditEvent, event)
+}
+
+// createAuditEventDirect inserts an audit event (direct database access, called by write queue)
+func (s *Store) createAuditEventDirect(event *model.AuditEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	event.CreatedAt = event.CreatedAt.UTC().Truncate(time.Second)
+
+	var resourceID, diff interface{}
+	if event.ResourceID != 0 {
+		resourceID = event.ResourceID
+	}
+	if len(event.Diff) > 0 {
+		diff = string(event.Diff)
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO audit_events (org_id, user_login, action, resource_type, resource_id, diff, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		event.OrgID, event.UserLogin, event.Action, event.ResourceType, resourceID, diff,
+		event.CreatedAt.Format(auditTimeFormat),
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	event.ID = id
+	return nil
+}
+
+// ListAuditEvents returns a page of the events matching the filter, newest first, and the number
+// of events matching it in total
+func (s *Store) ListAuditEvents(filter AuditFilter) ([]*model.AuditEvent, int, error) {
+	conditions := []string{"org_id = ?"}
+	args := []interface{}{filter.OrgID}
+	if filter.UserLogin != "" {
+		conditions = append(conditions, "user_login = ?")
+		args = append(args, filter.UserLogin)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.ResourceType != "" {
+		conditions = append(conditions, "resource_type = ?")
+		args = append(args, filter.ResourceType)
+	}
+	if filter.ResourceID != 0 {
+		conditions = append(conditions, "resource_id = ?")
+		args = append(args, filter.ResourceID)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From.UTC().Format(auditTimeFormat))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To.UTC().Format(auditTimeFormat))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM audit_events WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+	rows, err := s.db.Query(`
+		SELECT id, org_id, user_login, action, resource_type, resource_id, diff, created_at
+		FROM audit_events WHERE `+where+`
+		ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`,
+		append(args, limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	events := []*model.AuditEvent{}
+	for rows.Next() {
+		event := &model.AuditEvent{}
+		var resourceID sql.NullInt64
+		var diff sql.NullString
+		var createdAt string
+		if err := rows.Scan(&event.ID, &event.OrgID, &event.UserLogin, &event.Action, &event.ResourceType,
+			&resourceID, &diff, &createdAt); err != nil {
+			return nil, 0, err
+		}
+		event.ResourceID = resourceID.Int64
+		if diff.Valid {
+			event.Diff = []byte(diff.String)
+		}
+		if t := parseTimestamp(createdAt); t != nil {
+			event.CreatedAt = *t
+		}
+		events = append(events, event)
+	}
+	return events, total, rows.Err()
+}
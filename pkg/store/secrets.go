@@ -0,0 +1,298 @@
+package store
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/secrets"
+)
+
+// EncryptResult reports the rows whose secrets EncryptSecrets re-encrypted
+type EncryptResult struct {
+	Settings  int // Organizations whose settings were updated
+	Schedules int // Schedules whose delivery targets were updated
+}
+
+// SetKeyring sets the keys settings and delivery target secrets are encrypted with. Until a keyring is set, secrets
+// are stored as plaintext and encrypted secrets can't be read.
+func (s *Store) SetKeyring(keyring *secrets.Keyring) {
+	s.keyring.Store(keyring)
+}
+
+// sealSettings returns a copy of settings with its secrets encrypted for storage
+func (s *Store) sealSettings(settings *model.Settings) (*model.Settings, error) {
+	sealed := *settings
+	if settings.SMTPConfig != nil {
+		smtp := *settings.SMTPConfig
+		sealed.SMTPConfig = &smtp
+	}
+	if settings.Storage.S3 != nil {
+		s3 := *settings.Storage.S3
+		sealed.Storage.S3 = &s3
+	}
+
+	if err := sealSecrets(s.keyring.Load(), sealed.Secrets()); err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// openSettings decrypts the secrets of settings read from the database
+func (s *Store) openSettings(settings *model.Settings) error {
+	keyring := s.keyring.Load()
+	for path, secret := range settings.Secrets() {
+		plaintext, err := keyring.Decrypt(*secret)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s of org %d: %w", path, settings.OrgID, err)
+		}
+		*secret = plaintext
+	}
+	return nil
+}
+
+// sealTargets returns a copy of the schedule's delivery targets with their secrets encrypted for storage
+func (s *Store) sealTargets(schedule *model.Schedule) (model.DeliveryTargets, error) {
+	if schedule.Targets == nil {
+		return nil, nil
+	}
+	sealed := &model.Schedule{OrgID: schedule.OrgID, Targets: make(model.DeliveryTargets, len(schedule.Targets))}
+	for i, target := range schedule.Targets {
+		if target.Slack != nil {
+			slack := *target.Slack
+			target.Slack = &slack
+		}
+		if target.Teams != nil {
+			teams := *target.Teams
+			target.Teams = &teams
+		}
+		if target.Webhook != nil {
+			webhook := *target.Webhook
+			target.Webhook = &webhook
+		}
+		if target.SFTP != nil {
+			sftp := *target.SFTP
+			target.SFTP = &sftp
+		}
+		sealed.Targets[i] = target
+	}
+
+	if err := sealSecrets(s.keyring.Load(), sealed.Secrets()); err != nil {
+		return nil, err
+	}
+	return sealed.Targets, nil
+}
+
+// sealSecrets encrypts the secrets in place. Without a keyring they are stored as plaintext, except
+// those that look encrypted, which could not be read back.
+func sealSecrets(keyring *secrets.Keyring, values map[string]*string) error {
+	for path, secret := range values {
+		if keyring == nil {
+			if secrets.IsEncrypted(*secret) {
+				return fmt.Errorf("%s looks like an encrypted value and can only be stored with %s set", path, secrets.KeyEnv)
+			}
+			continue
+		}
+		encrypted, err := keyring.Encrypt(*secret)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", path, err)
+		}
+		*secret = encrypted
+	}
+	return nil
+}
+
+// openSchedule decrypts the delivery target secrets of a schedule read from the database
+func (s *Store) openSchedule(schedule *model.Schedule) error {
+	keyring := s.keyring.Load()
+	for path, secret := range schedule.Secrets() {
+		plaintext, err := keyring.Decrypt(*secret)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s of schedule %d: %w", path, schedule.ID, err)
+		}
+		*secret = plaintext
+	}
+	return nil
+}
+
+// SigningKey returns the random key stored under name, creating it on first use. Unlike the secrets
+// key it never leaves the database, so signatures made with it survive restarts without configuration.
+func (s *Store) SigningKey(name string) ([]byte, error) {
+	var key []byte
+	err := s.db.QueryRow(`SELECT key FROM signing_keys WHERE name = ?`, name).Scan(&key)
+	if err == sql.ErrNoRows {
+		if err := s.writeQueue.enqueue(opCreateSigningKey, createSigningKeyParams{name: name}); err != nil {
+			return nil, err
+		}
+		err = s.db.QueryRow(`SELECT key FROM signing_keys WHERE name = ?`, name).Scan(&key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// createSigningKeyDirect stores a new random key under name unless one exists (direct database
+// access, called by write queue)
+func (s *Store) createSigningKeyDirect(name string) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	_, err := s.db.Exec(`INSERT OR IGNORE INTO signing_keys (name, key) VALUES (?, ?)`, name, key)
+	return err
+}
+
+// HasEncryptedSecrets reports whether any settings or delivery target secret is stored encrypted,
+// so the secrets key is needed to read it
+func (s *Store) HasEncryptedSecrets() (bool, error) {
+	rows, err := s.db.Query(`SELECT org_id, smtp_config, storage FROM settings`)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		settings := &model.Settings{}
+		if err := rows.Scan(&settings.OrgID, &settings.SMTPConfig, &settings.Storage); err != nil {
+			return false, err
+		}
+		for _, secret := range settings.Secrets() {
+			if secrets.IsEncrypted(*secret) {
+				return true, nil
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	targetRows, err := s.db.Query(`SELECT id, targets FROM schedules WHERE targets IS NOT NULL`)
+	if err != nil {
+		return false, err
+	}
+	defer targetRows.Close()
+	for targetRows.Next() {
+		schedule := &model.Schedule{}
+		if err := targetRows.Scan(&schedule.ID, &schedule.Targets); err != nil {
+			return false, err
+		}
+		for _, secret := range schedule.Secrets() {
+			if secrets.IsEncrypted(*secret) {
+				return true, nil
+			}
+		}
+	}
+	return false, targetRows.Err()
+}
+
+// EncryptSecrets encrypts the settings and delivery target secrets that are stored as plaintext or
+// with a previous key with the current key of the keyring (queued for serialized execution)
+func (s *Store) EncryptSecrets() (*EncryptResult, error) {
+	params := &encryptSecretsParams{result: &EncryptResult{}}
+	if err := s.writeQueue.enqueue(opEncryptSecrets, params); err != nil {
+		return nil, err
+	}
+	return params.result, nil
+}
+
+// encryptSecretsDirect re-encrypts stale secrets (direct database access, called by write queue)
+func (s *Store) encryptSecretsDirect(params *encryptSecretsParams) error {
+	if err := s.encryptSettingsSecrets(params.result); err != nil {
+		return err
+	}
+	return s.encryptTargetSecrets(params.result)
+}
+
+// encryptSettingsSecrets re-encrypts stale settings secrets
+func (s *Store) encryptSettingsSecrets(result *EncryptResult) error {
+	keyring := s.keyring.Load()
+	if keyring == nil {
+		return secrets.ErrNoKey
+	}
+
+	rows, err := s.db.Query(`SELECT org_id, smtp_config, storage FROM settings`)
+	if err != nil {
+		return err
+	}
+	var stale []*model.Settings
+	for rows.Next() {
+		settings := &model.Settings{}
+		if err := rows.Scan(&settings.OrgID, &settings.SMTPConfig, &settings.Storage); err != nil {
+			rows.Close()
+			return err
+		}
+		for _, secret := range settings.Secrets() {
+			if !keyring.Current(*secret) {
+				stale = append(stale, settings)
+				break
+			}
+		}
+	}
+	// The rows hold the only connection, so they are closed before updating
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, settings := range stale {
+		if err := s.openSettings(settings); err != nil {
+			return err
+		}
+		sealed, err := s.sealSettings(settings)
+		if err != nil {
+			return err
+		}
+		if _, err := s.db.Exec(`UPDATE settings SET smtp_config = ?, storage = ? WHERE org_id = ?`,
+			sealed.SMTPConfig, sealed.Storage, sealed.OrgID,
+		); err != nil {
+			return err
+		}
+		result.Settings++
+	}
+	return nil
+}
+
+// encryptTargetSecrets re-encrypts stale delivery target secrets
+func (s *Store) encryptTargetSecrets(result *EncryptResult) error {
+	keyring := s.keyring.Load()
+	rows, err := s.db.Query(`SELECT id, org_id, targets FROM schedules WHERE targets IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+	var stale []*model.Schedule
+	for rows.Next() {
+		schedule := &model.Schedule{}
+		if err := rows.Scan(&schedule.ID, &schedule.OrgID, &schedule.Targets); err != nil {
+			rows.Close()
+			return err
+		}
+		for _, secret := range schedule.Secrets() {
+			if !keyring.Current(*secret) {
+				stale = append(stale, schedule)
+				break
+			}
+		}
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range stale {
+		if err := s.openSchedule(schedule); err != nil {
+			return err
+		}
+		targets, err := s.sealTargets(schedule)
+		if err != nil {
+			return err
+		}
+		if _, err := s.db.Exec(`UPDATE schedules SET targets = ? WHERE id = ?`, targets, schedule.ID); err != nil {
+			return err
+		}
+		result.Schedules++
+	}
+	return nil
+}
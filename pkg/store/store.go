@@ -2,13 +2,16 @@ package store
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	_ "modernc.org/sqlite" // Register SQLite driver
 	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/secrets"
 )
 
 // parseTimestamp parses a timestamp string from SQLite, handling multiple formats
@@ -44,6 +47,7 @@ func parseTimestamp(s string) *time.Time {
 type Store struct {
 	db         *sql.DB
 	writeQueue *writeQueue
+	keyring    atomic.Pointer[secrets.Keyring] // Encrypts settings secrets; nil stores them as plaintext
 }
 
 // NewStore creates a new store instance
@@ -72,6 +76,12 @@ func NewStore(dbPath string) (*Store, error) {
 	db.SetMaxOpenConns(1) // SQLite only supports single writer
 	db.SetMaxIdleConns(1)
 
+	// Let retention reclaim space incrementally (only takes effect on new databases;
+	// Vacuum converts existing ones on its first run)
+	if _, err := db.Exec("PRAGMA auto_vacuum=INCREMENTAL;"); err != nil {
+		return nil, fmt.Errorf("failed to set auto_vacuum: %w", err)
+	}
+
 	log.Println("[STORE] SQLite configured: WAL mode enabled, busy_timeout=5000ms, single writer connection")
 
 	store := &Store{db: db}
@@ -169,6 +179,71 @@ func (s *Store) migrate() error {
 		// Migration: Add artifact_data BLOB field to store PDF content directly in database
 		// This replaces filesystem storage to comply with Grafana catalog requirements
 		`ALTER TABLE runs ADD COLUMN artifact_data BLOB`,
+		// Migration: Add layout field to choose between single-page and paginated PDFs
+		`ALTER TABLE schedules ADD COLUMN layout TEXT NOT NULL DEFAULT 'single'`,
+		// Migration: Add content_type field so image and archive artifacts are served correctly
+		`ALTER TABLE runs ADD COLUMN content_type TEXT`,
+		// Migration: Add data export fields to attach panel query results as CSV or XLSX
+		`ALTER TABLE schedules ADD COLUMN data_format TEXT`,
+		`ALTER TABLE schedules ADD COLUMN data_only INTEGER NOT NULL DEFAULT 0`,
+		// Migration: Add delivery_mode field recording whether oversized reports were compressed or linked
+		`ALTER TABLE runs ADD COLUMN delivery_mode TEXT`,
+		// Migration: Add artifact storage backend selection and the location of externally stored artifacts
+		`ALTER TABLE settings ADD COLUMN storage TEXT`,
+		`ALTER TABLE runs ADD COLUMN storage_backend TEXT`,
+		`ALTER TABLE runs ADD COLUMN storage_key TEXT`,
+		// Migration: Add delivery targets (Slack, ...)
+		`ALTER TABLE schedules ADD COLUMN targets TEXT`,
+		// Migration: Add per-target delivery status so failed targets can be redelivered
+		`CREATE TABLE IF NOT EXISTS deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			run_id INTEGER NOT NULL,
+			org_id INTEGER NOT NULL,
+			target_index INTEGER NOT NULL,
+			type TEXT NOT NULL,
+			target TEXT,
+			success INTEGER NOT NULL DEFAULT 0,
+			error TEXT,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			delivered_at DATETIME NOT NULL,
+			UNIQUE (run_id, target_index),
+			FOREIGN KEY (run_id) REFERENCES runs(id) ON DELETE CASCADE
+		)`,
+		// Migration: Add delivery conditions for exception reports
+		`ALTER TABLE schedules ADD COLUMN condition TEXT`,
+		// Migration: Add report bursting, with one child run per variable value
+		`ALTER TABLE schedules ADD COLUMN burst TEXT`,
+		`ALTER TABLE runs ADD COLUMN parent_run_id INTEGER`,
+		`ALTER TABLE runs ADD COLUMN burst_value TEXT`,
+		// Migration: Add multi-dashboard reports
+		`ALTER TABLE schedules ADD COLUMN dashboards TEXT`,
+		// Migration: Add the Grafana theme dashboards are rendered in
+		`ALTER TABLE schedules ADD COLUMN theme TEXT`,
+		// Migration: Add the absolute time range each run was rendered for
+		`ALTER TABLE runs ADD COLUMN range_from DATETIME`,
+		`ALTER TABLE runs ADD COLUMN range_to DATETIME`,
+		// Migration: Add the login of the schedule owner, taken from the plugin request context
+		`ALTER TABLE schedules ADD COLUMN owner_login TEXT`,
+		// Migration: Add the audit log of schedule, settings and run actions
+		`CREATE TABLE IF NOT EXISTS audit_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			org_id INTEGER NOT NULL,
+			user_login TEXT NOT NULL,
+			action TEXT NOT NULL,
+			resource_type TEXT NOT NULL,
+			resource_id INTEGER,
+			diff TEXT,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_events_org_id_created_at ON audit_events(org_id, created_at)`,
+		// Migration: Add the login of the template owner, who may change it besides admins
+		`ALTER TABLE templates ADD COLUMN owner_login TEXT`,
+		// Migration: Add the keys links shared outside Grafana's permissions are signed with
+		`CREATE TABLE IF NOT EXISTS signing_keys (
+			name TEXT PRIMARY KEY,
+			key BLOB NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
 	}
 
 	for _, migration := range migrations {
@@ -202,19 +277,27 @@ func (s *Store) createScheduleDirect(schedule *model.Schedule) error {
 		nextRunAtStr = schedule.NextRunAt.UTC().Format("2006-01-02 15:04:05")
 	}
 
-	// Try INSERT with format field first (for backward compatibility with old databases)
+	// Target secrets are encrypted in a copy, leaving the caller's schedule readable
+	targets, err := s.sealTargets(schedule)
+	if err != nil {
+		return err
+	}
+
 	result, err := s.db.Exec(`
 		INSERT INTO schedules (
 			org_id, name, dashboard_uid, dashboard_title, panel_ids, range_from, range_to,
 			interval_type, cron_expr, timezone, format, variables, recipients,
 			email_subject, email_body, template_id, enabled, owner_user_id,
-			next_run_at, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			next_run_at, created_at, updated_at, layout, data_format, data_only, targets, condition, burst,
+			dashboards, theme, owner_login
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		schedule.OrgID, schedule.Name, schedule.DashboardUID, schedule.DashboardTitle,
 		schedule.PanelIDs, schedule.RangeFrom, schedule.RangeTo, schedule.IntervalType,
-		schedule.CronExpr, schedule.Timezone, "pdf", schedule.Variables,
+		schedule.CronExpr, schedule.Timezone, scheduleFormat(schedule), schedule.Variables,
 		schedule.Recipients, schedule.EmailSubject, schedule.EmailBody, schedule.TemplateID,
-		schedule.Enabled, schedule.OwnerUserID, nextRunAtStr, now, now,
+		schedule.Enabled, schedule.OwnerUserID, nextRunAtStr, now, now, scheduleLayout(schedule),
+		schedule.DataFormat, schedule.DataOnly, targets, schedule.Condition, schedule.Burst,
+		schedule.Dashboards, schedule.Theme, schedule.OwnerLogin,
 	)
 	if err != nil {
 		return err
@@ -229,30 +312,33 @@ func (s *Store) createScheduleDirect(schedule *model.Schedule) error {
 	return nil
 }
 
-// GetSchedule retrieves a schedule by ID
-func (s *Store) GetSchedule(orgID, id int64) (*model.Schedule, error) {
+// scheduleColumns lists the schedule columns in the order expected by scanSchedule
+const scheduleColumns = `id, org_id, name, dashboard_uid, dashboard_title, panel_ids, range_from, range_to,
+	interval_type, cron_expr, timezone, format, variables, recipients,
+	email_subject, email_body, template_id, enabled, last_run_at, next_run_at,
+	owner_user_id, created_at, updated_at, layout, data_format, data_only, targets, condition, burst,
+	dashboards, theme, owner_login`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSchedule scans a row selected with scheduleColumns into a schedule
+func scanSchedule(row rowScanner) (*model.Schedule, error) {
 	schedule := &model.Schedule{}
-	var format string // Backward compatibility - format field removed from model but may exist in old databases
-	var lastRunAtStr, nextRunAtStr sql.NullString
+	var lastRunAtStr, nextRunAtStr, dataFormat, theme, ownerLogin sql.NullString
 
-	err := s.db.QueryRow(`
-		SELECT id, org_id, name, dashboard_uid, dashboard_title, panel_ids, range_from, range_to,
-		       interval_type, cron_expr, timezone, format, variables, recipients,
-		       email_subject, email_body, template_id, enabled, last_run_at, next_run_at,
-		       owner_user_id, created_at, updated_at
-		FROM schedules WHERE id = ? AND org_id = ?`,
-		id, orgID,
-	).Scan(
+	err := row.Scan(
 		&schedule.ID, &schedule.OrgID, &schedule.Name, &schedule.DashboardUID,
 		&schedule.DashboardTitle, &schedule.PanelIDs, &schedule.RangeFrom, &schedule.RangeTo,
-		&schedule.IntervalType, &schedule.CronExpr, &schedule.Timezone, &format,
+		&schedule.IntervalType, &schedule.CronExpr, &schedule.Timezone, &schedule.Format,
 		&schedule.Variables, &schedule.Recipients, &schedule.EmailSubject, &schedule.EmailBody,
 		&schedule.TemplateID, &schedule.Enabled, &lastRunAtStr, &nextRunAtStr,
-		&schedule.OwnerUserID, &schedule.CreatedAt, &schedule.UpdatedAt,
+		&schedule.OwnerUserID, &schedule.CreatedAt, &schedule.UpdatedAt, &schedule.Layout,
+		&dataFormat, &schedule.DataOnly, &schedule.Targets, &schedule.Condition, &schedule.Burst,
+		&schedule.Dashboards, &theme, &ownerLogin,
 	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("schedule not found")
-	}
 	if err != nil {
 		return nil, err
 	}
@@ -264,18 +350,52 @@ func (s *Store) GetSchedule(orgID, id int64) (*model.Schedule, error) {
 	if nextRunAtStr.Valid {
 		schedule.NextRunAt = parseTimestamp(nextRunAtStr.String)
 	}
+	schedule.DataFormat = dataFormat.String
+	schedule.Theme = theme.String
+	schedule.OwnerLogin = ownerLogin.String
+
+	return schedule, nil
+}
+
+// scheduleFormat returns the format to persist, defaulting to PDF
+func scheduleFormat(schedule *model.Schedule) string {
+	if schedule.Format == "" {
+		return model.FormatPDF
+	}
+	return schedule.Format
+}
+
+// scheduleLayout returns the layout to persist, defaulting to the single-page layout
+func scheduleLayout(schedule *model.Schedule) string {
+	if schedule.Layout == "" {
+		return model.LayoutSingle
+	}
+	return schedule.Layout
+}
+
+// GetSchedule retrieves a schedule by ID
+func (s *Store) GetSchedule(orgID, id int64) (*model.Schedule, error) {
+	schedule, err := scanSchedule(s.db.QueryRow(
+		`SELECT `+scheduleColumns+` FROM schedules WHERE id = ? AND org_id = ?`,
+		id, orgID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("schedule not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.openSchedule(schedule); err != nil {
+		return nil, err
+	}
 
 	return schedule, nil
 }
 
 // ListSchedules retrieves all schedules for an organization
 func (s *Store) ListSchedules(orgID int64) ([]*model.Schedule, error) {
-	rows, err := s.db.Query(`
-		SELECT id, org_id, name, dashboard_uid, dashboard_title, panel_ids, range_from, range_to,
-		       interval_type, cron_expr, timezone, format, variables, recipients,
-		       email_subject, email_body, template_id, enabled, last_run_at, next_run_at,
-		       owner_user_id, created_at, updated_at
-		FROM schedules WHERE org_id = ? ORDER BY created_at DESC`,
+	rows, err := s.db.Query(
+		`SELECT `+scheduleColumns+` FROM schedules WHERE org_id = ? ORDER BY created_at DESC`,
 		orgID,
 	)
 	if err != nil {
@@ -285,30 +405,13 @@ func (s *Store) ListSchedules(orgID int64) ([]*model.Schedule, error) {
 
 	schedules := make([]*model.Schedule, 0)
 	for rows.Next() {
-		schedule := &model.Schedule{}
-		var format string // Backward compatibility - format field removed from model but may exist in old databases
-		var lastRunAtStr, nextRunAtStr sql.NullString
-
-		err := rows.Scan(
-			&schedule.ID, &schedule.OrgID, &schedule.Name, &schedule.DashboardUID,
-			&schedule.DashboardTitle, &schedule.PanelIDs, &schedule.RangeFrom, &schedule.RangeTo,
-			&schedule.IntervalType, &schedule.CronExpr, &schedule.Timezone, &format,
-			&schedule.Variables, &schedule.Recipients, &schedule.EmailSubject, &schedule.EmailBody,
-			&schedule.TemplateID, &schedule.Enabled, &lastRunAtStr, &nextRunAtStr,
-			&schedule.OwnerUserID, &schedule.CreatedAt, &schedule.UpdatedAt,
-		)
+		schedule, err := scanSchedule(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		// Parse timestamp strings to time.Time
-		if lastRunAtStr.Valid {
-			schedule.LastRunAt = parseTimestamp(lastRunAtStr.String)
-		}
-		if nextRunAtStr.Valid {
-			schedule.NextRunAt = parseTimestamp(nextRunAtStr.String)
+		if err := s.openSchedule(schedule); err != nil {
+			return nil, err
 		}
-
 		schedules = append(schedules, schedule)
 	}
 
@@ -333,33 +436,103 @@ func (s *Store) updateScheduleDirect(schedule *model.Schedule) error {
 		nextRunAtStr = schedule.NextRunAt.UTC().Format("2006-01-02 15:04:05")
 	}
 
-	// Include format field for backward compatibility with old databases (always set to 'pdf')
-	_, err := s.db.Exec(`
+	targets, err := s.sealTargets(schedule)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
 		UPDATE schedules SET
 			name = ?, dashboard_uid = ?, dashboard_title = ?, panel_ids = ?,
 			range_from = ?, range_to = ?, interval_type = ?, cron_expr = ?,
 			timezone = ?, format = ?, variables = ?, recipients = ?,
 			email_subject = ?, email_body = ?, template_id = ?, enabled = ?,
-			last_run_at = ?, next_run_at = ?, updated_at = ?
+			last_run_at = ?, next_run_at = ?, updated_at = ?, layout = ?,
+			data_format = ?, data_only = ?, targets = ?, condition = ?, burst = ?,
+			dashboards = ?, theme = ?
 		WHERE id = ? AND org_id = ?`,
 		schedule.Name, schedule.DashboardUID, schedule.DashboardTitle, schedule.PanelIDs,
 		schedule.RangeFrom, schedule.RangeTo, schedule.IntervalType, schedule.CronExpr,
-		schedule.Timezone, "pdf", schedule.Variables, schedule.Recipients,
+		schedule.Timezone, scheduleFormat(schedule), schedule.Variables, schedule.Recipients,
 		schedule.EmailSubject, schedule.EmailBody, schedule.TemplateID, schedule.Enabled,
-		lastRunAtStr, nextRunAtStr, schedule.UpdatedAt, schedule.ID, schedule.OrgID,
+		lastRunAtStr, nextRunAtStr, schedule.UpdatedAt, scheduleLayout(schedule),
+		schedule.DataFormat, schedule.DataOnly, targets, schedule.Condition, schedule.Burst,
+		schedule.Dashboards, schedule.Theme, schedule.ID, schedule.OrgID,
 	)
 	return err
 }
 
-// DeleteSchedule deletes a schedule (queued for serialized execution)
+// SetNextRun sets when a schedule runs next, leaving its other columns as they are, so it doesn't
+// overwrite edits saved since the schedule was read (queued for serialized execution)
+func (s *Store) SetNextRun(orgID, id int64, nextRunAt time.Time) error {
+	return s.writeQueue.enqueue(opSetNextRun, setNextRunParams{orgID: orgID, id: id, nextRunAt: nextRunAt})
+}
+
+// setNextRunDirect sets next_run_at (direct database access, called by write queue)
+func (s *Store) setNextRunDirect(orgID, id int64, nextRunAt time.Time) error {
+	_, err := s.db.Exec(`UPDATE schedules SET next_run_at = ? WHERE id = ? AND org_id = ?`,
+		nextRunAt.UTC().Format("2006-01-02 15:04:05"), id, orgID)
+	return err
+}
+
+// SetScheduleOwnerLogin records the login of a schedule's owner, leaving its other columns as they
+// are (queued for serialized execution)
+func (s *Store) SetScheduleOwnerLogin(orgID, id int64, login string) error {
+	return s.writeQueue.enqueue(opSetScheduleOwnerLogin, setScheduleOwnerLoginParams{orgID: orgID, id: id, login: login})
+}
+
+// setScheduleOwnerLoginDirect sets owner_login (direct database access, called by write queue)
+func (s *Store) setScheduleOwnerLoginDirect(orgID, id int64, login string) error {
+	_, err := s.db.Exec(`UPDATE schedules SET owner_login = ? WHERE id = ? AND org_id = ?`, login, id, orgID)
+	return err
+}
+
+// RecordScheduleRun records when a schedule last ran and, with disable, disables it, leaving its
+// other columns as they are (queued for serialized execution)
+func (s *Store) RecordScheduleRun(orgID, id int64, lastRunAt time.Time, disable bool) error {
+	return s.writeQueue.enqueue(opRecordScheduleRun, recordScheduleRunParams{
+		orgID: orgID, id: id, lastRunAt: lastRunAt, disable: disable,
+	})
+}
+
+// recordScheduleRunDirect updates the run bookkeeping of a schedule (direct database access, called by write queue)
+func (s *Store) recordScheduleRunDirect(params recordScheduleRunParams) error {
+	lastRunAt := params.lastRunAt.UTC().Format("2006-01-02 15:04:05")
+	if params.disable {
+		_, err := s.db.Exec(`UPDATE schedules SET last_run_at = ?, enabled = 0, next_run_at = NULL WHERE id = ? AND org_id = ?`,
+			lastRunAt, params.id, params.orgID)
+		return err
+	}
+	_, err := s.db.Exec(`UPDATE schedules SET last_run_at = ? WHERE id = ? AND org_id = ?`,
+		lastRunAt, params.id, params.orgID)
+	return err
+}
+
+// DeleteSchedule deletes a schedule and the delivery results of its runs (queued for serialized execution).
+// The runs are kept, so retention still removes their artifacts from external storage.
 func (s *Store) DeleteSchedule(orgID, id int64) error {
 	return s.writeQueue.enqueue(opDeleteSchedule, deleteScheduleParams{orgID: orgID, id: id})
 }
 
-// deleteScheduleDirect deletes a schedule (direct database access, called by write queue)
+// deleteScheduleDirect deletes a schedule (direct database access, called by write queue).
+// SQLite doesn't enforce foreign keys unless enabled per connection, so the deliveries are deleted
+// here rather than by the schema's ON DELETE CASCADE.
 func (s *Store) deleteScheduleDirect(orgID, id int64) error {
-	_, err := s.db.Exec("DELETE FROM schedules WHERE id = ? AND org_id = ?", id, orgID)
-	return err
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"DELETE FROM deliveries WHERE run_id IN (SELECT id FROM runs WHERE schedule_id = ? AND org_id = ?)", id, orgID,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM schedules WHERE id = ? AND org_id = ?", id, orgID); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // CreateRun creates a new run record (queued for serialized execution)
@@ -372,9 +545,11 @@ func (s *Store) createRunDirect(run *model.Run) error {
 	run.CreatedAt = time.Now()
 
 	result, err := s.db.Exec(`
-		INSERT INTO runs (schedule_id, org_id, started_at, status, created_at)
-		VALUES (?, ?, ?, ?, ?)`,
-		run.ScheduleID, run.OrgID, run.StartedAt, run.Status, run.CreatedAt,
+		INSERT INTO runs (schedule_id, org_id, started_at, status, created_at, parent_run_id, burst_value,
+			range_from, range_to)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ScheduleID, run.OrgID, run.StartedAt, run.Status, run.CreatedAt, run.ParentRunID, run.BurstValue,
+		run.RangeFrom, run.RangeTo,
 	)
 	if err != nil {
 		return err
@@ -399,35 +574,40 @@ func (s *Store) updateRunDirect(run *model.Run) error {
 	_, err := s.db.Exec(`
 		UPDATE runs SET
 			finished_at = ?, status = ?, error_text = ?, artifact_path = ?, artifact_data = ?,
-			rendered_pages = ?, bytes = ?, checksum = ?, email_sent = ?, email_error = ?
+			rendered_pages = ?, bytes = ?, checksum = ?, email_sent = ?, email_error = ?,
+			content_type = ?, delivery_mode = ?, storage_backend = ?, storage_key = ?
 		WHERE id = ?`,
 		run.FinishedAt, run.Status, run.ErrorText, run.ArtifactPath, run.ArtifactData,
-		run.RenderedPages, run.Bytes, run.Checksum, run.EmailSent, run.EmailError, run.ID,
+		run.RenderedPages, run.Bytes, run.Checksum, run.EmailSent, run.EmailError,
+		run.ContentType, run.DeliveryMode, run.StorageBackend, run.StorageKey, run.ID,
 	)
 	return err
 }
 
-// GetRun retrieves a run by ID
-func (s *Store) GetRun(orgID, id int64) (*model.Run, error) {
+// runColumns lists the run columns in the order expected by scanRun.
+// The artifact BLOB is selected separately so listings stay cheap, and the
+// deliveries are loaded from the deliveries table.
+const runColumns = `id, schedule_id, org_id, started_at, finished_at, status, error_text,
+	artifact_path, rendered_pages, bytes, checksum, email_sent, email_error, created_at, content_type,
+	delivery_mode, storage_backend, storage_key, parent_run_id, burst_value, range_from, range_to`
+
+// scanRun scans a row selected with runColumns into a run.
+// Additional destinations for columns selected after runColumns can be passed in extra.
+func scanRun(row rowScanner, extra ...interface{}) (*model.Run, error) {
 	run := &model.Run{}
-	var finishedAt sql.NullTime
-	var errorText, artifactPath, checksum, emailError sql.NullString
-	var artifactData []byte
+	var finishedAt, rangeFrom, rangeTo sql.NullTime
+	var errorText, artifactPath, checksum, emailError, contentType, deliveryMode sql.NullString
+	var storageBackend, storageKey, burstValue sql.NullString
+	var parentRunID sql.NullInt64
 
-	err := s.db.QueryRow(`
-		SELECT id, schedule_id, org_id, started_at, finished_at, status, error_text,
-		       artifact_path, artifact_data, rendered_pages, bytes, checksum, email_sent, email_error, created_at
-		FROM runs WHERE id = ? AND org_id = ?`,
-		id, orgID,
-	).Scan(
+	dest := []interface{}{
 		&run.ID, &run.ScheduleID, &run.OrgID, &run.StartedAt, &finishedAt,
-		&run.Status, &errorText, &artifactPath, &artifactData, &run.RenderedPages,
-		&run.Bytes, &checksum, &run.EmailSent, &emailError, &run.CreatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("run not found")
+		&run.Status, &errorText, &artifactPath, &run.RenderedPages,
+		&run.Bytes, &checksum, &run.EmailSent, &emailError, &run.CreatedAt, &contentType,
+		&deliveryMode, &storageBackend, &storageKey, &parentRunID, &burstValue,
+		&rangeFrom, &rangeTo,
 	}
-	if err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
@@ -435,31 +615,71 @@ func (s *Store) GetRun(orgID, id int64) (*model.Run, error) {
 	if finishedAt.Valid {
 		run.FinishedAt = &finishedAt.Time
 	}
+	if rangeFrom.Valid && rangeTo.Valid {
+		run.RangeFrom, run.RangeTo = &rangeFrom.Time, &rangeTo.Time
+	}
 	if errorText.Valid {
 		run.ErrorText = errorText.String
 	}
 	if artifactPath.Valid {
 		run.ArtifactPath = artifactPath.String
 	}
-	if len(artifactData) > 0 {
-		run.ArtifactData = artifactData
-	}
 	if checksum.Valid {
 		run.Checksum = checksum.String
 	}
 	if emailError.Valid {
 		run.EmailError = emailError.String
 	}
+	if contentType.Valid {
+		run.ContentType = contentType.String
+	}
+	if deliveryMode.Valid {
+		run.DeliveryMode = deliveryMode.String
+	}
+	if storageBackend.Valid {
+		run.StorageBackend = storageBackend.String
+	}
+	if storageKey.Valid {
+		run.StorageKey = storageKey.String
+	}
+	if parentRunID.Valid {
+		run.ParentRunID = &parentRunID.Int64
+	}
+	run.BurstValue = burstValue.String
+
+	return run, nil
+}
+
+// GetRun retrieves a run by ID, including its artifact data
+func (s *Store) GetRun(orgID, id int64) (*model.Run, error) {
+	var artifactData []byte
+
+	run, err := scanRun(s.db.QueryRow(
+		`SELECT `+runColumns+`, artifact_data FROM runs WHERE id = ? AND org_id = ?`,
+		id, orgID,
+	), &artifactData)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("run not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(artifactData) > 0 {
+		run.ArtifactData = artifactData
+	}
+
+	if err := s.loadDeliveries(orgID, []*model.Run{run}); err != nil {
+		return nil, err
+	}
 
 	return run, nil
 }
 
 // ListRuns retrieves runs for a schedule
 func (s *Store) ListRuns(orgID, scheduleID int64) ([]*model.Run, error) {
-	rows, err := s.db.Query(`
-		SELECT id, schedule_id, org_id, started_at, finished_at, status, error_text,
-		       artifact_path, rendered_pages, bytes, checksum, email_sent, email_error, created_at
-		FROM runs WHERE schedule_id = ? AND org_id = ? ORDER BY started_at DESC LIMIT 50`,
+	rows, err := s.db.Query(
+		`SELECT `+runColumns+` FROM runs WHERE schedule_id = ? AND org_id = ? ORDER BY started_at DESC LIMIT 50`,
 		scheduleID, orgID,
 	)
 	if err != nil {
@@ -469,57 +689,331 @@ func (s *Store) ListRuns(orgID, scheduleID int64) ([]*model.Run, error) {
 
 	runs := make([]*model.Run, 0)
 	for rows.Next() {
-		run := &model.Run{}
-		var finishedAt sql.NullTime
-		var errorText, artifactPath, checksum, emailError sql.NullString
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Release the connection before querying the deliveries
+	rows.Close()
 
-		err := rows.Scan(
-			&run.ID, &run.ScheduleID, &run.OrgID, &run.StartedAt, &finishedAt,
-			&run.Status, &errorText, &artifactPath, &run.RenderedPages,
-			&run.Bytes, &checksum, &run.EmailSent, &emailError, &run.CreatedAt,
-		)
+	if err := s.loadDeliveries(orgID, runs); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// PruneResult reports the artifacts removed by PruneArtifacts
+type PruneResult struct {
+	Runs        int          // Runs whose artifacts were removed
+	Bytes       int64        // Artifact bytes removed
+	LegacyPaths []string     // Filesystem artifacts of stripped runs, for the caller to delete
+	External    []*model.Run // Runs whose artifacts live in an external storage backend, for the caller to delete
+}
+
+// SaveDelivery records the outcome of delivering a run to one target, replacing the previous
+// outcome for the same target (queued for serialized execution)
+func (s *Store) SaveDelivery(orgID, runID int64, result *model.DeliveryResult) error {
+	return s.writeQueue.enqueue(opSaveDelivery, saveDeliveryParams{orgID: orgID, runID: runID, result: result})
+}
+
+// saveDeliveryDirect upserts a delivery (direct database access, called by write queue)
+func (s *Store) saveDeliveryDirect(orgID, runID int64, result *model.DeliveryResult) error {
+	_, err := s.db.Exec(`
+		INSERT INTO deliveries (run_id, org_id, target_index, type, target, success, error, attempts, delivered_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (run_id, target_index) DO UPDATE SET
+			type = excluded.type, target = excluded.target, success = excluded.success,
+			error = excluded.error, attempts = excluded.attempts, delivered_at = excluded.delivered_at`,
+		runID, orgID, result.Index, result.Type, result.Target, result.Success, result.Error, result.Attempts, result.DeliveredAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	// LastInsertId is not reliable for upserts that updated an existing row
+	return s.db.QueryRow(`SELECT id FROM deliveries WHERE run_id = ? AND target_index = ?`, runID, result.Index).Scan(&result.ID)
+}
+
+// ListDeliveries retrieves the delivery status of each target of a run
+func (s *Store) ListDeliveries(orgID, runID int64) (model.DeliveryResults, error) {
+	rows, err := s.db.Query(`
+		SELECT id, target_index, type, target, success, error, attempts, delivered_at
+		FROM deliveries WHERE run_id = ? AND org_id = ? ORDER BY target_index`,
+		runID, orgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := model.DeliveryResults{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
 		if err != nil {
 			return nil, err
 		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// loadDeliveries fills in the deliveries of runs from the deliveries table
+func (s *Store) loadDeliveries(orgID int64, runs []*model.Run) error {
+	if len(runs) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*model.Run, len(runs))
+	placeholders := make([]string, 0, len(runs))
+	args := []interface{}{orgID}
+	for _, run := range runs {
+		byID[run.ID] = run
+		placeholders = append(placeholders, "?")
+		args = append(args, run.ID)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, target_index, type, target, success, error, attempts, delivered_at, run_id
+		FROM deliveries WHERE org_id = ? AND run_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY run_id, target_index`,
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
 
-		// Convert nullable fields
-		if finishedAt.Valid {
-			run.FinishedAt = &finishedAt.Time
+	for rows.Next() {
+		var runID int64
+		delivery, err := scanDelivery(rows, &runID)
+		if err != nil {
+			return err
 		}
-		if errorText.Valid {
-			run.ErrorText = errorText.String
+		if run := byID[runID]; run != nil {
+			run.Deliveries = append(run.Deliveries, delivery)
 		}
-		if artifactPath.Valid {
-			run.ArtifactPath = artifactPath.String
+	}
+	return rows.Err()
+}
+
+// scanDelivery scans a delivery selected as id, target_index, type, target, success, error,
+// attempts and delivered_at. Destinations for columns selected after these can be passed in extra.
+func scanDelivery(row rowScanner, extra ...interface{}) (model.DeliveryResult, error) {
+	var delivery model.DeliveryResult
+	var target, errorText sql.NullString
+	dest := []interface{}{&delivery.ID, &delivery.Index, &delivery.Type, &target, &delivery.Success,
+		&errorText, &delivery.Attempts, &delivery.DeliveredAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return model.DeliveryResult{}, err
+	}
+	delivery.Target = target.String
+	delivery.Error = errorText.String
+	return delivery, nil
+}
+
+// PruneArtifacts removes the artifacts of an org's runs started before cutoff, keeping the
+// run records themselves. The newest keepLast runs of each schedule keep their artifacts
+// regardless of age (queued for serialized execution).
+func (s *Store) PruneArtifacts(orgID int64, cutoff time.Time, keepLast int) (*PruneResult, error) {
+	params := &pruneArtifactsParams{orgID: orgID, cutoff: cutoff, keepLast: keepLast, result: &PruneResult{}}
+	if err := s.writeQueue.enqueue(opPruneArtifacts, params); err != nil {
+		return nil, err
+	}
+	return params.result, nil
+}
+
+// pruneArtifactsDirect removes expired artifacts (direct database access, called by write queue)
+func (s *Store) pruneArtifactsDirect(params *pruneArtifactsParams) error {
+	// Timestamps are stored in mixed formats, so ages are compared in Go rather than SQL.
+	// IDs increase with creation, which orders runs within a schedule.
+	rows, err := s.db.Query(`
+		SELECT id, schedule_id, started_at, length(artifact_data), bytes, artifact_path, storage_backend, storage_key
+		FROM runs
+		WHERE org_id = ? AND (artifact_data IS NOT NULL
+			OR (artifact_path IS NOT NULL AND artifact_path != '')
+			OR (storage_key IS NOT NULL AND storage_key != ''))
+		ORDER BY schedule_id, id DESC`,
+		params.orgID,
+	)
+	if err != nil {
+		return err
+	}
+
+	var ids []int64
+	kept := make(map[int64]int)
+	for rows.Next() {
+		var id, scheduleID, bytes int64
+		var startedAt time.Time
+		var size sql.NullInt64
+		var artifactPath, storageBackend, storageKey sql.NullString
+		if err := rows.Scan(&id, &scheduleID, &startedAt, &size, &bytes, &artifactPath, &storageBackend, &storageKey); err != nil {
+			rows.Close()
+			return err
 		}
-		if checksum.Valid {
-			run.Checksum = checksum.String
+
+		if kept[scheduleID] < params.keepLast {
+			kept[scheduleID]++
+			continue
 		}
-		if emailError.Valid {
-			run.EmailError = emailError.String
+		if !startedAt.Before(params.cutoff) {
+			continue
 		}
 
-		runs = append(runs, run)
+		ids = append(ids, id)
+		params.result.Bytes += size.Int64
+		if artifactPath.Valid && artifactPath.String != "" {
+			params.result.LegacyPaths = append(params.result.LegacyPaths, artifactPath.String)
+		}
+		if storageKey.Valid && storageKey.String != "" {
+			params.result.Bytes += bytes
+			params.result.External = append(params.result.External, &model.Run{
+				ID:             id,
+				ScheduleID:     scheduleID,
+				OrgID:          params.orgID,
+				StorageBackend: storageBackend.String,
+				StorageKey:     storageKey.String,
+			})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
-	return runs, nil
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range ids {
+		if _, err := tx.Exec(`UPDATE runs SET artifact_data = NULL, artifact_path = NULL, storage_key = NULL WHERE id = ?`, id); err != nil {
+			return err
+		}
+	}
+	params.result.Runs = len(ids)
+
+	return tx.Commit()
+}
+
+// Vacuum returns free database pages to the filesystem and reports the bytes reclaimed
+// (queued for serialized execution)
+func (s *Store) Vacuum() (int64, error) {
+	params := &vacuumParams{}
+	if err := s.writeQueue.enqueue(opVacuum, params); err != nil {
+		return 0, err
+	}
+	return params.reclaimed, nil
+}
+
+// vacuumDirect reclaims free pages (direct database access, called by write queue)
+func (s *Store) vacuumDirect(params *vacuumParams) error {
+	before, err := s.databaseSize()
+	if err != nil {
+		return err
+	}
+
+	var autoVacuum int
+	if err := s.db.QueryRow("PRAGMA auto_vacuum").Scan(&autoVacuum); err != nil {
+		return err
+	}
+	if autoVacuum == 2 {
+		if _, err := s.db.Exec("PRAGMA incremental_vacuum"); err != nil {
+			return fmt.Errorf("incremental vacuum failed: %w", err)
+		}
+	} else {
+		// Databases created before incremental vacuum was enabled need one full VACUUM to switch modes
+		log.Println("[STORE] Converting database to incremental auto_vacuum (full VACUUM)")
+		if _, err := s.db.Exec("PRAGMA auto_vacuum=INCREMENTAL"); err != nil {
+			return err
+		}
+		if _, err := s.db.Exec("VACUUM"); err != nil {
+			return fmt.Errorf("vacuum failed: %w", err)
+		}
+	}
+
+	// Shrink the WAL file so the reclaimed space is released on disk
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("wal checkpoint failed: %w", err)
+	}
+
+	after, err := s.databaseSize()
+	if err != nil {
+		return err
+	}
+	params.reclaimed = max(before-after, 0)
+
+	return nil
+}
+
+// databaseSize returns the size of the main database in bytes
+func (s *Store) databaseSize() (int64, error) {
+	var pageCount, pageSize int64
+	if err := s.db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, err
+	}
+	if err := s.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, err
+	}
+	return pageCount * pageSize, nil
 }
 
 // GetSettings retrieves settings for an organization
 func (s *Store) GetSettings(orgID int64) (*model.Settings, error) {
 	settings := &model.Settings{}
 	err := s.db.QueryRow(`
-		SELECT id, org_id, smtp_config, renderer_config, limits, created_at, updated_at
+		SELECT id, org_id, smtp_config, renderer_config, limits, storage, created_at, updated_at
 		FROM settings WHERE org_id = ?`,
 		orgID,
 	).Scan(
 		&settings.ID, &settings.OrgID, &settings.SMTPConfig,
-		&settings.RendererConfig, &settings.Limits, &settings.CreatedAt, &settings.UpdatedAt,
+		&settings.RendererConfig, &settings.Limits, &settings.Storage, &settings.CreatedAt, &settings.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-	return settings, err
+	if err != nil {
+		return nil, err
+	}
+	if err := s.openSettings(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// ListSettings retrieves the settings of all organizations
+func (s *Store) ListSettings() ([]*model.Settings, error) {
+	rows, err := s.db.Query(`
+		SELECT id, org_id, smtp_config, renderer_config, limits, storage, created_at, updated_at
+		FROM settings ORDER BY org_id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	all := make([]*model.Settings, 0)
+	for rows.Next() {
+		settings := &model.Settings{}
+		if err := rows.Scan(
+			&settings.ID, &settings.OrgID, &settings.SMTPConfig,
+			&settings.RendererConfig, &settings.Limits, &settings.Storage, &settings.CreatedAt, &settings.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := s.openSettings(settings); err != nil {
+			return nil, err
+		}
+		all = append(all, settings)
+	}
+
+	return all, rows.Err()
 }
 
 // UpsertSettings creates or updates settings (queued for serialized execution)
@@ -532,18 +1026,26 @@ func (s *Store) upsertSettingsDirect(settings *model.Settings) error {
 	now := time.Now()
 	settings.UpdatedAt = now
 
-	existing, err := s.GetSettings(settings.OrgID)
+	// Secrets are encrypted in a copy, leaving the caller's settings readable
+	sealed, err := s.sealSettings(settings)
 	if err != nil {
 		return err
 	}
 
-	if existing == nil {
+	// The existing settings are not read, so they can be replaced even if their secrets can't be decrypted
+	var existingID int64
+	err = s.db.QueryRow(`SELECT id FROM settings WHERE org_id = ?`, settings.OrgID).Scan(&existingID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if err == sql.ErrNoRows {
 		settings.CreatedAt = now
 		result, err := s.db.Exec(`
-			INSERT INTO settings (org_id, smtp_config, renderer_config, limits, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?)`,
-			settings.OrgID, settings.SMTPConfig, settings.RendererConfig,
-			settings.Limits, settings.CreatedAt, settings.UpdatedAt,
+			INSERT INTO settings (org_id, smtp_config, renderer_config, limits, storage, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			settings.OrgID, sealed.SMTPConfig, settings.RendererConfig,
+			settings.Limits, sealed.Storage, settings.CreatedAt, settings.UpdatedAt,
 		)
 		if err != nil {
 			return err
@@ -553,17 +1055,182 @@ func (s *Store) upsertSettingsDirect(settings *model.Settings) error {
 	} else {
 		_, err := s.db.Exec(`
 			UPDATE settings SET
-				smtp_config = ?, renderer_config = ?, limits = ?, updated_at = ?
+				smtp_config = ?, renderer_config = ?, limits = ?, storage = ?, updated_at = ?
 			WHERE org_id = ?`,
-			settings.SMTPConfig, settings.RendererConfig,
-			settings.Limits, settings.UpdatedAt, settings.OrgID,
+			sealed.SMTPConfig, settings.RendererConfig,
+			settings.Limits, sealed.Storage, settings.UpdatedAt, settings.OrgID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ErrTemplateInUse is returned when deleting a template that schedules still reference
+var ErrTemplateInUse = errors.New("template is in use")
+
+// CreateTemplate creates a new template (queued for serialized execution)
+func (s *Store) CreateTemplate(template *model.Template) error {
+	return s.writeQueue.enqueue(opCreateTemplate, template)
+}
+
+// createTemplateDirect creates a new template (direct database access, called by write queue)
+func (s *Store) createTemplateDirect(template *model.Template) error {
+	now := time.Now()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+
+	result, err := s.db.Exec(`
+		INSERT INTO templates (org_id, name, kind, config, owner_login, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		template.OrgID, template.Name, template.Kind, template.Config, template.OwnerLogin, now, now,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	template.ID = id
+
+	return nil
+}
+
+// GetTemplate retrieves a template by ID
+func (s *Store) GetTemplate(orgID, id int64) (*model.Template, error) {
+	template := &model.Template{}
+	var ownerLogin sql.NullString
+	err := s.db.QueryRow(`
+		SELECT id, org_id, name, kind, config, owner_login, created_at, updated_at
+		FROM templates WHERE id = ? AND org_id = ?`,
+		id, orgID,
+	).Scan(
+		&template.ID, &template.OrgID, &template.Name, &template.Kind,
+		&template.Config, &ownerLogin, &template.CreatedAt, &template.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("template not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	template.OwnerLogin = ownerLogin.String
+
+	return template, nil
+}
+
+// ListTemplates retrieves all templates for an organization
+func (s *Store) ListTemplates(orgID int64) ([]*model.Template, error) {
+	rows, err := s.db.Query(`
+		SELECT id, org_id, name, kind, config, owner_login, created_at, updated_at
+		FROM templates WHERE org_id = ? ORDER BY name ASC`,
+		orgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]*model.Template, 0)
+	for rows.Next() {
+		template := &model.Template{}
+		var ownerLogin sql.NullString
+		err := rows.Scan(
+			&template.ID, &template.OrgID, &template.Name, &template.Kind,
+			&template.Config, &ownerLogin, &template.CreatedAt, &template.UpdatedAt,
 		)
+		if err != nil {
+			return nil, err
+		}
+		template.OwnerLogin = ownerLogin.String
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// UpdateTemplate updates an existing template (queued for serialized execution)
+func (s *Store) UpdateTemplate(template *model.Template) error {
+	return s.writeQueue.enqueue(opUpdateTemplate, template)
+}
+
+// updateTemplateDirect updates an existing template (direct database access, called by write queue)
+func (s *Store) updateTemplateDirect(template *model.Template) error {
+	template.UpdatedAt = time.Now()
+
+	result, err := s.db.Exec(`
+		UPDATE templates SET name = ?, kind = ?, config = ?, updated_at = ?
+		WHERE id = ? AND org_id = ?`,
+		template.Name, template.Kind, template.Config, template.UpdatedAt,
+		template.ID, template.OrgID,
+	)
+	if err != nil {
 		return err
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("template not found")
+	}
+
 	return nil
 }
 
+// DeleteTemplate deletes a template (queued for serialized execution).
+// If schedules still reference the template, ErrTemplateInUse is returned unless
+// detach is true, in which case those schedules are switched back to the default layout.
+func (s *Store) DeleteTemplate(orgID, id int64, detach bool) error {
+	return s.writeQueue.enqueue(opDeleteTemplate, deleteTemplateParams{orgID: orgID, id: id, detach: detach})
+}
+
+// deleteTemplateDirect deletes a template (direct database access, called by write queue)
+func (s *Store) deleteTemplateDirect(orgID, id int64, detach bool) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow(
+		"SELECT COUNT(*) FROM schedules WHERE template_id = ? AND org_id = ?", id, orgID,
+	).Scan(&count); err != nil {
+		return err
+	}
+
+	if count > 0 {
+		if !detach {
+			return fmt.Errorf("%w: referenced by %d schedule(s)", ErrTemplateInUse, count)
+		}
+		if _, err := tx.Exec(
+			"UPDATE schedules SET template_id = NULL, updated_at = ? WHERE template_id = ? AND org_id = ?",
+			time.Now(), id, orgID,
+		); err != nil {
+			return err
+		}
+		log.Printf("[STORE] Detached template %d from %d schedule(s) in org %d", id, count, orgID)
+	}
+
+	result, err := tx.Exec("DELETE FROM templates WHERE id = ? AND org_id = ?", id, orgID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("template not found")
+	}
+
+	return tx.Commit()
+}
+
 // GetDueSchedules retrieves schedules that are due to run
 func (s *Store) GetDueSchedules() ([]*model.Schedule, error) {
 	now := time.Now().UTC().Format("2006-01-02 15:04:05")
@@ -571,12 +1238,8 @@ func (s *Store) GetDueSchedules() ([]*model.Schedule, error) {
 	// Debug: log the query parameters
 	log.Printf("[STORE] GetDueSchedules: current time = %s", now)
 
-	rows, err := s.db.Query(`
-		SELECT id, org_id, name, dashboard_uid, dashboard_title, panel_ids, range_from, range_to,
-		       interval_type, cron_expr, timezone, format, variables, recipients,
-		       email_subject, email_body, template_id, enabled, last_run_at, next_run_at,
-		       owner_user_id, created_at, updated_at
-		FROM schedules
+	rows, err := s.db.Query(
+		`SELECT `+scheduleColumns+` FROM schedules
 		WHERE enabled = 1 AND (next_run_at IS NULL OR datetime(next_run_at) <= datetime(?))
 		ORDER BY next_run_at ASC`,
 		now,
@@ -588,29 +1251,15 @@ func (s *Store) GetDueSchedules() ([]*model.Schedule, error) {
 
 	schedules := make([]*model.Schedule, 0)
 	for rows.Next() {
-		schedule := &model.Schedule{}
-		var format string // Backward compatibility - format field removed from model but may exist in old databases
-		var lastRunAtStr, nextRunAtStr sql.NullString
-
-		err := rows.Scan(
-			&schedule.ID, &schedule.OrgID, &schedule.Name, &schedule.DashboardUID,
-			&schedule.DashboardTitle, &schedule.PanelIDs, &schedule.RangeFrom, &schedule.RangeTo,
-			&schedule.IntervalType, &schedule.CronExpr, &schedule.Timezone, &format,
-			&schedule.Variables, &schedule.Recipients, &schedule.EmailSubject, &schedule.EmailBody,
-			&schedule.TemplateID, &schedule.Enabled, &lastRunAtStr, &nextRunAtStr,
-			&schedule.OwnerUserID, &schedule.CreatedAt, &schedule.UpdatedAt,
-		)
+		schedule, err := scanSchedule(rows)
 		if err != nil {
 			log.Printf("[STORE] ERROR: Failed to scan schedule row: %v", err)
 			return nil, err
 		}
-
-		// Parse timestamp strings to time.Time
-		if lastRunAtStr.Valid {
-			schedule.LastRunAt = parseTimestamp(lastRunAtStr.String)
-		}
-		if nextRunAtStr.Valid {
-			schedule.NextRunAt = parseTimestamp(nextRunAtStr.String)
+		// A schedule whose secrets can't be read is skipped rather than holding up the others
+		if err := s.openSchedule(schedule); err != nil {
+			log.Printf("[STORE] ERROR: Skipping due schedule %d: %v", schedule.ID, err)
+			continue
 		}
 
 		log.Printf("[STORE] Found due schedule: ID=%d, Name='%s', NextRunAt=%v", schedule.ID, schedule.Name, schedule.NextRunAt)
@@ -0,0 +1,847 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/scheduled-reports-app/pkg/model"
+	"github.com/yourusername/scheduled-reports-app/pkg/secrets"
+)
+
+// TestTemplateCRUD tests the full template lifecycle including delete protection
+func TestTemplateCRUD(t *testing.T) {
+	dbPath := "test_templates.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	template := &model.Template{
+		OrgID:      1,
+		Name:       "Branded",
+		Kind:       "pdf",
+		OwnerLogin: "editor",
+		Config: model.TemplateConfig{
+			Header:      "{{schedule.name}}",
+			PageSize:    "A4",
+			Orientation: "landscape",
+			Margins:     &model.Margins{Top: 15, Bottom: 15, Left: 10, Right: 10},
+		},
+	}
+	if err := store.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+	if template.ID == 0 {
+		t.Fatal("CreateTemplate() did not set template ID")
+	}
+
+	// Templates are scoped per org
+	if _, err := store.GetTemplate(2, template.ID); err == nil {
+		t.Error("GetTemplate() returned a template from another org")
+	}
+
+	got, err := store.GetTemplate(1, template.ID)
+	if err != nil {
+		t.Fatalf("GetTemplate() error = %v", err)
+	}
+	if got.Config.Margins == nil || got.Config.Margins.Top != 15 || got.Config.Header != "{{schedule.name}}" {
+		t.Errorf("GetTemplate() config = %+v, want stored config", got.Config)
+	}
+
+	if got.OwnerLogin != "editor" {
+		t.Errorf("GetTemplate() OwnerLogin = %q, want the creator", got.OwnerLogin)
+	}
+
+	template.Name = "Branded v2"
+	if err := store.UpdateTemplate(template); err != nil {
+		t.Fatalf("UpdateTemplate() error = %v", err)
+	}
+
+	templates, err := store.ListTemplates(1)
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+	if len(templates) != 1 || templates[0].Name != "Branded v2" || templates[0].OwnerLogin != "editor" {
+		t.Errorf("ListTemplates() = %+v, want one updated template", templates)
+	}
+
+	// Reference the template from a schedule
+	nextRun := time.Now().Add(time.Hour)
+	schedule := &model.Schedule{
+		OrgID:        1,
+		Name:         "Uses template",
+		DashboardUID: "test-dashboard",
+		RangeFrom:    "now-1h",
+		RangeTo:      "now",
+		IntervalType: "daily",
+		Timezone:     "UTC",
+		Recipients:   model.Recipients{To: []string{"test@example.com"}},
+		EmailSubject: "Report",
+		EmailBody:    "Body",
+		TemplateID:   &template.ID,
+		Enabled:      true,
+		NextRunAt:    &nextRun,
+		OwnerUserID:  1,
+	}
+	if err := store.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+
+	// Delete must be refused while the template is referenced
+	err = store.DeleteTemplate(1, template.ID, false)
+	if !errors.Is(err, ErrTemplateInUse) {
+		t.Fatalf("DeleteTemplate() error = %v, want ErrTemplateInUse", err)
+	}
+
+	// Detaching removes the reference and deletes the template
+	if err := store.DeleteTemplate(1, template.ID, true); err != nil {
+		t.Fatalf("DeleteTemplate(detach) error = %v", err)
+	}
+	if _, err := store.GetTemplate(1, template.ID); err == nil {
+		t.Error("GetTemplate() found a deleted template")
+	}
+
+	updated, err := store.GetSchedule(1, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if updated.TemplateID != nil {
+		t.Errorf("schedule TemplateID = %d, want nil after detach", *updated.TemplateID)
+	}
+}
+
+// TestScheduleLayoutAndFormat tests that layout and format default to single-page PDF and survive updates
+func TestScheduleLayoutAndFormat(t *testing.T) {
+	dbPath := "test_schedule_layout.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	schedule := &model.Schedule{
+		OrgID:        1,
+		Name:         "Layout",
+		DashboardUID: "test-dashboard",
+		RangeFrom:    "now-1h",
+		RangeTo:      "now",
+		IntervalType: "daily",
+		Timezone:     "UTC",
+		Recipients:   model.Recipients{To: []string{"test@example.com"}},
+		EmailSubject: "Report",
+		EmailBody:    "Body",
+		OwnerUserID:  1,
+		OwnerLogin:   "editor",
+	}
+	if err := store.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+
+	got, err := store.GetSchedule(1, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if got.Layout != model.LayoutSingle {
+		t.Errorf("Layout = %q, want %q", got.Layout, model.LayoutSingle)
+	}
+	if got.Format != model.FormatPDF {
+		t.Errorf("Format = %q, want %q", got.Format, model.FormatPDF)
+	}
+
+	if got.Dashboards != nil {
+		t.Errorf("Dashboards = %+v, want none", got.Dashboards)
+	}
+	if got.Theme != "" {
+		t.Errorf("Theme = %q, want the default", got.Theme)
+	}
+
+	got.Layout = model.LayoutPaginated
+	got.Format = model.FormatJPEG
+	got.Theme = model.ThemeDark
+	got.OwnerLogin = "someone-else" // The owner is set on creation only
+	got.Dashboards = model.DashboardRefs{{UID: "a", Title: "Overview"}, {UID: "b", PanelIDs: model.IntSlice{2}, RangeFrom: "now-1d", RangeTo: "now"}}
+	if err := store.UpdateSchedule(got); err != nil {
+		t.Fatalf("UpdateSchedule() error = %v", err)
+	}
+
+	schedules, err := store.ListSchedules(1)
+	if err != nil {
+		t.Fatalf("ListSchedules() error = %v", err)
+	}
+	if len(schedules) != 1 || schedules[0].Layout != model.LayoutPaginated || schedules[0].Format != model.FormatJPEG {
+		t.Errorf("ListSchedules() = %+v, want one paginated JPEG schedule", schedules)
+	}
+	if dashboards := schedules[0].Dashboards; len(dashboards) != 2 || dashboards[1].UID != "b" || dashboards[1].PanelIDs[0] != 2 {
+		t.Errorf("Dashboards = %+v, want the two dashboards in order", dashboards)
+	}
+	if schedules[0].Theme != model.ThemeDark {
+		t.Errorf("Theme = %q, want %q", schedules[0].Theme, model.ThemeDark)
+	}
+	if schedules[0].OwnerLogin != "editor" {
+		t.Errorf("OwnerLogin = %q, want the creator", schedules[0].OwnerLogin)
+	}
+}
+
+// TestRecordScheduleRun tests that the scheduler's bookkeeping keeps edits saved while a run was in progress
+func TestRecordScheduleRun(t *testing.T) {
+	dbPath := "test_record_schedule_run.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	schedule := &model.Schedule{
+		OrgID: 1, Name: "Before", DashboardUID: "abc", IntervalType: "daily", Timezone: "UTC", Enabled: true,
+		Recipients: model.Recipients{To: []string{"a@example.com"}},
+	}
+	if err := store.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+
+	// The schedule is edited while the scheduler holds the copy it read
+	running, err := store.GetSchedule(1, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	schedule.Name = "After"
+	schedule.Recipients.To = []string{"b@example.com"}
+	if err := store.UpdateSchedule(schedule); err != nil {
+		t.Fatalf("UpdateSchedule() error = %v", err)
+	}
+
+	nextRun := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	if err := store.SetNextRun(1, running.ID, nextRun); err != nil {
+		t.Fatalf("SetNextRun() error = %v", err)
+	}
+	lastRun := time.Now().UTC().Truncate(time.Second)
+	if err := store.RecordScheduleRun(1, running.ID, lastRun, false); err != nil {
+		t.Fatalf("RecordScheduleRun() error = %v", err)
+	}
+	got, err := store.GetSchedule(1, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if got.Name != "After" || got.Recipients.To[0] != "b@example.com" || !got.Enabled {
+		t.Errorf("schedule = %+v, want the edit kept", got)
+	}
+	if got.NextRunAt == nil || !got.NextRunAt.Equal(nextRun) || got.LastRunAt == nil || !got.LastRunAt.Equal(lastRun) {
+		t.Errorf("NextRunAt, LastRunAt = %v, %v; want %v, %v", got.NextRunAt, got.LastRunAt, nextRun, lastRun)
+	}
+
+	// Disabling only touches enabled and next_run_at
+	if err := store.RecordScheduleRun(1, running.ID, lastRun, true); err != nil {
+		t.Fatalf("RecordScheduleRun() error = %v", err)
+	}
+	got, err = store.GetSchedule(1, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if got.Enabled || got.NextRunAt != nil || got.Name != "After" {
+		t.Errorf("schedule = %+v, want it disabled with the edit kept", got)
+	}
+}
+
+// TestPruneArtifacts tests that expired artifacts are removed while recent and kept runs retain theirs
+func TestPruneArtifacts(t *testing.T) {
+	dbPath := "test_prune_artifacts.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	schedule := &model.Schedule{
+		OrgID:        1,
+		Name:         "Retention",
+		DashboardUID: "test-dashboard",
+		RangeFrom:    "now-1h",
+		RangeTo:      "now",
+		IntervalType: "daily",
+		Timezone:     "UTC",
+		Recipients:   model.Recipients{To: []string{"test@example.com"}},
+		EmailSubject: "Report",
+		EmailBody:    "Body",
+		OwnerUserID:  1,
+	}
+	if err := store.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+
+	// Three expired runs followed by a recent one, oldest first
+	ages := []time.Duration{90 * 24 * time.Hour, 60 * 24 * time.Hour, 40 * 24 * time.Hour, time.Hour}
+	runs := make([]*model.Run, 0, len(ages))
+	for _, age := range ages {
+		run := &model.Run{ScheduleID: schedule.ID, OrgID: 1, StartedAt: time.Now().Add(-age), Status: "running"}
+		if err := store.CreateRun(run); err != nil {
+			t.Fatalf("CreateRun() error = %v", err)
+		}
+		run.Status = "completed"
+		run.ArtifactData = make([]byte, 1000)
+		if err := store.UpdateRun(run); err != nil {
+			t.Fatalf("UpdateRun() error = %v", err)
+		}
+		runs = append(runs, run)
+	}
+
+	// Other orgs are untouched
+	result, err := store.PruneArtifacts(2, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("PruneArtifacts(org 2) error = %v", err)
+	}
+	if result.Runs != 0 {
+		t.Errorf("PruneArtifacts(org 2) removed %d run(s), want 0", result.Runs)
+	}
+
+	// 30-day retention keeping the last two runs: only the two oldest lose their artifacts
+	result, err = store.PruneArtifacts(1, time.Now().AddDate(0, 0, -30), 2)
+	if err != nil {
+		t.Fatalf("PruneArtifacts() error = %v", err)
+	}
+	if result.Runs != 2 || result.Bytes != 2000 {
+		t.Errorf("PruneArtifacts() = %+v, want 2 runs and 2000 bytes", result)
+	}
+
+	for i, want := range []bool{false, false, true, true} {
+		got, err := store.GetRun(1, runs[i].ID)
+		if err != nil {
+			t.Fatalf("GetRun() error = %v", err)
+		}
+		if hasArtifact := len(got.ArtifactData) > 0; hasArtifact != want {
+			t.Errorf("run %d has artifact = %v, want %v", i, hasArtifact, want)
+		}
+		if got.Status != "completed" {
+			t.Errorf("run %d status = %q, want the run record kept", i, got.Status)
+		}
+	}
+
+	if _, err := store.Vacuum(); err != nil {
+		t.Errorf("Vacuum() error = %v", err)
+	}
+}
+
+// TestSettingsStorage tests that the artifact storage selection survives a settings round trip
+func TestSettingsStorage(t *testing.T) {
+	dbPath := "test_settings_storage.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	settings := &model.Settings{
+		OrgID: 1,
+		Storage: model.StorageConfig{
+			Backend: model.StorageS3,
+			S3:      &model.S3Config{Endpoint: "http://minio:9000", Bucket: "reports", UsePathStyle: true},
+		},
+	}
+	if err := store.UpsertSettings(settings); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+
+	all, err := store.ListSettings()
+	if err != nil {
+		t.Fatalf("ListSettings() error = %v", err)
+	}
+	if len(all) != 1 || all[0].Storage.Backend != model.StorageS3 || all[0].Storage.S3 == nil || all[0].Storage.S3.Bucket != "reports" {
+		t.Errorf("ListSettings() = %+v, want stored S3 storage", all)
+	}
+}
+
+// TestSettingsSecrets tests that settings secrets are encrypted at rest, including rows stored
+// before encryption, and re-encrypted after a key rotation
+func TestSettingsSecrets(t *testing.T) {
+	dbPath := "test_settings_secrets.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	rawSecrets := func() string {
+		var smtp, storage string
+		if err := store.db.QueryRow(`SELECT smtp_config, storage FROM settings WHERE org_id = 1`).Scan(&smtp, &storage); err != nil {
+			t.Fatalf("reading settings: %v", err)
+		}
+		return smtp + storage
+	}
+	assertSecrets := func() {
+		t.Helper()
+		loaded, err := store.GetSettings(1)
+		if err != nil {
+			t.Fatalf("GetSettings() error = %v", err)
+		}
+		if loaded.SMTPConfig.Password != "smtp-secret" || loaded.Storage.S3.SecretAccessKey != "s3-secret" {
+			t.Errorf("secrets = %q, %q; want them decrypted", loaded.SMTPConfig.Password, loaded.Storage.S3.SecretAccessKey)
+		}
+	}
+
+	// Settings saved before a key was configured are plaintext
+	settings := &model.Settings{
+		OrgID:      1,
+		SMTPConfig: &model.SMTPConfig{Host: "smtp.example.com", Password: "smtp-secret"},
+		Storage:    model.StorageConfig{Backend: model.StorageS3, S3: &model.S3Config{Bucket: "reports", SecretAccessKey: "s3-secret"}},
+	}
+	if err := store.UpsertSettings(settings); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+	if raw := rawSecrets(); !strings.Contains(raw, "smtp-secret") {
+		t.Fatalf("stored settings = %s, want plaintext without a key", raw)
+	}
+	lookalike := *settings
+	lookalike.SMTPConfig = &model.SMTPConfig{Host: "smtp.example.com", Password: "enc:v1:abc"}
+	if err := store.UpsertSettings(&lookalike); err == nil {
+		t.Error("UpsertSettings() stored a password looking encrypted as plaintext, want an error")
+	}
+	if _, err := store.EncryptSecrets(); !errors.Is(err, secrets.ErrNoKey) {
+		t.Errorf("EncryptSecrets() without a key error = %v, want ErrNoKey", err)
+	}
+	if encrypted, err := store.HasEncryptedSecrets(); err != nil || encrypted {
+		t.Errorf("HasEncryptedSecrets() = %v, %v; want false for plaintext settings", encrypted, err)
+	}
+
+	// Configuring a key encrypts them
+	first, _ := secrets.NewKeyring("first key")
+	store.SetKeyring(first)
+	if updated, err := store.EncryptSecrets(); err != nil || updated.Settings != 1 {
+		t.Fatalf("EncryptSecrets() = %+v, %v; want 1 org updated", updated, err)
+	}
+	if raw := rawSecrets(); strings.Contains(raw, "smtp-secret") || strings.Contains(raw, "s3-secret") {
+		t.Errorf("stored settings = %s, want the secrets encrypted", raw)
+	}
+	if encrypted, err := store.HasEncryptedSecrets(); err != nil || !encrypted {
+		t.Errorf("HasEncryptedSecrets() = %v, %v; want true once the key is needed", encrypted, err)
+	}
+	assertSecrets()
+	if settings.SMTPConfig.Password != "smtp-secret" {
+		t.Error("UpsertSettings() encrypted the caller's settings")
+	}
+	if updated, err := store.EncryptSecrets(); err != nil || updated.Settings != 0 {
+		t.Errorf("EncryptSecrets() again = %+v, %v; want nothing to do", updated, err)
+	}
+
+	// New settings are encrypted as they are saved
+	settings.SMTPConfig.Password = "new-secret"
+	if err := store.UpsertSettings(settings); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+	if raw := rawSecrets(); strings.Contains(raw, "new-secret") {
+		t.Errorf("stored settings = %s, want the new secret encrypted", raw)
+	}
+
+	// Secrets entered with the encrypted prefix are encrypted as well, and read back as entered
+	settings.SMTPConfig.Password = "enc:v1:abc"
+	if err := store.UpsertSettings(settings); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+	if loaded, err := store.GetSettings(1); err != nil || loaded.SMTPConfig.Password != "enc:v1:abc" {
+		t.Errorf("GetSettings() = %+v, %v; want the password as entered", loaded, err)
+	}
+	settings.SMTPConfig.Password = "smtp-secret"
+	if err := store.UpsertSettings(settings); err != nil {
+		t.Fatalf("UpsertSettings() error = %v", err)
+	}
+
+	// Rotating the key re-encrypts with the new key, after which the old key is no longer needed
+	second, _ := secrets.NewKeyring("second key", "first key")
+	store.SetKeyring(second)
+	if updated, err := store.EncryptSecrets(); err != nil || updated.Settings != 1 {
+		t.Fatalf("EncryptSecrets() after rotation = %+v, %v; want 1 org updated", updated, err)
+	}
+	secondOnly, _ := secrets.NewKeyring("second key")
+	store.SetKeyring(secondOnly)
+	assertSecrets()
+
+	// Without the key the settings can't be read, but can still be replaced
+	other, _ := secrets.NewKeyring("other key")
+	store.SetKeyring(other)
+	if _, err := store.GetSettings(1); err == nil {
+		t.Error("GetSettings() with the wrong key succeeded")
+	}
+	if err := store.UpsertSettings(settings); err != nil {
+		t.Errorf("UpsertSettings() with the wrong key error = %v", err)
+	}
+}
+
+func TestTargetSecrets(t *testing.T) {
+	dbPath := "test_target_secrets.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	schedule := &model.Schedule{
+		OrgID: 1, Name: "Targets", DashboardUID: "abc", IntervalType: "daily", Timezone: "UTC", Enabled: true,
+		Targets: model.DeliveryTargets{
+			{Type: model.TargetSlack, Slack: &model.SlackTarget{Token: "xoxb-secret", Channel: "C1"}},
+			{Type: model.TargetSFTP, SFTP: &model.SFTPTarget{Host: "sftp.example.com", Username: "reports", Password: "sftp-secret"}},
+		},
+	}
+	rawTargets := func() string {
+		var targets string
+		if err := store.db.QueryRow(`SELECT targets FROM schedules WHERE id = ?`, schedule.ID).Scan(&targets); err != nil {
+			t.Fatalf("reading targets: %v", err)
+		}
+		return targets
+	}
+	assertSecrets := func() {
+		t.Helper()
+		loaded, err := store.GetSchedule(1, schedule.ID)
+		if err != nil {
+			t.Fatalf("GetSchedule() error = %v", err)
+		}
+		if loaded.Targets[0].Slack.Token != "xoxb-secret" || loaded.Targets[1].SFTP.Password != "sftp-secret" {
+			t.Errorf("secrets = %q, %q; want them decrypted", loaded.Targets[0].Slack.Token, loaded.Targets[1].SFTP.Password)
+		}
+	}
+
+	// Schedules saved before a key was configured are plaintext, until EncryptSecrets encrypts them
+	if err := store.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	if raw := rawTargets(); !strings.Contains(raw, "xoxb-secret") {
+		t.Fatalf("stored targets = %s, want plaintext without a key", raw)
+	}
+	if encrypted, err := store.HasEncryptedSecrets(); err != nil || encrypted {
+		t.Errorf("HasEncryptedSecrets() = %v, %v; want false for plaintext targets", encrypted, err)
+	}
+	first, _ := secrets.NewKeyring("first key")
+	store.SetKeyring(first)
+	if updated, err := store.EncryptSecrets(); err != nil || updated.Schedules != 1 {
+		t.Fatalf("EncryptSecrets() = %+v, %v; want 1 schedule updated", updated, err)
+	}
+	if raw := rawTargets(); strings.Contains(raw, "xoxb-secret") || strings.Contains(raw, "sftp-secret") || !strings.Contains(raw, "C1") {
+		t.Errorf("stored targets = %s, want only the secrets encrypted", raw)
+	}
+	if encrypted, err := store.HasEncryptedSecrets(); err != nil || !encrypted {
+		t.Errorf("HasEncryptedSecrets() = %v, %v; want true for encrypted targets", encrypted, err)
+	}
+	assertSecrets()
+
+	// Updates are encrypted as they are saved, leaving the caller's schedule readable
+	schedule.Targets[0].Slack.Token = "xoxb-new"
+	if err := store.UpdateSchedule(schedule); err != nil {
+		t.Fatalf("UpdateSchedule() error = %v", err)
+	}
+	if raw := rawTargets(); strings.Contains(raw, "xoxb-new") {
+		t.Errorf("stored targets = %s, want the new token encrypted", raw)
+	}
+	if schedule.Targets[0].Slack.Token != "xoxb-new" {
+		t.Error("UpdateSchedule() encrypted the caller's targets")
+	}
+	schedule.Targets[0].Slack.Token = "xoxb-secret"
+	if err := store.UpdateSchedule(schedule); err != nil {
+		t.Fatalf("UpdateSchedule() error = %v", err)
+	}
+
+	// Rotating the key re-encrypts the targets too
+	second, _ := secrets.NewKeyring("second key", "first key")
+	store.SetKeyring(second)
+	if updated, err := store.EncryptSecrets(); err != nil || updated.Schedules != 1 {
+		t.Fatalf("EncryptSecrets() after rotation = %+v, %v; want 1 schedule updated", updated, err)
+	}
+	secondOnly, _ := secrets.NewKeyring("second key")
+	store.SetKeyring(secondOnly)
+	assertSecrets()
+	if due, err := store.GetDueSchedules(); err != nil || len(due) != 1 || due[0].Targets[0].Slack.Token != "xoxb-secret" {
+		t.Errorf("GetDueSchedules() = %+v, %v; want the schedule with its secrets decrypted", due, err)
+	}
+
+	// Without the key the schedule can't be read, and isn't run
+	other, _ := secrets.NewKeyring("other key")
+	store.SetKeyring(other)
+	if _, err := store.GetSchedule(1, schedule.ID); err == nil {
+		t.Error("GetSchedule() with the wrong key succeeded")
+	}
+	if due, err := store.GetDueSchedules(); err != nil || len(due) != 0 {
+		t.Errorf("GetDueSchedules() with the wrong key = %+v, %v; want the schedule skipped", due, err)
+	}
+}
+
+// TestSaveDelivery tests that deliveries are recorded per run and target, and redelivery replaces the outcome
+func TestSaveDelivery(t *testing.T) {
+	dbPath := "test_deliveries.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	run := &model.Run{ScheduleID: 1, OrgID: 1, StartedAt: time.Now(), Status: "completed"}
+	if err := store.CreateRun(run); err != nil {
+		t.Fatalf("CreateRun() error = %v", err)
+	}
+
+	email := &model.DeliveryResult{Index: model.EmailTargetIndex, Type: model.TargetEmail, Target: "2 recipient(s)", Success: true, Attempts: 1, DeliveredAt: time.Now()}
+	slack := &model.DeliveryResult{Index: 0, Type: model.TargetSlack, Target: "C1", Error: "channel_not_found", Attempts: 3, DeliveredAt: time.Now()}
+	for _, result := range []*model.DeliveryResult{email, slack} {
+		if err := store.SaveDelivery(run.OrgID, run.ID, result); err != nil {
+			t.Fatalf("SaveDelivery() error = %v", err)
+		}
+	}
+	if email.ID == 0 || slack.ID == 0 || email.ID == slack.ID {
+		t.Fatalf("delivery IDs = %d, %d, want distinct IDs", email.ID, slack.ID)
+	}
+
+	// Redelivering the same target updates its row
+	retried := &model.DeliveryResult{Index: 0, Type: model.TargetSlack, Target: "C1", Success: true, Attempts: 1, DeliveredAt: time.Now()}
+	if err := store.SaveDelivery(run.OrgID, run.ID, retried); err != nil {
+		t.Fatalf("SaveDelivery() error = %v", err)
+	}
+	if retried.ID != slack.ID {
+		t.Errorf("redelivery ID = %d, want the existing row %d", retried.ID, slack.ID)
+	}
+
+	deliveries, err := store.ListDeliveries(run.OrgID, run.ID)
+	if err != nil {
+		t.Fatalf("ListDeliveries() error = %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("ListDeliveries() = %+v, want 2 deliveries", deliveries)
+	}
+	if deliveries[0].Type != model.TargetEmail || deliveries[1].Type != model.TargetSlack {
+		t.Errorf("deliveries = %+v, want email then slack", deliveries)
+	}
+	if !deliveries[1].Success || deliveries[1].Error != "" || deliveries[1].Attempts != 1 {
+		t.Errorf("slack delivery = %+v, want the redelivered outcome", deliveries[1])
+	}
+
+	// Other orgs can't see the run's deliveries
+	if other, err := store.ListDeliveries(2, run.ID); err != nil || len(other) != 0 {
+		t.Errorf("ListDeliveries() for another org = %+v, %v; want none", other, err)
+	}
+
+	// Runs carry their deliveries from the deliveries table
+	stored, err := store.GetRun(run.OrgID, run.ID)
+	if err != nil {
+		t.Fatalf("GetRun() error = %v", err)
+	}
+	if len(stored.Deliveries) != 2 || !stored.Deliveries[1].Success {
+		t.Errorf("GetRun() deliveries = %+v, want the 2 recorded deliveries", stored.Deliveries)
+	}
+	runs, err := store.ListRuns(run.OrgID, run.ScheduleID)
+	if err != nil {
+		t.Fatalf("ListRuns() error = %v", err)
+	}
+	if len(runs) != 1 || len(runs[0].Deliveries) != 2 || runs[0].Deliveries[0].ID != email.ID {
+		t.Errorf("ListRuns() = %+v, want the run with its 2 deliveries", runs)
+	}
+}
+
+// TestDeleteScheduleDeliveries tests that deleting a schedule deletes the deliveries of its runs
+func TestDeleteScheduleDeliveries(t *testing.T) {
+	dbPath := "test_delete_schedule_deliveries.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	var runs []*model.Run
+	for _, name := range []string{"Deleted", "Kept"} {
+		schedule := &model.Schedule{OrgID: 1, Name: name, DashboardUID: "abc", IntervalType: "daily", Timezone: "UTC"}
+		if err := store.CreateSchedule(schedule); err != nil {
+			t.Fatalf("CreateSchedule() error = %v", err)
+		}
+		run := &model.Run{ScheduleID: schedule.ID, OrgID: 1, StartedAt: time.Now(), Status: "completed"}
+		if err := store.CreateRun(run); err != nil {
+			t.Fatalf("CreateRun() error = %v", err)
+		}
+		result := &model.DeliveryResult{Index: 0, Type: model.TargetSlack, Target: "C1", Success: true, Attempts: 1, DeliveredAt: time.Now()}
+		if err := store.SaveDelivery(1, run.ID, result); err != nil {
+			t.Fatalf("SaveDelivery() error = %v", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := store.DeleteSchedule(1, runs[0].ScheduleID); err != nil {
+		t.Fatalf("DeleteSchedule() error = %v", err)
+	}
+	var count int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM deliveries WHERE run_id = ?`, runs[0].ID).Scan(&count); err != nil {
+		t.Fatalf("counting deliveries: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("deliveries of the deleted schedule's run = %d, want none", count)
+	}
+	if deliveries, err := store.ListDeliveries(1, runs[1].ID); err != nil || len(deliveries) != 1 {
+		t.Errorf("ListDeliveries() of the other schedule = %+v, %v; want its delivery kept", deliveries, err)
+	}
+
+	// The run stays until retention has removed its artifact
+	if _, err := store.GetRun(1, runs[0].ID); err != nil {
+		t.Errorf("GetRun() after deleting its schedule error = %v", err)
+	}
+}
+
+func TestBurstRuns(t *testing.T) {
+	dbPath := "test_burst.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	schedule := &model.Schedule{
+		OrgID:        1,
+		Name:         "Regional",
+		DashboardUID: "abc",
+		IntervalType: "daily",
+		Timezone:     "UTC",
+		Burst: &model.Burst{Variable: "region", Values: []model.BurstValue{
+			{Value: "emea", Recipients: model.Recipients{To: []string{"emea@example.com"}}},
+			{Value: "apac", Recipients: model.Recipients{To: []string{"apac@example.com"}}},
+		}},
+	}
+	if err := store.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	loaded, err := store.GetSchedule(1, schedule.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if loaded.Burst == nil || loaded.Burst.Variable != "region" || len(loaded.Burst.Values) != 2 {
+		t.Fatalf("Burst = %+v, want the region burst", loaded.Burst)
+	}
+	if apac := loaded.Burst.Find("apac"); apac == nil || apac.Recipients.To[0] != "apac@example.com" {
+		t.Errorf("Find(apac) = %+v", apac)
+	}
+
+	parent := &model.Run{ScheduleID: schedule.ID, OrgID: 1, StartedAt: time.Now(), Status: "running"}
+	if err := store.CreateRun(parent); err != nil {
+		t.Fatalf("CreateRun() error = %v", err)
+	}
+	from := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 10, 23, 59, 59, 0, time.UTC)
+	child := &model.Run{ScheduleID: schedule.ID, OrgID: 1, StartedAt: time.Now(), Status: "running", ParentRunID: &parent.ID, BurstValue: "emea", RangeFrom: &from, RangeTo: &to}
+	if err := store.CreateRun(child); err != nil {
+		t.Fatalf("CreateRun() error = %v", err)
+	}
+
+	runs, err := store.ListRuns(1, schedule.ID)
+	if err != nil {
+		t.Fatalf("ListRuns() error = %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("ListRuns() returned %d runs, want 2", len(runs))
+	}
+	for _, run := range runs {
+		switch run.ID {
+		case parent.ID:
+			if run.ParentRunID != nil || run.BurstValue != "" || run.RangeFrom != nil {
+				t.Errorf("parent run = %+v, want no parent, burst value or time range", run)
+			}
+		case child.ID:
+			if run.ParentRunID == nil || *run.ParentRunID != parent.ID || run.BurstValue != "emea" {
+				t.Errorf("child run = %+v, want parent %d and value emea", run, parent.ID)
+			}
+			if run.RangeFrom == nil || !run.RangeFrom.Equal(from) || run.RangeTo == nil || !run.RangeTo.Equal(to) {
+				t.Errorf("child run range = %v to %v, want %v to %v", run.RangeFrom, run.RangeTo, from, to)
+			}
+		}
+	}
+}
+
+// TestAuditEvents tests recording audit events and listing them with filters and pagination
+func TestAuditEvents(t *testing.T) {
+	dbPath := "test_audit.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	events := []*model.AuditEvent{
+		{OrgID: 1, UserLogin: "alice", Action: model.AuditScheduleCreate, ResourceType: model.AuditResourceSchedule, ResourceID: 7,
+			Diff: []byte(`{"name":{"old":null,"new":"Weekly"}}`), CreatedAt: start},
+		{OrgID: 1, UserLogin: "bob", Action: model.AuditScheduleRun, ResourceType: model.AuditResourceSchedule, ResourceID: 7,
+			CreatedAt: start.Add(time.Hour)},
+		{OrgID: 1, UserLogin: "alice", Action: model.AuditSettingsUpdate, ResourceType: model.AuditResourceSettings,
+			CreatedAt: start.Add(2 * time.Hour)},
+		{OrgID: 2, UserLogin: "alice", Action: model.AuditScheduleDelete, ResourceType: model.AuditResourceSchedule, ResourceID: 9,
+			CreatedAt: start.Add(3 * time.Hour)},
+	}
+	for _, event := range events {
+		if err := store.CreateAuditEvent(event); err != nil {
+			t.Fatalf("CreateAuditEvent() error = %v", err)
+		}
+		if event.ID == 0 {
+			t.Fatal("CreateAuditEvent() did not set the event ID")
+		}
+	}
+
+	tests := []struct {
+		name      string
+		filter    AuditFilter
+		wantIDs   []int64
+		wantTotal int
+	}{
+		{"org, newest first", AuditFilter{OrgID: 1}, []int64{events[2].ID, events[1].ID, events[0].ID}, 3},
+		{"user", AuditFilter{OrgID: 1, UserLogin: "alice"}, []int64{events[2].ID, events[0].ID}, 2},
+		{"action", AuditFilter{OrgID: 1, Action: model.AuditScheduleRun}, []int64{events[1].ID}, 1},
+		{"resource", AuditFilter{OrgID: 1, ResourceType: model.AuditResourceSchedule, ResourceID: 7}, []int64{events[1].ID, events[0].ID}, 2},
+		{"time range", AuditFilter{OrgID: 1, From: start.Add(time.Hour), To: start.Add(2 * time.Hour)}, []int64{events[1].ID}, 1},
+		{"page", AuditFilter{OrgID: 1, Limit: 1, Offset: 1}, []int64{events[1].ID}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := store.ListAuditEvents(tt.filter)
+			if err != nil {
+				t.Fatalf("ListAuditEvents() error = %v", err)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("ListAuditEvents() total = %d, want %d", total, tt.wantTotal)
+			}
+			var ids []int64
+			for _, event := range got {
+				ids = append(ids, event.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("ListAuditEvents() IDs = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("ListAuditEvents() IDs = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+		})
+	}
+
+	got, _, err := store.ListAuditEvents(AuditFilter{OrgID: 1, Action: model.AuditScheduleCreate})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("ListAuditEvents() = %v, %v", got, err)
+	}
+	if event := got[0]; event.UserLogin != "alice" || event.ResourceID != 7 || !event.CreatedAt.Equal(start) ||
+		string(event.Diff) != `{"name":{"old":null,"new":"Weekly"}}` {
+		t.Errorf("ListAuditEvents() event = %+v", event)
+	}
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/yourusername/scheduled-reports-app/pkg/model"
 )
@@ -14,9 +15,21 @@ const (
 	opCreateSchedule writeOpType = iota
 	opUpdateSchedule
 	opDeleteSchedule
+	opSetNextRun
+	opRecordScheduleRun
+	opSetScheduleOwnerLogin
 	opCreateRun
 	opUpdateRun
 	opUpsertSettings
+	opCreateTemplate
+	opUpdateTemplate
+	opDeleteTemplate
+	opPruneArtifacts
+	opVacuum
+	opSaveDelivery
+	opEncryptSecrets
+	opCreateAuditEvent
+	opCreateSigningKey
 )
 
 // writeOp represents a single write operation with its response channel
@@ -98,6 +111,18 @@ func (wq *writeQueue) executeOp(db *Store, op writeOp) {
 		params := op.data.(deleteScheduleParams)
 		result.err = db.deleteScheduleDirect(params.orgID, params.id)
 
+	case opSetNextRun:
+		params := op.data.(setNextRunParams)
+		result.err = db.setNextRunDirect(params.orgID, params.id, params.nextRunAt)
+
+	case opRecordScheduleRun:
+		params := op.data.(recordScheduleRunParams)
+		result.err = db.recordScheduleRunDirect(params)
+
+	case opSetScheduleOwnerLogin:
+		params := op.data.(setScheduleOwnerLoginParams)
+		result.err = db.setScheduleOwnerLoginDirect(params.orgID, params.id, params.login)
+
 	case opCreateRun:
 		run := op.data.(*model.Run)
 		result.err = db.createRunDirect(run)
@@ -111,6 +136,42 @@ func (wq *writeQueue) executeOp(db *Store, op writeOp) {
 		settings := op.data.(*model.Settings)
 		result.err = db.upsertSettingsDirect(settings)
 		result.id = settings.ID
+
+	case opCreateTemplate:
+		template := op.data.(*model.Template)
+		result.err = db.createTemplateDirect(template)
+		result.id = template.ID
+
+	case opUpdateTemplate:
+		template := op.data.(*model.Template)
+		result.err = db.updateTemplateDirect(template)
+
+	case opDeleteTemplate:
+		params := op.data.(deleteTemplateParams)
+		result.err = db.deleteTemplateDirect(params.orgID, params.id, params.detach)
+
+	case opPruneArtifacts:
+		result.err = db.pruneArtifactsDirect(op.data.(*pruneArtifactsParams))
+
+	case opVacuum:
+		result.err = db.vacuumDirect(op.data.(*vacuumParams))
+
+	case opSaveDelivery:
+		params := op.data.(saveDeliveryParams)
+		result.err = db.saveDeliveryDirect(params.orgID, params.runID, params.result)
+		result.id = params.result.ID
+
+	case opEncryptSecrets:
+		result.err = db.encryptSecretsDirect(op.data.(*encryptSecretsParams))
+
+	case opCreateAuditEvent:
+		event := op.data.(*model.AuditEvent)
+		result.err = db.createAuditEventDirect(event)
+		result.id = event.ID
+
+	case opCreateSigningKey:
+		params := op.data.(createSigningKeyParams)
+		result.err = db.createSigningKeyDirect(params.name)
 	}
 
 	// Send result back to caller
@@ -155,3 +216,53 @@ type deleteScheduleParams struct {
 	orgID int64
 	id    int64
 }
+
+type setNextRunParams struct {
+	orgID     int64
+	id        int64
+	nextRunAt time.Time
+}
+
+type setScheduleOwnerLoginParams struct {
+	orgID int64
+	id    int64
+	login string
+}
+
+type recordScheduleRunParams struct {
+	orgID     int64
+	id        int64
+	lastRunAt time.Time
+	disable   bool
+}
+
+type deleteTemplateParams struct {
+	orgID  int64
+	id     int64
+	detach bool
+}
+
+type pruneArtifactsParams struct {
+	orgID    int64
+	cutoff   time.Time
+	keepLast int
+	result   *PruneResult
+}
+
+type vacuumParams struct {
+	reclaimed int64
+}
+
+type saveDeliveryParams struct {
+	orgID  int64
+	runID  int64
+	result *model.DeliveryResult
+}
+
+type createSigningKeyParams struct {
+	name string
+}
+
+type encryptSecretsParams struct {
+	result *EncryptResult
+}
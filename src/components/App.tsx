@@ -7,8 +7,9 @@ import { ScheduleEditPage } from '../pages/Schedules/ScheduleEditPage';
 import { RunHistoryPage } from '../pages/RunHistory/RunHistoryPage';
 import { SettingsPage } from '../pages/Settings/SettingsPage';
 import { DocumentationPage } from '../pages/Documentation/DocumentationPage';
+import { AuditLogPage } from '../pages/AuditLog/AuditLogPage';
 
-type Page = 'schedules' | 'schedule-new' | 'schedule-edit' | 'run-history' | 'settings' | 'documentation';
+type Page = 'schedules' | 'schedule-new' | 'schedule-edit' | 'run-history' | 'settings' | 'documentation' | 'audit';
 
 export const App: React.FC<AppRootProps> = (props) => {
   const [currentPage, setCurrentPage] = useState<Page>('schedules');
@@ -28,6 +29,8 @@ export const App: React.FC<AppRootProps> = (props) => {
       setCurrentPage('settings');
     } else if (path.includes('documentation')) {
       setCurrentPage('documentation');
+    } else if (path.includes('audit')) {
+      setCurrentPage('audit');
     } else if (path.includes('schedule/new')) {
       setCurrentPage('schedule-new');
     } else if (path.includes('schedule/edit')) {
@@ -66,6 +69,9 @@ export const App: React.FC<AppRootProps> = (props) => {
       case 'documentation':
         url = `${baseUrl}/documentation`;
         break;
+      case 'audit':
+        url = `${baseUrl}/audit`;
+        break;
     }
 
     window.location.href = url;
@@ -85,6 +91,8 @@ export const App: React.FC<AppRootProps> = (props) => {
         return <SettingsPage onNavigate={navigate} />;
       case 'documentation':
         return <DocumentationPage />;
+      case 'audit':
+        return <AuditLogPage />;
       default:
         return <SchedulesPage onNavigate={navigate} />;
     }
@@ -108,11 +116,20 @@ export const App: React.FC<AppRootProps> = (props) => {
           active={currentPage === 'documentation'}
           onChangeTab={() => navigate('documentation')}
         />
-        <Tab
-          label="Settings"
-          active={currentPage === 'settings'}
-          onChangeTab={() => navigate('settings')}
-        />
+        {config.bootData.user.orgRole === 'Admin' && (
+          <Tab
+            label="Audit Log"
+            active={currentPage === 'audit'}
+            onChangeTab={() => navigate('audit')}
+          />
+        )}
+        {config.bootData.user.orgRole === 'Admin' && (
+          <Tab
+            label="Settings"
+            active={currentPage === 'settings'}
+            onChangeTab={() => navigate('settings')}
+          />
+        )}
       </TabsBar>
       {renderPage()}
     </div>
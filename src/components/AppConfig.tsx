@@ -60,6 +60,14 @@ export const AppConfig: React.FC<AppConfigProps> = ({ plugin }) => {
           This plugin uses Grafana's managed service accounts. Authentication is handled automatically - no manual token configuration required.
         </Alert>
       )}
+
+      {enabled && (
+        <Alert title="Secrets Encryption" severity="info" style={{ marginTop: '16px' }}>
+          SMTP passwords, storage credentials and delivery target credentials are encrypted in the plugin database with
+          the key in the <code>GF_PLUGIN_SECRETS_KEY</code> environment variable of the Grafana server. Use a long random
+          value and keep a copy: once secrets are encrypted, the plugin doesn&apos;t start without it.
+        </Alert>
+      )}
     </div>
   );
 };
@@ -0,0 +1,124 @@
+import React from 'react';
+import { Field, Input, Button, Select, Switch } from '@grafana/ui';
+import { css } from '@emotion/css';
+import { GrafanaTheme2 } from '@grafana/data';
+import { useStyles2 } from '@grafana/ui';
+import { Burst, BurstValue, Variable } from '../types/types';
+import { RecipientsEditor } from './RecipientsEditor';
+
+interface BurstEditorProps {
+  value?: Burst;
+  variables: Variable[];
+  onChange: (value?: Burst) => void;
+}
+
+export const BurstEditor: React.FC<BurstEditorProps> = ({ value, variables, onChange }) => {
+  const styles = useStyles2(getStyles);
+
+  // Each dashboard variable once, even when the schedule sets several values for it
+  const variableNames = Array.from(new Set(variables.map((v) => v.name)));
+  const options = variables.find((v) => v.name === value?.variable)?.options || [];
+
+  const updateValue = (index: number, burstValue: BurstValue) => {
+    const values = [...value!.values];
+    values[index] = burstValue;
+    onChange({ ...value!, values });
+  };
+
+  const removeValue = (index: number) => {
+    const values = [...value!.values];
+    values.splice(index, 1);
+    onChange({ ...value!, values });
+  };
+
+  // Adds an entry for every option of the variable that isn't listed yet
+  const addAllOptions = () => {
+    const existing = new Set(value!.values.map((v) => v.value));
+    const added = options
+      .filter((o) => o.value && !o.value.startsWith('$__') && !existing.has(o.value))
+      .map((o) => ({ value: o.value, recipients: { to: [] } }));
+    onChange({ ...value!, values: [...value!.values, ...added] });
+  };
+
+  return (
+    <>
+      <Field
+        label="Burst Reports"
+        description="Render one report per variable value and email each to its own recipients instead of the recipients above"
+      >
+        <Switch
+          value={!!value}
+          onChange={(e) =>
+            onChange(e.currentTarget.checked ? { variable: variableNames[0] || '', values: [] } : undefined)
+          }
+        />
+      </Field>
+      {value && (
+        <>
+          <Field label="Variable" description="Dashboard variable that is set to each value in turn">
+            <Select
+              options={variableNames.map((name) => ({ label: name, value: name }))}
+              value={value.variable}
+              allowCustomValue
+              onChange={(v) => onChange({ ...value, variable: v.value || '' })}
+              width={30}
+            />
+          </Field>
+          {value.values.map((burstValue, index) => (
+            <div key={index} className={styles.value}>
+              <div className={styles.valueHeader}>
+                <Field label="Value">
+                  <Input
+                    width={30}
+                    value={burstValue.value}
+                    onChange={(e) => updateValue(index, { ...burstValue, value: e.currentTarget.value })}
+                  />
+                </Field>
+                <Button size="sm" variant="destructive" icon="trash-alt" onClick={() => removeValue(index)}>
+                  Remove
+                </Button>
+              </div>
+              <RecipientsEditor
+                value={burstValue.recipients}
+                onChange={(recipients) => updateValue(index, { ...burstValue, recipients })}
+              />
+            </div>
+          ))}
+          <div className={styles.actions}>
+            <Button
+              size="sm"
+              variant="secondary"
+              icon="plus"
+              onClick={() => onChange({ ...value, values: [...value.values, { value: '', recipients: { to: [] } }] })}
+            >
+              Add Value
+            </Button>
+            {options.length > 0 && (
+              <Button size="sm" variant="secondary" onClick={addAllOptions}>
+                Add All Options
+              </Button>
+            )}
+          </div>
+        </>
+      )}
+    </>
+  );
+};
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  value: css`
+    border: 1px solid ${theme.colors.border.weak};
+    border-radius: ${theme.shape.radius.default};
+    padding: ${theme.spacing(2)};
+    margin-bottom: ${theme.spacing(2)};
+  `,
+  valueHeader: css`
+    display: flex;
+    justify-content: space-between;
+    align-items: flex-start;
+  `,
+  actions: css`
+    display: flex;
+    gap: ${theme.spacing(1)};
+  `,
+});
@@ -0,0 +1,106 @@
+import React, { useState, useEffect } from 'react';
+import { Field, Input, Select, Switch, TextArea } from '@grafana/ui';
+import { Condition } from '../types/types';
+
+interface ConditionEditorProps {
+  value?: Condition;
+  onChange: (value?: Condition) => void;
+}
+
+const reducerOptions = [
+  { label: 'Last', value: 'last' },
+  { label: 'Min', value: 'min' },
+  { label: 'Max', value: 'max' },
+  { label: 'Mean', value: 'mean' },
+  { label: 'Sum', value: 'sum' },
+  { label: 'Count', value: 'count' },
+];
+
+const operatorOptions = ['>', '>=', '<', '<=', '==', '!='].map((op) => ({ label: op, value: op }));
+
+const newCondition = (): Condition => ({
+  datasource_uid: '',
+  query: { expr: '' },
+  reducer: 'last',
+  operator: '>',
+  threshold: 0,
+});
+
+export const ConditionEditor: React.FC<ConditionEditorProps> = ({ value, onChange }) => {
+  // The query is edited as JSON text and only applied once it parses
+  const [queryText, setQueryText] = useState(JSON.stringify(value?.query || {}, null, 2));
+  const [queryError, setQueryError] = useState('');
+
+  // Refresh the text when a condition is loaded or enabled
+  useEffect(() => {
+    setQueryText(JSON.stringify(value?.query || {}, null, 2));
+    setQueryError('');
+  }, [!!value]);
+
+  const applyQuery = (text: string) => {
+    setQueryText(text);
+    try {
+      const query = JSON.parse(text);
+      if (typeof query !== 'object' || query === null || Array.isArray(query)) {
+        throw new Error('query must be a JSON object');
+      }
+      setQueryError('');
+      onChange({ ...value!, query });
+    } catch (err) {
+      setQueryError(err instanceof Error ? err.message : String(err));
+    }
+  };
+
+  return (
+    <>
+      <Field label="Only Send When" description="Skip the report unless a query result matches the condition">
+        <Switch
+          value={!!value}
+          onChange={(e) => onChange(e.currentTarget.checked ? newCondition() : undefined)}
+        />
+      </Field>
+      {value && (
+        <>
+          <Field label="Data Source UID" description="The data source the condition query runs against">
+            <Input
+              value={value.datasource_uid}
+              onChange={(e) => onChange({ ...value, datasource_uid: e.currentTarget.value })}
+            />
+          </Field>
+          <Field
+            label="Query"
+            description='Data source query model as JSON, e.g. {"expr": "sum(errors_total)"}; dashboard variables are substituted'
+            invalid={!!queryError}
+            error={queryError}
+          >
+            <TextArea rows={4} value={queryText} onChange={(e) => applyQuery(e.currentTarget.value)} />
+          </Field>
+          <Field label="Reducer" description="How each returned series is reduced to a single value">
+            <Select
+              options={reducerOptions}
+              value={value.reducer}
+              onChange={(v) => onChange({ ...value, reducer: v.value as Condition['reducer'] })}
+              width={20}
+            />
+          </Field>
+          <Field label="Threshold" description="The report is sent when any series satisfies the comparison">
+            <div style={{ display: 'flex', gap: '8px' }}>
+              <Select
+                options={operatorOptions}
+                value={value.operator}
+                onChange={(v) => onChange({ ...value, operator: v.value as Condition['operator'] })}
+                width={10}
+              />
+              <Input
+                type="number"
+                width={20}
+                value={value.threshold}
+                onChange={(e) => onChange({ ...value, threshold: parseFloat(e.currentTarget.value) || 0 })}
+              />
+            </div>
+          </Field>
+        </>
+      )}
+    </>
+  );
+};
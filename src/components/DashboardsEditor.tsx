@@ -0,0 +1,119 @@
+import React from 'react';
+import { Field, Input, Button } from '@grafana/ui';
+import { css } from '@emotion/css';
+import { GrafanaTheme2 } from '@grafana/data';
+import { useStyles2 } from '@grafana/ui';
+import { DashboardRef } from '../types/types';
+import { DashboardPicker } from './DashboardPicker';
+
+interface DashboardsEditorProps {
+  value: DashboardRef[];
+  onChange: (value: DashboardRef[]) => void;
+}
+
+// Parses a comma-separated list of panel IDs, ignoring anything that isn't a number
+const parsePanelIds = (text: string): number[] =>
+  text
+    .split(',')
+    .map((id) => parseInt(id.trim(), 10))
+    .filter((id) => !isNaN(id));
+
+export const DashboardsEditor: React.FC<DashboardsEditorProps> = ({ value, onChange }) => {
+  const styles = useStyles2(getStyles);
+
+  const update = (index: number, dashboard: DashboardRef) => {
+    const updated = [...value];
+    updated[index] = dashboard;
+    onChange(updated);
+  };
+
+  const remove = (index: number) => {
+    const updated = [...value];
+    updated.splice(index, 1);
+    onChange(updated);
+  };
+
+  const move = (index: number, offset: number) => {
+    const updated = [...value];
+    const [dashboard] = updated.splice(index, 1);
+    updated.splice(index + offset, 0, dashboard);
+    onChange(updated);
+  };
+
+  return (
+    <div>
+      {value.map((dashboard, index) => (
+        <div key={index} className={styles.dashboard}>
+          <div className={styles.dashboardHeader}>
+            <strong>
+              {index + 1}. {dashboard.title || dashboard.uid || 'New dashboard'}
+            </strong>
+            <div>
+              <Button size="sm" variant="secondary" icon="arrow-up" disabled={index === 0} onClick={() => move(index, -1)} />
+              <Button
+                size="sm"
+                variant="secondary"
+                icon="arrow-down"
+                disabled={index === value.length - 1}
+                onClick={() => move(index, 1)}
+                style={{ marginLeft: '4px' }}
+              />
+              <Button
+                size="sm"
+                variant="destructive"
+                icon="trash-alt"
+                onClick={() => remove(index)}
+                style={{ marginLeft: '4px' }}
+              />
+            </div>
+          </div>
+          <Field label="Dashboard">
+            <DashboardPicker value={dashboard.uid} onChange={(uid, title) => update(index, { ...dashboard, uid, title })} />
+          </Field>
+          <Field label="Panel IDs" description="Comma-separated; leave empty for the whole dashboard">
+            <Input
+              value={(dashboard.panel_ids || []).join(', ')}
+              onChange={(e) => update(index, { ...dashboard, panel_ids: parsePanelIds(e.currentTarget.value) })}
+              placeholder="1, 4, 7"
+            />
+          </Field>
+          <Field label="Time Range" description="Leave empty to use the schedule's time range">
+            <div className={styles.range}>
+              <Input
+                value={dashboard.range_from || ''}
+                onChange={(e) => update(index, { ...dashboard, range_from: e.currentTarget.value })}
+                placeholder="From"
+              />
+              <Input
+                value={dashboard.range_to || ''}
+                onChange={(e) => update(index, { ...dashboard, range_to: e.currentTarget.value })}
+                placeholder="To"
+              />
+            </div>
+          </Field>
+        </div>
+      ))}
+      <Button size="sm" variant="secondary" icon="plus" onClick={() => onChange([...value, { uid: '' }])}>
+        Add Dashboard
+      </Button>
+    </div>
+  );
+};
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  dashboard: css`
+    border: 1px solid ${theme.colors.border.weak};
+    border-radius: ${theme.shape.radius.default};
+    padding: ${theme.spacing(2)};
+    margin-bottom: ${theme.spacing(2)};
+  `,
+  dashboardHeader: css`
+    display: flex;
+    justify-content: space-between;
+    margin-bottom: ${theme.spacing(2)};
+  `,
+  range: css`
+    display: flex;
+    gap: ${theme.spacing(1)};
+  `,
+});
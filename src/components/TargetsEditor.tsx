@@ -0,0 +1,268 @@
+import React from 'react';
+import { Field, Input, Button, SecretInput, Select, Switch, TextArea } from '@grafana/ui';
+import { css } from '@emotion/css';
+import { GrafanaTheme2 } from '@grafana/data';
+import { useStyles2 } from '@grafana/ui';
+import { DeliveryTarget, SFTPTarget, SECRET_UNCHANGED } from '../types/types';
+
+interface TargetsEditorProps {
+  value: DeliveryTarget[];
+  onChange: (value: DeliveryTarget[]) => void;
+}
+
+const targetTypeOptions = [
+  { label: 'Slack', value: 'slack' },
+  { label: 'Microsoft Teams', value: 'teams' },
+  { label: 'Webhook', value: 'webhook' },
+  { label: 'SFTP', value: 'sftp' },
+  { label: 'Network Share', value: 'share' },
+];
+
+const pathDescription =
+  'Variables: {{schedule.name}}, {{schedule.id}}, {{run.id}}, {{run.timestamp}}, {{run.date}}, {{file.name}}, {{file.ext}}. ' +
+  'A trailing / keeps the report file name.';
+
+const newTarget = (type: DeliveryTarget['type']): DeliveryTarget => {
+  switch (type) {
+    case 'teams':
+      return { type: 'teams', teams: { webhook_url: '' } };
+    case 'webhook':
+      return { type: 'webhook', webhook: { url: '' } };
+    case 'sftp':
+      return { type: 'sftp', sftp: { host: '', username: '' }, retries: 2 };
+    case 'share':
+      return { type: 'share', share: { path: '' }, retries: 2 };
+    default:
+      return { type: 'slack', slack: { token: '', channel: '' } };
+  }
+};
+
+export const TargetsEditor: React.FC<TargetsEditorProps> = ({ value, onChange }) => {
+  const styles = useStyles2(getStyles);
+
+  const updateTarget = (index: number, target: DeliveryTarget) => {
+    const updated = [...value];
+    updated[index] = target;
+    onChange(updated);
+  };
+
+  const removeTarget = (index: number) => {
+    const updated = [...value];
+    updated.splice(index, 1);
+    onChange(updated);
+  };
+
+  const updateSFTP = (index: number, target: DeliveryTarget, changes: Partial<SFTPTarget>) => {
+    updateTarget(index, { ...target, sftp: { host: '', username: '', ...target.sftp, ...changes } });
+  };
+
+  const renderSettings = (target: DeliveryTarget, index: number) => {
+    switch (target.type) {
+      case 'slack':
+        return (
+          <>
+            <Field label="Bot Token" description="Slack bot token (xoxb-...) with the files:write scope">
+              <SecretInput
+                isConfigured={target.slack?.token === SECRET_UNCHANGED}
+                value={target.slack?.token || ''}
+                onChange={(e) =>
+                  updateTarget(index, { ...target, slack: { channel: '', ...target.slack, token: e.currentTarget.value } })
+                }
+                onReset={() => updateTarget(index, { ...target, slack: { channel: '', ...target.slack, token: '' } })}
+              />
+            </Field>
+            <Field label="Channel ID" description="e.g. C0123456789; invite the bot to the channel first">
+              <Input
+                value={target.slack?.channel || ''}
+                onChange={(e) =>
+                  updateTarget(index, { ...target, slack: { token: '', ...target.slack, channel: e.currentTarget.value } })
+                }
+              />
+            </Field>
+          </>
+        );
+      case 'teams':
+        return (
+          <Field label="Webhook URL" description="Incoming webhook URL of the Teams channel; the card links to the report">
+            <SecretInput
+              isConfigured={target.teams?.webhook_url === SECRET_UNCHANGED}
+              value={target.teams?.webhook_url || ''}
+              onChange={(e) => updateTarget(index, { ...target, teams: { webhook_url: e.currentTarget.value } })}
+              onReset={() => updateTarget(index, { ...target, teams: { webhook_url: '' } })}
+            />
+          </Field>
+        );
+      case 'webhook':
+        return (
+          <>
+            <Field label="URL" description="Receives a JSON POST describing the report">
+              <Input
+                value={target.webhook?.url || ''}
+                onChange={(e) =>
+                  updateTarget(index, { ...target, webhook: { ...target.webhook, url: e.currentTarget.value } })
+                }
+              />
+            </Field>
+            <Field
+              label="Signing Secret"
+              description="Optional; signs each request with HMAC-SHA256 in the X-Report-Signature header"
+            >
+              <SecretInput
+                isConfigured={target.webhook?.secret === SECRET_UNCHANGED}
+                value={target.webhook?.secret || ''}
+                onChange={(e) =>
+                  updateTarget(index, { ...target, webhook: { url: '', ...target.webhook, secret: e.currentTarget.value } })
+                }
+                onReset={() => updateTarget(index, { ...target, webhook: { url: '', ...target.webhook, secret: '' } })}
+              />
+            </Field>
+            <Field label="Include Content" description="Embed the report base64-encoded in the payload">
+              <Switch
+                value={target.webhook?.include_content || false}
+                onChange={(e) =>
+                  updateTarget(index, {
+                    ...target,
+                    webhook: { url: '', ...target.webhook, include_content: e.currentTarget.checked },
+                  })
+                }
+              />
+            </Field>
+          </>
+        );
+      case 'sftp':
+        return (
+          <>
+            <Field label="Host">
+              <Input value={target.sftp?.host || ''} onChange={(e) => updateSFTP(index, target, { host: e.currentTarget.value })} />
+            </Field>
+            <Field label="Port" description="Defaults to 22">
+              <Input
+                type="number"
+                width={12}
+                value={target.sftp?.port || ''}
+                onChange={(e) => updateSFTP(index, target, { port: parseInt(e.currentTarget.value, 10) || undefined })}
+              />
+            </Field>
+            <Field label="Username">
+              <Input
+                value={target.sftp?.username || ''}
+                onChange={(e) => updateSFTP(index, target, { username: e.currentTarget.value })}
+              />
+            </Field>
+            <Field label="Password" description="Leave empty when using a private key">
+              <SecretInput
+                isConfigured={target.sftp?.password === SECRET_UNCHANGED}
+                value={target.sftp?.password || ''}
+                onChange={(e) => updateSFTP(index, target, { password: e.currentTarget.value })}
+                onReset={() => updateSFTP(index, target, { password: '' })}
+              />
+            </Field>
+            <Field label="Private Key" description="PEM or OpenSSH private key; used before the password">
+              {target.sftp?.private_key === SECRET_UNCHANGED ? (
+                <SecretInput
+                  isConfigured={true}
+                  value=""
+                  onReset={() => updateSFTP(index, target, { private_key: '', passphrase: '' })}
+                />
+              ) : (
+                <TextArea
+                  rows={4}
+                  value={target.sftp?.private_key || ''}
+                  onChange={(e) => updateSFTP(index, target, { private_key: e.currentTarget.value })}
+                />
+              )}
+            </Field>
+            {target.sftp?.private_key && (
+              <Field label="Key Passphrase">
+                <SecretInput
+                  isConfigured={target.sftp?.passphrase === SECRET_UNCHANGED}
+                  value={target.sftp?.passphrase || ''}
+                  onChange={(e) => updateSFTP(index, target, { passphrase: e.currentTarget.value })}
+                  onReset={() => updateSFTP(index, target, { passphrase: '' })}
+                />
+              </Field>
+            )}
+            <Field
+              label="Host Key"
+              description="Server public key used to verify the server (e.g. ssh-ed25519 AAAA...; see ssh-keyscan)"
+              required
+            >
+              <Input
+                value={target.sftp?.host_key || ''}
+                onChange={(e) => updateSFTP(index, target, { host_key: e.currentTarget.value })}
+              />
+            </Field>
+            <Field label="Remote Path" description={pathDescription}>
+              <Input
+                placeholder="{{schedule.name}}/{{run.timestamp}}{{file.ext}}"
+                value={target.sftp?.path || ''}
+                onChange={(e) => updateSFTP(index, target, { path: e.currentTarget.value })}
+              />
+            </Field>
+          </>
+        );
+      case 'share':
+        return (
+          <Field
+            label="Path"
+            description={`Absolute path on a share mounted on the Grafana server, inside a directory allowed by GF_PLUGIN_SHARE_PATHS. ${pathDescription}`}
+          >
+            <Input
+              placeholder="/mnt/reports/{{schedule.name}}/{{run.timestamp}}{{file.ext}}"
+              value={target.share?.path || ''}
+              onChange={(e) => updateTarget(index, { ...target, share: { path: e.currentTarget.value } })}
+            />
+          </Field>
+        );
+      default:
+        return null;
+    }
+  };
+
+  return (
+    <div>
+      {value.map((target, index) => (
+        <div key={index} className={styles.target}>
+          <div className={styles.targetHeader}>
+            <Select
+              width={24}
+              options={targetTypeOptions}
+              value={target.type}
+              onChange={(v) => updateTarget(index, newTarget(v.value as DeliveryTarget['type']))}
+            />
+            {/* @ts-ignore */}
+            <Button size="sm" variant="secondary" icon="trash-alt" onClick={() => removeTarget(index)} />
+          </div>
+          {renderSettings(target, index)}
+          <Field label="Retries" description="Times a failed delivery is retried, with increasing delays (0-5)">
+            <Input
+              type="number"
+              width={12}
+              min={0}
+              max={5}
+              value={target.retries ?? 0}
+              onChange={(e) => updateTarget(index, { ...target, retries: parseInt(e.currentTarget.value, 10) || 0 })}
+            />
+          </Field>
+        </div>
+      ))}
+      <Button size="sm" variant="secondary" icon="plus" onClick={() => onChange([...value, newTarget('slack')])}>
+        Add Target
+      </Button>
+    </div>
+  );
+};
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  target: css`
+    border: 1px solid ${theme.colors.border.weak};
+    border-radius: ${theme.shape.radius.default};
+    padding: ${theme.spacing(2)};
+    margin-bottom: ${theme.spacing(2)};
+  `,
+  targetHeader: css`
+    display: flex;
+    justify-content: space-between;
+    margin-bottom: ${theme.spacing(2)};
+  `,
+});
@@ -0,0 +1,185 @@
+import React, { useState, useEffect } from 'react';
+import { css } from '@emotion/css';
+import { GrafanaTheme2, SelectableValue } from '@grafana/data';
+import { useStyles2, Button, Input, Select, LoadingPlaceholder } from '@grafana/ui';
+import { AuditEvent, AuditEventList } from '../../types/types';
+import { getBackendSrv } from '@grafana/runtime';
+
+const PAGE_SIZE = 50;
+
+const actionOptions: Array<SelectableValue<string>> = [
+  { label: 'All actions', value: '' },
+  { label: 'Schedule created', value: 'schedule.create' },
+  { label: 'Schedule updated', value: 'schedule.update' },
+  { label: 'Schedule deleted', value: 'schedule.delete' },
+  { label: 'Schedule run manually', value: 'schedule.run' },
+  { label: 'Settings updated', value: 'settings.update' },
+  { label: 'Report downloaded', value: 'run.download' },
+];
+
+const formatValue = (value: unknown) => (value === null || value === undefined ? '—' : JSON.stringify(value));
+
+export const AuditLogPage: React.FC = () => {
+  const styles = useStyles2(getStyles);
+  const [events, setEvents] = useState<AuditEvent[]>([]);
+  const [total, setTotal] = useState(0);
+  const [offset, setOffset] = useState(0);
+  const [user, setUser] = useState('');
+  const [action, setAction] = useState('');
+  const [loading, setLoading] = useState(true);
+
+  useEffect(() => {
+    loadEvents();
+  }, [offset, action]);
+
+  const loadEvents = async () => {
+    try {
+      const params = new URLSearchParams({ limit: String(PAGE_SIZE), offset: String(offset) });
+      if (user) {
+        params.set('user', user);
+      }
+      if (action) {
+        params.set('action', action);
+      }
+      const response: AuditEventList = await getBackendSrv().get(
+        `/api/plugins/scheduled-reports-app/resources/api/audit?${params.toString()}`
+      );
+      setEvents(response.events || []);
+      setTotal(response.total || 0);
+    } catch (error) {
+      console.error('Failed to load audit log:', error);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  // Filters apply from the first page
+  const applyFilters = () => {
+    if (offset === 0) {
+      loadEvents();
+    } else {
+      setOffset(0);
+    }
+  };
+
+  if (loading) {
+    return <LoadingPlaceholder text="Loading audit log..." />;
+  }
+
+  return (
+    <div className={styles.container}>
+      <div className={styles.header}>
+        <h2>Audit Log</h2>
+      </div>
+      <div className={styles.filters}>
+        <Input
+          width={30}
+          placeholder="User login"
+          value={user}
+          onChange={(e) => setUser(e.currentTarget.value)}
+          onKeyDown={(e) => e.key === 'Enter' && applyFilters()}
+        />
+        <Select
+          width={30}
+          options={actionOptions}
+          value={action}
+          onChange={(option) => {
+            setAction(option.value || '');
+            setOffset(0);
+          }}
+        />
+        {/* @ts-ignore */}
+        <Button variant="secondary" icon="search" onClick={applyFilters}>
+          Filter
+        </Button>
+      </div>
+      {events.length === 0 ? (
+        <p>No audit events</p>
+      ) : (
+        <table style={{ width: '100%', borderCollapse: 'collapse' }}>
+          <thead>
+            <tr>
+              <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Time</th>
+              <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>User</th>
+              <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Action</th>
+              <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Resource</th>
+              <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Changes</th>
+            </tr>
+          </thead>
+          <tbody>
+            {events.map((event) => (
+              <tr key={event.id}>
+                <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>
+                  {new Date(event.created_at).toLocaleString()}
+                </td>
+                <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>{event.user_login}</td>
+                <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>{event.action}</td>
+                <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>
+                  {event.resource_type}
+                  {event.resource_id ? ` #${event.resource_id}` : ''}
+                </td>
+                <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>
+                  {Object.entries(event.diff || {}).map(([path, change]) => (
+                    <div key={path} className={styles.change}>
+                      <code>{path}</code>: {formatValue(change.old)} → {formatValue(change.new)}
+                    </div>
+                  ))}
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      )}
+      <div className={styles.pagination}>
+        {/* @ts-ignore */}
+        <Button
+          variant="secondary"
+          icon="angle-left"
+          disabled={offset === 0}
+          onClick={() => setOffset(Math.max(0, offset - PAGE_SIZE))}
+        >
+          Previous
+        </Button>
+        <span>
+          {total === 0 ? 0 : offset + 1}–{Math.min(offset + PAGE_SIZE, total)} of {total}
+        </span>
+        {/* @ts-ignore */}
+        <Button
+          variant="secondary"
+          icon="angle-right"
+          disabled={offset + PAGE_SIZE >= total}
+          onClick={() => setOffset(offset + PAGE_SIZE)}
+        >
+          Next
+        </Button>
+      </div>
+    </div>
+  );
+};
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  container: css`
+    padding: ${theme.spacing(2)};
+  `,
+  header: css`
+    display: flex;
+    justify-content: space-between;
+    align-items: center;
+    margin-bottom: ${theme.spacing(2)};
+  `,
+  filters: css`
+    display: flex;
+    gap: ${theme.spacing(1)};
+    margin-bottom: ${theme.spacing(2)};
+  `,
+  change: css`
+    font-size: ${theme.typography.bodySmall.fontSize};
+    word-break: break-all;
+  `,
+  pagination: css`
+    display: flex;
+    align-items: center;
+    gap: ${theme.spacing(2)};
+    margin-top: ${theme.spacing(2)};
+  `,
+});
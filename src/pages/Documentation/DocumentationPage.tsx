@@ -63,11 +63,59 @@ export const DocumentationPage: React.FC = () => {
           <li><strong>Enabled:</strong> Enable or disable the schedule</li>
         </ul>
 
+        <h3>Combined Reports</h3>
+        <p>
+          Turn on <strong>Combine Dashboards</strong> to render an ordered list of dashboards into a single PDF. Each
+          dashboard can select its own panels and time range (empty uses the schedule&apos;s time range); the
+          schedule&apos;s variables apply to all of them. The report starts with a generated cover page and a table of
+          contents whose entries link to the first page of each dashboard, which also appear as PDF bookmarks. Up to 20
+          dashboards can be combined, and combined reports are always PDFs.
+        </p>
+
+        <h3>Theme</h3>
+        <p>
+          Dashboards are rendered in Grafana&apos;s light theme unless the schedule selects <strong>Dark</strong> or
+          another Grafana theme by its ID (e.g. <code>tron</code>). To save ink, PDFs of dark reports are printed
+          without page backgrounds; a report template can set <code>print_background</code> to print them anyway, or
+          to leave them out for any other theme. This applies to every PDF layout, including selected panels and the
+          paginated layout; image reports always keep the background.
+        </p>
+
+        <h3>Cover Page and Table of Contents</h3>
+        <p>
+          Report templates can add front matter in front of a single-dashboard PDF report with{' '}
+          <code>cover_page</code> and <code>table_of_contents</code>:
+        </p>
+        <ul>
+          <li>
+            <strong>Cover page:</strong> the schedule name, the dashboard title, the time range resolved to absolute
+            dates in the schedule&apos;s timezone (e.g. <code>now-7d/d</code> becomes the date seven days ago at
+            00:00), the time the report was generated and the template logo
+          </li>
+          <li>
+            <strong>Table of contents:</strong> the dashboard&apos;s titled rows and panels, each linking to the page
+            it is printed on and also available as nested PDF bookmarks
+          </li>
+        </ul>
+        <p>Combined reports always include both, listing the rows and panels below each dashboard.</p>
+
         <h3>Time Range</h3>
         <ul>
           <li><strong>From:</strong> Start of the time range (e.g., "now-24h", "now-7d", "2024-01-01")</li>
           <li><strong>To:</strong> End of the time range (e.g., "now")</li>
+          <li>
+            <strong>Presets:</strong> last 24 hours, 7 days or 30 days, and the previous day, week (Monday to Sunday),
+            month, quarter or year
+          </li>
         </ul>
+        <p>
+          Relative ranges are resolved in the schedule&apos;s timezone at the time the run was scheduled, not when it
+          starts, so a weekly report due Monday at 00:00 covers the previous Monday to Sunday even if it runs late.
+          Rounding with <code>/unit</code> goes to the start of the unit in From and to its end in To; besides
+          Grafana&apos;s units, <code>Q</code> selects calendar quarters (e.g. <code>now-1Q/Q</code>). Manual runs
+          resolve the range when they start. The resolved absolute range is used for every dashboard of the report and
+          recorded on the run in the run history.
+        </p>
 
         <h3>Schedule Intervals</h3>
         <ul>
@@ -158,9 +206,66 @@ export const DocumentationPage: React.FC = () => {
         <ul>
           <li><code>{'{{schedule.name}}'}</code> - Name of the schedule</li>
           <li><code>{'{{dashboard.title}}'}</code> - Dashboard title</li>
-          <li><code>{'{{timerange}}'}</code> - Time range used for the report</li>
+          <li><code>{'{{timerange}}'}</code> - Resolved time range of the report, e.g. &quot;2024-03-04 00:00 to 2024-03-10 23:59 UTC&quot;</li>
+          <li><code>{'{{timerange.from}}'}</code> - Start of the resolved range (RFC 3339, in the schedule&apos;s timezone)</li>
+          <li><code>{'{{timerange.to}}'}</code> - End of the resolved range</li>
           <li><code>{'{{run.started_at}}'}</code> - When the report generation started</li>
+          <li><code>{'{{burst.value}}'}</code> - The variable value of a burst report</li>
         </ul>
+
+        <h3>Burst Reports</h3>
+        <p>
+          Bursting sends each audience its own version of the dashboard. Pick a dashboard variable (e.g.{' '}
+          <code>region</code>) and list its values with the recipients of each. Every run then renders the dashboard once
+          per value, with the variable set to that value, and emails the report only to that value&apos;s recipients; the
+          schedule&apos;s own recipients and other destinations are not used. Each report appears in Run History as its own
+          run under the schedule&apos;s run, which fails if any of the reports failed. A schedule can burst to at most 100
+          values, and conditions are evaluated separately for each value.
+        </p>
+
+        <h3>Other Destinations</h3>
+        <ul>
+          <li><strong>Slack:</strong> Uploads the report file to a channel with the subject and body as the comment</li>
+          <li><strong>Microsoft Teams:</strong> Posts a card with the subject, body and a download link to an incoming webhook</li>
+          <li>
+            <strong>Webhook:</strong> POSTs a JSON payload with the schedule, run and file (name, size, SHA-256 checksum and
+            download URL; optionally the base64 content)
+          </li>
+          <li>
+            <strong>SFTP:</strong> Uploads the report file with password or private key authentication; the server's host key is
+            required to verify the server
+          </li>
+          <li>
+            <strong>Network Share:</strong> Copies the report file to a share mounted on the Grafana server. Allowed
+            directories are set with the <code>GF_PLUGIN_SHARE_PATHS</code> environment variable (separated by{' '}
+            <code>:</code>)
+          </li>
+        </ul>
+        <p>
+          File paths support <code>{'{{schedule.name}}'}</code>, <code>{'{{run.timestamp}}'}</code> (UTC,{' '}
+          <code>20060102-150405</code>), <code>{'{{run.date}}'}</code>, <code>{'{{file.name}}'}</code> and{' '}
+          <code>{'{{file.ext}}'}</code>. Each destination can retry failed deliveries; the attempts are shown in Run History.
+        </p>
+        <p>
+          When a signing secret is set, webhook requests carry <code>X-Report-Timestamp</code> (Unix seconds) and{' '}
+          <code>X-Report-Signature</code>: <code>sha256=</code> followed by the hex HMAC-SHA256 of{' '}
+          <code>{'<timestamp>.<body>'}</code> keyed with the secret. Recompute it over the raw request body and reject stale
+          timestamps to guard against replays.
+        </p>
+        <p>
+          The download links in Teams cards and webhook payloads are signed, so every member of the organization signed in
+          to Grafana can open them, not only the schedule&apos;s recipients. They expire after 7 days; the report stays
+          available in Run History to those managing the schedule.
+        </p>
+
+        <h3>Conditions</h3>
+        <p>
+          A condition turns a schedule into an exception report: before rendering, its query runs against the chosen data
+          source over the schedule&apos;s time range, with dashboard variables substituted. Each returned series is reduced
+          to one value (last, min, max, mean, sum or count) and compared with the threshold. The report is sent when any
+          series matches; otherwise the run is recorded as <strong>skipped</strong> with the values that were checked.
+          Queries that return no numeric data never match.
+        </p>
       </section>
 
       <section className={styles.section}>
@@ -181,11 +286,15 @@ export const DocumentationPage: React.FC = () => {
         </p>
         <ul>
           <li>Execution time and duration</li>
-          <li>Status (completed, failed, running)</li>
+          <li>Status (completed, failed, running, or skipped when a condition was not met)</li>
           <li>Number of pages rendered</li>
           <li>File size</li>
           <li>Error messages (if failed)</li>
           <li>Download button for successful reports</li>
+          <li>
+            Delivery status of each destination, with a <strong>Resend Failed</strong> button that sends the stored report
+            to destinations that failed, using the schedule&apos;s current destination settings
+          </li>
         </ul>
       </section>
 
@@ -224,8 +333,38 @@ export const DocumentationPage: React.FC = () => {
           <li><strong>Max Recipients:</strong> Maximum number of email recipients per schedule</li>
           <li><strong>Max Attachment Size:</strong> Maximum report file size in MB</li>
           <li><strong>Max Concurrent Renders:</strong> Number of reports that can render simultaneously</li>
-          <li><strong>Retention Days:</strong> How long to keep report artifacts</li>
+          <li><strong>Retention Days:</strong> How long to keep report artifacts (0 keeps them forever)</li>
+          <li><strong>Keep Last Runs:</strong> Recent runs per schedule whose artifacts survive retention</li>
         </ul>
+
+        <h3>Secrets Encryption</h3>
+        <p>
+          The SMTP password, the S3 secret access key and the credentials of delivery targets (Slack token, Teams
+          webhook URL, webhook signing secret, SFTP password, private key and passphrase) are encrypted with AES-256-GCM
+          in the plugin database. The key is the <code>GF_PLUGIN_SECRETS_KEY</code> environment variable of the
+          Grafana server, read when the plugin starts. Secrets saved before a key was configured are encrypted when the
+          plugin starts with the key; without one they are stored as plaintext. Once any secret is encrypted, the plugin
+          refuses to start without the key, since scheduled reports couldn&apos;t be delivered.
+        </p>
+        <p>
+          Secrets are write-only: the Settings page and the schedule editor show them as configured without revealing
+          them, and saving the form keeps them unless they are reset or replaced.
+        </p>
+        <p>To rotate the key, run the plugin binary with the new and current keys, then restart Grafana with the new key:</p>
+        <p>
+          <code>
+            GF_PLUGIN_SECRETS_KEY=new-key GF_PLUGIN_SECRETS_PREVIOUS_KEY=current-key gpx_reporting rotate-secrets-key
+          </code>
+        </p>
+        <p>
+          Set <code>GF_PLUGIN_APP_DATA_PATH</code> as for Grafana when the plugin&apos;s data is not in the{' '}
+          <code>data</code> directory next to the binary.
+        </p>
+        <p>
+          Alternatively, set <code>GF_PLUGIN_SECRETS_PREVIOUS_KEY</code> alongside the new key and restart Grafana; the
+          secrets are re-encrypted as the plugin starts. Secrets can&apos;t be recovered without the key, so keep a copy
+          of it.
+        </p>
       </section>
 
       <section className={styles.section}>
@@ -233,7 +372,10 @@ export const DocumentationPage: React.FC = () => {
 
         <h3>Reports Not Being Generated</h3>
         <ul>
-          <li>Check that the schedule is enabled</li>
+          <li>
+            Check that the schedule is enabled. Schedules are disabled automatically when their owner can no longer view
+            one of their dashboards; the last run's error names the dashboard
+          </li>
           <li>Verify the "Next Run" time is in the future</li>
           <li>Check the Run History for error messages</li>
           <li>Verify the service account token is configured</li>
@@ -312,7 +454,7 @@ export const DocumentationPage: React.FC = () => {
         <h3>Data Storage</h3>
         <ul>
           <li><strong>Database:</strong> /var/lib/grafana/plugin-data/reporting.db</li>
-          <li><strong>Artifacts:</strong> /var/lib/grafana/plugin-data/artifacts/org_[id]/</li>
+          <li><strong>Artifacts:</strong> In the database by default; per organization, the Artifact Storage settings can store them in /var/lib/grafana/plugin-data/artifacts/org_[id]/ or an S3-compatible bucket</li>
           <li><strong>Multi-tenancy:</strong> All data scoped by organization ID</li>
           <li><strong>Retention:</strong> Artifacts auto-deleted based on configured retention days</li>
         </ul>
@@ -367,7 +509,20 @@ export const DocumentationPage: React.FC = () => {
               <tr>
                 <td>GET</td>
                 <td><code>/api/runs/:id/artifact</code></td>
-                <td>Download PDF artifact</td>
+                <td>Download report artifact (PDF, PNG, JPEG or ZIP)</td>
+              </tr>
+              <tr>
+                <td>GET</td>
+                <td><code>/api/runs/:id/deliveries</code></td>
+                <td>Get the delivery status of each destination of a run</td>
+              </tr>
+              <tr>
+                <td>POST</td>
+                <td><code>/api/runs/:id/redeliver</code></td>
+                <td>
+                  Resend the stored report to failed destinations, or to those in <code>{'{"delivery_ids": [...]}'}</code>,
+                  without rendering again. Each destination is tried once, without its retries
+                </td>
               </tr>
               <tr>
                 <td>GET/POST</td>
@@ -389,6 +544,15 @@ export const DocumentationPage: React.FC = () => {
                 <td><code>/api/service-account/status</code></td>
                 <td>Check service account status</td>
               </tr>
+              <tr>
+                <td>GET</td>
+                <td><code>/api/audit</code></td>
+                <td>
+                  List audit events, newest first. Filter with <code>user</code>, <code>action</code>,{' '}
+                  <code>resource_type</code>, <code>resource_id</code>, <code>from</code> and <code>to</code> (RFC 3339);
+                  page with <code>limit</code> (default 50, at most 500) and <code>offset</code>
+                </td>
+              </tr>
             </tbody>
           </table>
         </div>
@@ -397,11 +561,38 @@ export const DocumentationPage: React.FC = () => {
         <h3>Security</h3>
         <ul>
           <li>Service account tokens are stored securely in Grafana's encrypted settings</li>
-          <li>All schedules are scoped by organization ID</li>
-          <li>Users must have Editor role to create schedules</li>
-          <li>Admin role required to modify plugin settings</li>
+          <li>SMTP, storage and delivery target credentials are encrypted at rest and never returned by the API</li>
+          <li>
+            All schedules are scoped by organization ID. The organization and user are taken from the request context
+            Grafana attaches to every API call, never from request headers, and requests without them are rejected
+          </li>
+          <li>
+            Viewers can list schedules, and see the runs and download the reports they are a recipient of
+          </li>
+          <li>
+            Editors can also create schedules and templates, run, edit, delete and download the reports of the schedules
+            they own, and edit and delete the templates they created
+          </li>
+          <li>
+            Admins manage all schedules, templates and the plugin settings. Schedules created before owners were
+            identified by login stay with the user who created them, and templates created before owners were recorded
+            can be managed by all editors
+          </li>
+          <li>
+            Reports are rendered with the plugin's service account, so a schedule can only be saved when its owner can
+            view all of its dashboards. Before every run the owner's access is checked again: if the owner lost it, or a
+            dashboard was deleted, the run fails and the schedule is disabled
+          </li>
           <li>Email domain whitelisting available for recipient restrictions</li>
         </ul>
+
+        <h3>Audit Log</h3>
+        <p>
+          Every schedule creation, update and deletion, settings change, manual run and report download is recorded with
+          the user, organization and time. Changes carry a diff of the fields that changed with their old and new values;
+          SMTP passwords and S3 secret keys only show as <code>[redacted]</code>. Admins can browse the log on the{' '}
+          <strong>Audit Log</strong> tab, filtered by user or action, or query it through <code>/api/audit</code>.
+        </p>
       </section>
 
       <section className={styles.section}>
@@ -9,6 +9,27 @@ interface RunWithSchedule extends Run {
   schedule_name?: string;
 }
 
+// Places the per-value reports of a bursting run right after it, in the order they were rendered
+const groupBursts = <T extends Run>(runs: T[]): T[] => {
+  const ids = new Set(runs.map((run) => run.id));
+  const children = new Map<number, T[]>();
+  runs.forEach((run) => {
+    if (run.parent_run_id && ids.has(run.parent_run_id)) {
+      children.set(run.parent_run_id, [...(children.get(run.parent_run_id) || []), run]);
+    }
+  });
+
+  const grouped: T[] = [];
+  runs.forEach((run) => {
+    if (run.parent_run_id && ids.has(run.parent_run_id)) {
+      return;
+    }
+    grouped.push(run);
+    grouped.push(...(children.get(run.id) || []).sort((a, b) => a.id - b.id));
+  });
+  return grouped;
+};
+
 interface RunHistoryPageProps {
   onNavigate: (page: string) => void;
   scheduleId: number | null | undefined;
@@ -18,6 +39,7 @@ export const RunHistoryPage: React.FC<RunHistoryPageProps> = ({ onNavigate, sche
   const styles = useStyles2(getStyles);
   const [runs, setRuns] = useState<RunWithSchedule[]>([]);
   const [loading, setLoading] = useState(true);
+  const [resending, setResending] = useState<number | null>(null);
 
   useEffect(() => {
     loadRuns();
@@ -39,7 +61,7 @@ export const RunHistoryPage: React.FC<RunHistoryPageProps> = ({ onNavigate, sche
         response = await getBackendSrv().get(
           `/api/plugins/scheduled-reports-app/resources/api/schedules/${scheduleId}/runs`
         );
-        setRuns(response.runs || []);
+        setRuns(groupBursts(response.runs || []));
       } else {
         // Load all runs from all schedules
         const schedulesResponse = await getBackendSrv().get('/api/plugins/scheduled-reports-app/resources/api/schedules');
@@ -64,7 +86,7 @@ export const RunHistoryPage: React.FC<RunHistoryPageProps> = ({ onNavigate, sche
         }
         // Sort by started_at descending (most recent first)
         allRuns.sort((a, b) => new Date(b.started_at).getTime() - new Date(a.started_at).getTime());
-        setRuns(allRuns);
+        setRuns(groupBursts(allRuns));
       }
     } catch (error) {
       console.error('Failed to load runs:', error);
@@ -78,6 +100,19 @@ export const RunHistoryPage: React.FC<RunHistoryPageProps> = ({ onNavigate, sche
     window.open(`${appSubUrl}/api/plugins/scheduled-reports-app/resources/api/runs/${runId}/artifact`, '_blank');
   };
 
+  // Resends the stored report to the run's failed destinations without rendering again
+  const redeliver = async (runId: number) => {
+    setResending(runId);
+    try {
+      await getBackendSrv().post(`/api/plugins/scheduled-reports-app/resources/api/runs/${runId}/redeliver`, {});
+    } catch (error) {
+      console.error('Failed to redeliver run:', error);
+    } finally {
+      setResending(null);
+      loadRuns();
+    }
+  };
+
   if (loading) {
     return <LoadingPlaceholder text="Loading run history..." />;
   }
@@ -110,7 +145,7 @@ export const RunHistoryPage: React.FC<RunHistoryPageProps> = ({ onNavigate, sche
               )}
               <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Started</th>
               <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Status</th>
-              <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Email</th>
+              <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Delivery</th>
               <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Duration</th>
               <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Pages</th>
               <th style={{ textAlign: 'left', padding: '8px', borderBottom: '2px solid #ddd' }}>Size</th>
@@ -126,6 +161,8 @@ export const RunHistoryPage: React.FC<RunHistoryPageProps> = ({ onNavigate, sche
                   ? styles.statusSuccess
                   : status === 'failed'
                   ? styles.statusError
+                  : status === 'skipped'
+                  ? styles.statusSkipped
                   : styles.statusPending;
 
               const duration = run.finished_at
@@ -150,7 +187,16 @@ export const RunHistoryPage: React.FC<RunHistoryPageProps> = ({ onNavigate, sche
                     </td>
                   )}
                   <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>
-                    {new Date(run.started_at).toLocaleString()}
+                    {run.burst_value ? (
+                      <span className={styles.burstValue}>↳ {run.burst_value}</span>
+                    ) : (
+                      new Date(run.started_at).toLocaleString()
+                    )}
+                    {!run.burst_value && run.range_from && run.range_to && (
+                      <div className={styles.timeRange}>
+                        {new Date(run.range_from).toLocaleString()} – {new Date(run.range_to).toLocaleString()}
+                      </div>
+                    )}
                   </td>
                   <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>
                     <span className={statusClass}>{status}</span>
@@ -162,6 +208,25 @@ export const RunHistoryPage: React.FC<RunHistoryPageProps> = ({ onNavigate, sche
                         (download available)
                       </span>
                     )}
+                    {run.email_sent && run.delivery_mode && run.delivery_mode !== 'attachment' && (
+                      <span style={{ marginLeft: '4px', fontSize: '0.9em', opacity: 0.8 }}>
+                        {run.delivery_mode === 'link' ? '(download link, over size limit)' : '(compressed)'}
+                      </span>
+                    )}
+                    {(run.deliveries || [])
+                      .filter((d) => d.type !== 'email')
+                      .map((d, i) => (
+                        <div key={i} title={d.error}>
+                          <span className={d.success ? styles.statusSuccess : styles.statusError}>
+                            {d.type}: {d.success ? 'Sent' : 'Failed'}
+                          </span>
+                          {d.attempts && d.attempts > 1 && (
+                            <span style={{ marginLeft: '4px', fontSize: '0.9em', opacity: 0.8 }}>
+                              ({d.attempts} attempts)
+                            </span>
+                          )}
+                        </div>
+                      ))}
                   </td>
                   <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>{duration}</td>
                   <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>{run.rendered_pages}</td>
@@ -179,6 +244,19 @@ export const RunHistoryPage: React.FC<RunHistoryPageProps> = ({ onNavigate, sche
                         Download PDF
                       </Button>
                     ) : null}
+                    {run.status === 'completed' && (run.deliveries || []).some((d) => d.id && !d.success) && (
+                      // @ts-ignore
+                      <Button
+                        size="sm"
+                        variant="secondary"
+                        icon="repeat"
+                        style={{ marginLeft: '4px' }}
+                        disabled={resending === run.id}
+                        onClick={() => redeliver(run.id)}
+                      >
+                        {resending === run.id ? 'Resending...' : 'Resend Failed'}
+                      </Button>
+                    )}
                   </td>
                 </tr>
               );
@@ -212,4 +290,15 @@ const getStyles = (theme: GrafanaTheme2) => ({
   statusWarning: css`
     color: ${theme.colors.warning.text};
   `,
+  timeRange: css`
+    font-size: ${theme.typography.bodySmall.fontSize};
+    color: ${theme.colors.text.secondary};
+  `,
+  burstValue: css`
+    padding-left: ${theme.spacing(2)};
+    color: ${theme.colors.text.secondary};
+  `,
+  statusSkipped: css`
+    color: ${theme.colors.text.secondary};
+  `,
 });
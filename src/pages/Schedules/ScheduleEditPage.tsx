@@ -8,6 +8,10 @@ import { AppEvents } from '@grafana/data';
 import { DashboardPicker } from '../../components/DashboardPicker';
 import { CronEditor } from '../../components/CronEditor';
 import { RecipientsEditor } from '../../components/RecipientsEditor';
+import { TargetsEditor } from '../../components/TargetsEditor';
+import { ConditionEditor } from '../../components/ConditionEditor';
+import { BurstEditor } from '../../components/BurstEditor';
+import { DashboardsEditor } from '../../components/DashboardsEditor';
 import { VariablesEditor } from '../../components/VariablesEditor';
 
 interface ScheduleEditPageProps {
@@ -23,6 +27,40 @@ const intervalOptions = [
   { label: 'Custom (Cron)', value: 'cron' },
 ];
 
+const formatOptions = [
+  { label: 'PDF', value: 'pdf' },
+  { label: 'PNG', value: 'png', description: 'One image, or a ZIP with one image per selected panel' },
+  { label: 'JPEG', value: 'jpeg', description: 'One image, or a ZIP with one image per selected panel' },
+];
+
+const dataFormatOptions = [
+  { label: 'None', value: '' },
+  { label: 'CSV', value: 'csv', description: 'One CSV file per panel query result' },
+  { label: 'XLSX', value: 'xlsx', description: 'One workbook with a sheet per panel query result' },
+];
+
+const layoutOptions = [
+  { label: 'Single page', value: 'single', description: 'Whole dashboard on one page sized to its content' },
+  { label: 'Paginated', value: 'paginated', description: 'Dashboard rows laid onto standard paper pages' },
+];
+
+const themeOptions = [
+  { label: 'Light', value: 'light' },
+  { label: 'Dark', value: 'dark', description: 'Page backgrounds are not printed unless the template enables them' },
+];
+
+// Previous periods end at the end of the last full unit, resolved in the schedule's timezone
+const timeRangePresets = [
+  { label: 'Last 24 hours', value: 'now-24h|now' },
+  { label: 'Last 7 days', value: 'now-7d|now' },
+  { label: 'Last 30 days', value: 'now-30d|now' },
+  { label: 'Previous day', value: 'now-1d/d|now-1d/d' },
+  { label: 'Previous week', value: 'now-1w/w|now-1w/w', description: 'Monday to Sunday' },
+  { label: 'Previous month', value: 'now-1M/M|now-1M/M' },
+  { label: 'Previous quarter', value: 'now-1Q/Q|now-1Q/Q' },
+  { label: 'Previous year', value: 'now-1y/y|now-1y/y' },
+];
+
 export const ScheduleEditPage: React.FC<ScheduleEditPageProps> = ({ onNavigate, isNew, scheduleId }) => {
   const styles = useStyles2(getStyles);
 
@@ -143,6 +181,30 @@ export const ScheduleEditPage: React.FC<ScheduleEditPageProps> = ({ onNavigate,
                 />
               </Field>
 
+              <Field
+                label="Combine Dashboards"
+                description="Render several dashboards into one PDF with a cover page and table of contents"
+              >
+                <Switch
+                  value={(formData.dashboards || []).length > 0}
+                  onChange={(e) =>
+                    setFormData({
+                      ...formData,
+                      dashboards: e.currentTarget.checked
+                        ? [{ uid: formData.dashboard_uid, title: formData.dashboard_title, panel_ids: formData.panel_ids }]
+                        : undefined,
+                      format: e.currentTarget.checked ? 'pdf' : formData.format,
+                    })
+                  }
+                />
+              </Field>
+              {(formData.dashboards || []).length > 0 && (
+                <DashboardsEditor
+                  value={formData.dashboards || []}
+                  onChange={(dashboards) => setFormData({ ...formData, dashboards: dashboards.length ? dashboards : undefined })}
+                />
+              )}
+
               <Field label="Enabled">
                 <Switch
                   value={formData.enabled}
@@ -152,6 +214,21 @@ export const ScheduleEditPage: React.FC<ScheduleEditPageProps> = ({ onNavigate,
             </FieldSet>
 
             <FieldSet label="Time Range">
+              <Field
+                label="Preset"
+                description="Relative ranges are resolved at the scheduled run time in the schedule's timezone"
+              >
+                <Select
+                  options={timeRangePresets}
+                  value={`${formData.range_from}|${formData.range_to}`}
+                  onChange={(v) => {
+                    const [from, to] = (v.value as string).split('|');
+                    setFormData({ ...formData, range_from: from, range_to: to });
+                  }}
+                  placeholder="Custom"
+                />
+              </Field>
+
               <Field label="From">
                 <Input
                   value={formData.range_from}
@@ -169,6 +246,59 @@ export const ScheduleEditPage: React.FC<ScheduleEditPageProps> = ({ onNavigate,
               </Field>
             </FieldSet>
 
+            <FieldSet label="Report">
+              <Field label="Format">
+                <Select
+                  options={formatOptions}
+                  value={formData.format || 'pdf'}
+                  onChange={(v) => setFormData({ ...formData, format: v.value as any })}
+                />
+              </Field>
+
+              <Field label="Layout" description="How the dashboard is laid out in the PDF">
+                <Select
+                  options={layoutOptions}
+                  value={formData.layout || 'single'}
+                  onChange={(v) => setFormData({ ...formData, layout: v.value as any })}
+                />
+              </Field>
+
+              <Field label="Theme" description="Grafana theme the dashboard is rendered in; type a theme ID for other themes">
+                <Select
+                  options={
+                    formData.theme && !themeOptions.some((o) => o.value === formData.theme)
+                      ? [...themeOptions, { label: formData.theme, value: formData.theme }]
+                      : themeOptions
+                  }
+                  value={formData.theme || 'light'}
+                  allowCustomValue
+                  onChange={(v) => setFormData({ ...formData, theme: v.value })}
+                />
+              </Field>
+
+              <Field
+                label="Data export"
+                description="Attach the query results of the selected panels (all panels when none are selected)"
+              >
+                <Select
+                  options={dataFormatOptions}
+                  value={formData.data_format || ''}
+                  onChange={(v) =>
+                    setFormData({ ...formData, data_format: v.value as any, data_only: v.value ? formData.data_only : false })
+                  }
+                />
+              </Field>
+
+              {formData.data_format && (
+                <Field label="Data only" description="Send only the data files, without the rendered report">
+                  <Switch
+                    value={formData.data_only || false}
+                    onChange={(e) => setFormData({ ...formData, data_only: e.currentTarget.checked })}
+                  />
+                </Field>
+              )}
+            </FieldSet>
+
             <FieldSet label="Schedule">
               <Field
                 label="Interval"
@@ -226,13 +356,19 @@ export const ScheduleEditPage: React.FC<ScheduleEditPageProps> = ({ onNavigate,
             </FieldSet>
 
             <FieldSet label="Email">
-              <Field label="Recipients" required>
+              <Field label="Recipients" required={!formData.burst}>
                 <RecipientsEditor
                   value={formData.recipients}
                   onChange={(recipients) => setFormData({ ...formData, recipients })}
                 />
               </Field>
 
+              <BurstEditor
+                value={formData.burst}
+                variables={formData.variables || []}
+                onChange={(burst) => setFormData({ ...formData, burst })}
+              />
+
               <Field label="Subject" description="Email subject line. You can use template variables like {{dashboard.title}}">
                 <Input
                   value={formData.email_subject}
@@ -258,14 +394,32 @@ export const ScheduleEditPage: React.FC<ScheduleEditPageProps> = ({ onNavigate,
                     <ul style={{ marginTop: '8px', marginBottom: '0', paddingLeft: '20px' }}>
                       <li><code>{'{{schedule.name}}'}</code> - Schedule name</li>
                       <li><code>{'{{dashboard.title}}'}</code> - Dashboard title</li>
-                      <li><code>{'{{timerange}}'}</code> - Time range (e.g., "now-7d to now")</li>
+                      <li><code>{'{{timerange}}'}</code> - Resolved time range (e.g., "2024-03-04 00:00 to 2024-03-10 23:59 UTC")</li>
+                      <li><code>{'{{timerange.from}}'}</code>, <code>{'{{timerange.to}}'}</code> - Range start and end (RFC 3339)</li>
                       <li><code>{'{{run.started_at}}'}</code> - Report generation timestamp</li>
+                      <li><code>{'{{burst.value}}'}</code> - Variable value of a burst report</li>
                     </ul>
                   </div>
                 </>
               </Field>
             </FieldSet>
 
+            <FieldSet label="Other Destinations">
+              <Field description="Also deliver the report to these destinations, using the email subject and body as the message">
+                <TargetsEditor
+                  value={formData.targets || []}
+                  onChange={(targets) => setFormData({ ...formData, targets })}
+                />
+              </Field>
+            </FieldSet>
+
+            <FieldSet label="Condition">
+              <ConditionEditor
+                value={formData.condition}
+                onChange={(condition) => setFormData({ ...formData, condition })}
+              />
+            </FieldSet>
+
             <div className={styles.actions}>
               {/* @ts-ignore */}
               <Button type="submit" variant="primary">
@@ -145,17 +145,25 @@ export const SchedulesPage: React.FC<SchedulesPageProps> = ({ onNavigate }) => {
   // Ensure schedules is always an array
   const safeSchedules = Array.isArray(schedules) ? schedules : [];
 
+  // Mirrors the backend's authorization: admins manage all schedules, editors their own
+  const user = config.bootData.user;
+  const canCreate = user.orgRole === 'Admin' || user.orgRole === 'Editor';
+  const canManage = (schedule: Schedule) =>
+    user.orgRole === 'Admin' || (user.orgRole === 'Editor' && !!schedule.owner_login && schedule.owner_login === user.login);
+
   return (
     <div className={styles.container}>
       <div className={styles.header}>
         <h2>Report Schedules</h2>
-          {/* @ts-ignore */}
-        <Button
-          icon="plus"
-          onClick={() => onNavigate('schedule-new')}
-        >
-          New Schedule
-        </Button>
+        {canCreate && (
+          // @ts-ignore
+          <Button
+            icon="plus"
+            onClick={() => onNavigate('schedule-new')}
+          >
+            New Schedule
+          </Button>
+        )}
       </div>
 
       {safeSchedules.length === 0 ? (
@@ -163,13 +171,15 @@ export const SchedulesPage: React.FC<SchedulesPageProps> = ({ onNavigate }) => {
           <Icon name="calendar-alt" size="xxxl" />
           <h3>No schedules yet</h3>
           <p>Create your first report schedule to get started</p>
-          {/* @ts-ignore */}
-          <Button
-            icon="plus"
-            onClick={() => onNavigate('schedule-new')}
-          >
-            Create Schedule
-          </Button>
+          {canCreate && (
+            // @ts-ignore
+            <Button
+              icon="plus"
+              onClick={() => onNavigate('schedule-new')}
+            >
+              Create Schedule
+            </Button>
+          )}
         </div>
       ) : (
         <table style={{ width: '100%', borderCollapse: 'collapse' }}>
@@ -239,31 +249,35 @@ export const SchedulesPage: React.FC<SchedulesPageProps> = ({ onNavigate }) => {
                 </td>
                 <td style={{ padding: '8px', borderBottom: '1px solid #eee' }}>
                   <div className={styles.actions}>
-                    {/* @ts-ignore */}
-                    <Button
-                      size="sm"
-                      variant="secondary"
-                      icon="play"
-                      onClick={() => handleRunNow(schedule.id)}
-                      title="Run now"
-                    />
-                    {/* @ts-ignore */}
-                    <Button
-                      size="sm"
-                      variant="secondary"
-                      icon={schedule.enabled ? 'toggle-on' : 'toggle-off'}
-                      onClick={() => handleToggle(schedule)}
-                      title={schedule.enabled ? 'Disable' : 'Enable'}
-                      className={schedule.enabled ? styles.toggleEnabled : styles.toggleDisabled}
-                    />
-                    {/* @ts-ignore */}
-                    <Button
-                      size="sm"
-                      variant="secondary"
-                      icon="edit"
-                      onClick={() => onNavigate('schedule-edit', schedule.id)}
-                      title="Edit"
-                    />
+                    {canManage(schedule) && (
+                      <>
+                        {/* @ts-ignore */}
+                        <Button
+                          size="sm"
+                          variant="secondary"
+                          icon="play"
+                          onClick={() => handleRunNow(schedule.id)}
+                          title="Run now"
+                        />
+                        {/* @ts-ignore */}
+                        <Button
+                          size="sm"
+                          variant="secondary"
+                          icon={schedule.enabled ? 'toggle-on' : 'toggle-off'}
+                          onClick={() => handleToggle(schedule)}
+                          title={schedule.enabled ? 'Disable' : 'Enable'}
+                          className={schedule.enabled ? styles.toggleEnabled : styles.toggleDisabled}
+                        />
+                        {/* @ts-ignore */}
+                        <Button
+                          size="sm"
+                          variant="secondary"
+                          icon="edit"
+                          onClick={() => onNavigate('schedule-edit', schedule.id)}
+                          title="Edit"
+                        />
+                      </>
+                    )}
                     {/* @ts-ignore */}
                     <Button
                       size="sm"
@@ -272,14 +286,18 @@ export const SchedulesPage: React.FC<SchedulesPageProps> = ({ onNavigate }) => {
                       onClick={() => onNavigate('run-history', schedule.id)}
                       title="View history"
                     />
-                    {/* @ts-ignore */}
-                    <Button
-                      size="sm"
-                      variant="destructive"
-                      icon="trash-alt"
-                      onClick={() => handleDelete(schedule.id)}
-                      title="Delete"
-                    />
+                    {canManage(schedule) && (
+                      <>
+                        {/* @ts-ignore */}
+                        <Button
+                          size="sm"
+                          variant="destructive"
+                          icon="trash-alt"
+                          onClick={() => handleDelete(schedule.id)}
+                          title="Delete"
+                        />
+                      </>
+                    )}
                   </div>
                 </td>
               </tr>
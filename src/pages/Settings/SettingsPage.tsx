@@ -1,8 +1,8 @@
 import React, { useState, useEffect } from 'react';
 import { css } from '@emotion/css';
 import { GrafanaTheme2 } from '@grafana/data';
-import { useStyles2, Button, Field, Input, Switch, FieldSet, Form, TextArea } from '@grafana/ui';
-import { Settings, SMTPConfig, RendererConfig, Limits } from '../../types/types';
+import { useStyles2, Button, Field, Input, SecretInput, Select, Switch, FieldSet, Form, TextArea } from '@grafana/ui';
+import { Settings, SMTPConfig, RendererConfig, Limits, S3Config, SECRET_UNCHANGED } from '../../types/types';
 import { getBackendSrv, getAppEvents } from '@grafana/runtime';
 import { AppEvents } from '@grafana/data';
 
@@ -41,6 +41,23 @@ const DEFAULT_LIMITS: Limits = {
   retention_days: 30,
 };
 
+const DEFAULT_S3_CONFIG: S3Config = {
+  endpoint: '',
+  region: '',
+  bucket: '',
+  prefix: '',
+  access_key_id: '',
+  secret_access_key: '',
+  use_path_style: true,
+  skip_tls_verify: false,
+};
+
+const storageOptions = [
+  { label: 'Database', value: 'database', description: 'Store reports in the plugin database' },
+  { label: 'Filesystem', value: 'filesystem', description: 'Store reports in the plugin data directory' },
+  { label: 'S3-compatible bucket', value: 's3', description: 'Store reports in AWS S3, MinIO or another S3-compatible service' },
+];
+
 export const SettingsPage: React.FC<SettingsPageProps> = ({ onNavigate }) => {
   const styles = useStyles2(getStyles);
   const [settings, setSettings] = useState<Partial<Settings>>({
@@ -140,6 +157,20 @@ export const SettingsPage: React.FC<SettingsPageProps> = ({ onNavigate }) => {
     });
   };
 
+  const updateS3 = (field: keyof S3Config, value: any) => {
+    setSettings({
+      ...settings,
+      storage: {
+        ...settings.storage,
+        s3: {
+          ...DEFAULT_S3_CONFIG,
+          ...settings.storage?.s3,
+          [field]: value
+        },
+      },
+    });
+  };
+
   const handleCheckChromium = async () => {
     const appEvents = getAppEvents();
     setIsCheckingChromium(true);
@@ -332,11 +363,12 @@ export const SettingsPage: React.FC<SettingsPageProps> = ({ onNavigate }) => {
                   onChange={(e) => updateSMTP('username', e.currentTarget.value)}
                 />
               </Field>
-              <Field label="Password" description="SMTP authentication password">
-                <Input
-                  type="password"
+              <Field label="Password" description="SMTP authentication password, stored encrypted">
+                <SecretInput
+                  isConfigured={settings.smtp_config?.password === SECRET_UNCHANGED}
                   value={settings.smtp_config?.password || ''}
                   onChange={(e) => updateSMTP('password', e.currentTarget.value)}
+                  onReset={() => updateSMTP('password', '')}
                 />
               </Field>
               <Field label="From Address" description="Email address to send reports from">
@@ -567,7 +599,10 @@ export const SettingsPage: React.FC<SettingsPageProps> = ({ onNavigate }) => {
                   }}
                 />
               </Field>
-              <Field label="Max Attachment Size (MB)">
+              <Field
+                label="Max Attachment Size (MB)"
+                description="Larger reports are sent zipped, or as a download link when still too large"
+              >
                 <Input
                   type="number"
                   value={settings.limits?.max_attachment_size_mb ?? 25}
@@ -579,7 +614,10 @@ export const SettingsPage: React.FC<SettingsPageProps> = ({ onNavigate }) => {
                   }}
                 />
               </Field>
-              <Field label="Max Concurrent Renders">
+              <Field
+                label="Max Concurrent Renders"
+                description="Reports of this organization rendered at the same time; further runs wait in queue"
+              >
                 <Input
                   type="number"
                   value={settings.limits?.max_concurrent_renders ?? 5}
@@ -591,7 +629,10 @@ export const SettingsPage: React.FC<SettingsPageProps> = ({ onNavigate }) => {
                   }}
                 />
               </Field>
-              <Field label="Retention Days">
+              <Field
+                label="Retention Days"
+                description="Report artifacts of older runs are deleted daily; run history is kept. Set to 0 to keep artifacts forever"
+              >
                 <Input
                   type="number"
                   value={settings.limits?.retention_days ?? 30}
@@ -603,6 +644,21 @@ export const SettingsPage: React.FC<SettingsPageProps> = ({ onNavigate }) => {
                   }}
                 />
               </Field>
+              <Field
+                label="Keep Last Runs"
+                description="Number of most recent runs per schedule whose artifacts are kept regardless of age"
+              >
+                <Input
+                  type="number"
+                  value={settings.limits?.keep_last_runs ?? 0}
+                  onChange={(e) => {
+                    const value = parseInt(e.currentTarget.value, 10);
+                    if (!isNaN(value)) {
+                      updateLimits('keep_last_runs', value);
+                    }
+                  }}
+                />
+              </Field>
               <Field
                 label="Allowed Email Domains"
                 description="Whitelist of allowed email domains for report recipients. Enter one domain per line. Leave empty to allow all domains. Supports wildcards (e.g., *.example.com)"
@@ -622,6 +678,77 @@ export const SettingsPage: React.FC<SettingsPageProps> = ({ onNavigate }) => {
               </Field>
             </FieldSet>
 
+            <FieldSet label="Artifact Storage">
+              <Field
+                label="Backend"
+                description="Where new reports are stored. Existing reports stay in the backend they were saved to"
+              >
+                <Select
+                  options={storageOptions}
+                  value={settings.storage?.backend || 'database'}
+                  onChange={(v) => setSettings({ ...settings, storage: { ...settings.storage, backend: v.value as any } })}
+                />
+              </Field>
+              {settings.storage?.backend === 's3' && (
+                <>
+                  <Field label="Endpoint" description="e.g. https://s3.eu-west-1.amazonaws.com or http://minio:9000">
+                    <Input
+                      value={settings.storage?.s3?.endpoint || ''}
+                      onChange={(e) => updateS3('endpoint', e.currentTarget.value)}
+                      required
+                    />
+                  </Field>
+                  <Field label="Region" description="Defaults to us-east-1">
+                    <Input
+                      value={settings.storage?.s3?.region || ''}
+                      onChange={(e) => updateS3('region', e.currentTarget.value)}
+                    />
+                  </Field>
+                  <Field label="Bucket">
+                    <Input
+                      value={settings.storage?.s3?.bucket || ''}
+                      onChange={(e) => updateS3('bucket', e.currentTarget.value)}
+                      required
+                    />
+                  </Field>
+                  <Field label="Key Prefix" description="Optional folder inside the bucket">
+                    <Input
+                      value={settings.storage?.s3?.prefix || ''}
+                      onChange={(e) => updateS3('prefix', e.currentTarget.value)}
+                    />
+                  </Field>
+                  <Field label="Access Key ID">
+                    <Input
+                      value={settings.storage?.s3?.access_key_id || ''}
+                      onChange={(e) => updateS3('access_key_id', e.currentTarget.value)}
+                      required
+                    />
+                  </Field>
+                  <Field label="Secret Access Key" description="Stored encrypted">
+                    <SecretInput
+                      isConfigured={settings.storage?.s3?.secret_access_key === SECRET_UNCHANGED}
+                      value={settings.storage?.s3?.secret_access_key || ''}
+                      onChange={(e) => updateS3('secret_access_key', e.currentTarget.value)}
+                      onReset={() => updateS3('secret_access_key', '')}
+                      required
+                    />
+                  </Field>
+                  <Field label="Path-style Addressing" description="Required by MinIO and most S3-compatible services">
+                    <Switch
+                      value={settings.storage?.s3?.use_path_style ?? true}
+                      onChange={(e) => updateS3('use_path_style', e.currentTarget.checked)}
+                    />
+                  </Field>
+                  <Field label="Skip TLS Verification">
+                    <Switch
+                      value={settings.storage?.s3?.skip_tls_verify ?? false}
+                      onChange={(e) => updateS3('skip_tls_verify', e.currentTarget.checked)}
+                    />
+                  </Field>
+                </>
+              )}
+            </FieldSet>
+
             {/* @ts-ignore */}
             <Button type="submit" variant="primary">
               Save Settings
@@ -16,6 +16,7 @@ export interface Schedule {
   name: string;
   dashboard_uid: string;
   dashboard_title?: string;
+  dashboards?: DashboardRef[]; // Combined into one PDF instead of dashboard_uid
   panel_ids?: number[];
   range_from: string;
   range_to: string;
@@ -24,17 +25,107 @@ export interface Schedule {
   timezone: string;
   variables?: Variable[];
   recipients: Recipients;
+  targets?: DeliveryTarget[];
+  condition?: Condition;
+  burst?: Burst;
   email_subject: string;
   email_body: string;
   template_id?: number;
+  format?: 'pdf' | 'png' | 'jpeg';
+  layout?: 'single' | 'paginated';
+  theme?: string; // 'light' (default), 'dark' or a Grafana theme ID
+  data_format?: '' | 'csv' | 'xlsx';
+  data_only?: boolean;
   enabled: boolean;
   last_run_at?: string;
   next_run_at?: string;
   owner_user_id: number;
+  owner_login?: string; // Login of the user who created the schedule
   created_at: string;
   updated_at: string;
 }
 
+// DashboardRef is one dashboard of a multi-dashboard report
+export interface DashboardRef {
+  uid: string;
+  title?: string;
+  panel_ids?: number[];
+  range_from?: string; // Empty uses the schedule's time range
+  range_to?: string;
+  variables?: Variable[];
+}
+
+// Condition limits a schedule to runs where the query result matches, e.g. "only send when errors > 0"
+export interface Condition {
+  datasource_uid: string;
+  query: Record<string, any>;
+  reducer: 'last' | 'min' | 'max' | 'mean' | 'sum' | 'count';
+  operator: '>' | '>=' | '<' | '<=' | '==' | '!=';
+  threshold: number;
+}
+
+// Burst renders one report per value of a dashboard variable, emailed to that value's recipients
+export interface Burst {
+  variable: string;
+  values: BurstValue[];
+}
+
+export interface BurstValue {
+  value: string;
+  recipients: Recipients;
+}
+
+export interface DeliveryTarget {
+  type: 'slack' | 'teams' | 'webhook' | 'sftp' | 'share';
+  slack?: SlackTarget;
+  teams?: TeamsTarget;
+  webhook?: WebhookTarget;
+  sftp?: SFTPTarget;
+  share?: ShareTarget;
+  retries?: number;
+}
+
+export interface SlackTarget {
+  token: string;
+  channel: string;
+}
+
+export interface TeamsTarget {
+  webhook_url: string;
+}
+
+export interface WebhookTarget {
+  url: string;
+  secret?: string;
+  include_content?: boolean;
+}
+
+export interface SFTPTarget {
+  host: string;
+  port?: number;
+  username: string;
+  password?: string;
+  private_key?: string;
+  passphrase?: string;
+  host_key?: string;
+  path?: string;
+}
+
+export interface ShareTarget {
+  path: string;
+}
+
+export interface DeliveryResult {
+  id?: number;
+  index: number;
+  type: string;
+  target: string;
+  success: boolean;
+  error?: string;
+  attempts?: number;
+  delivered_at: string;
+}
+
 export interface Recipients {
   to: string[];
   cc?: string[];
@@ -47,23 +138,55 @@ export interface Run {
   org_id: number;
   started_at: string;
   finished_at?: string;
-  status: 'pending' | 'running' | 'completed' | 'failed';
+  status: 'pending' | 'running' | 'completed' | 'failed' | 'skipped';
   email_sent: boolean;
   email_error?: string;
   error_text?: string;
   artifact_path?: string;
   rendered_pages: number;
+  content_type?: string;
+  delivery_mode?: 'attachment' | 'compressed' | 'link';
+  storage_backend?: 'database' | 'filesystem' | 's3';
+  deliveries?: DeliveryResult[];
+  parent_run_id?: number; // Set on the per-value reports of a bursting schedule's run
+  burst_value?: string;
+  range_from?: string; // Absolute time range the report covered, resolved at the scheduled time
+  range_to?: string;
   bytes: number;
   checksum?: string;
   created_at: string;
 }
 
+export interface AuditChange {
+  old: unknown;
+  new: unknown;
+}
+
+export interface AuditEvent {
+  id: number;
+  org_id: number;
+  user_login: string;
+  action: 'schedule.create' | 'schedule.update' | 'schedule.delete' | 'schedule.run' | 'settings.update' | 'run.download';
+  resource_type: 'schedule' | 'settings' | 'run';
+  resource_id?: number;
+  diff?: Record<string, AuditChange>; // Changed JSON paths; secrets show as "[redacted]"
+  created_at: string;
+}
+
+export interface AuditEventList {
+  events: AuditEvent[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
 export interface Template {
   id: number;
   org_id: number;
   name: string;
   kind: 'pdf' | 'html' | 'email';
   config: TemplateConfig;
+  owner_login?: string; // Login of the user who created the template
   created_at: string;
   updated_at: string;
 }
@@ -73,9 +196,15 @@ export interface TemplateConfig {
   footer?: string;
   logo_url?: string;
   watermark?: string;
-  page_size?: 'A4' | 'Letter';
+  page_size?: 'A3' | 'A4' | 'A5' | 'Letter' | 'Legal';
   orientation?: 'portrait' | 'landscape';
+  // Overrides whether page backgrounds are printed (default: all themes but dark)
+  print_background?: boolean;
+  // Front matter added to PDF reports; combined reports always have both
+  cover_page?: boolean;
+  table_of_contents?: boolean;
   margins?: {
+    // Millimeters
     top: number;
     bottom: number;
     left: number;
@@ -89,15 +218,35 @@ export interface Settings {
   smtp_config?: SMTPConfig;
   renderer_config: RendererConfig;
   limits: Limits;
+  storage?: StorageConfig;
   created_at: string;
   updated_at: string;
 }
 
+export interface StorageConfig {
+  backend?: 'database' | 'filesystem' | 's3';
+  s3?: S3Config;
+}
+
+export interface S3Config {
+  endpoint: string;
+  region?: string;
+  bucket: string;
+  prefix?: string;
+  access_key_id: string;
+  secret_access_key: string; // SECRET_UNCHANGED when stored
+  use_path_style: boolean;
+  skip_tls_verify: boolean;
+}
+
+// Returned by the API in place of stored secrets; sending it back keeps the stored secret
+export const SECRET_UNCHANGED = '__unchanged__';
+
 export interface SMTPConfig {
   host: string;
   port: number;
   username: string;
-  password: string;
+  password: string; // SECRET_UNCHANGED when stored
   from: string;
   use_tls: boolean;
   skip_tls_verify: boolean;
@@ -124,7 +273,8 @@ export interface Limits {
   max_recipients: number;
   max_attachment_size_mb: number;
   max_concurrent_renders: number;
-  retention_days: number;
+  retention_days: number; // 0 keeps artifacts forever
+  keep_last_runs?: number; // Runs per schedule that keep their artifacts regardless of age
   allowed_domains?: string[]; // If empty, all domains are allowed
 }
 
@@ -132,6 +282,7 @@ export interface ScheduleFormData {
   name: string;
   dashboard_uid: string;
   dashboard_title?: string;
+  dashboards?: DashboardRef[]; // Combined into one PDF instead of dashboard_uid
   panel_ids?: number[];
   range_from: string;
   range_to: string;
@@ -140,8 +291,16 @@ export interface ScheduleFormData {
   timezone: string;
   variables?: Variable[];
   recipients: Recipients;
+  targets?: DeliveryTarget[];
+  condition?: Condition;
+  burst?: Burst;
   email_subject: string;
   email_body: string;
   template_id?: number;
+  format?: 'pdf' | 'png' | 'jpeg';
+  layout?: 'single' | 'paginated';
+  theme?: string; // 'light' (default), 'dark' or a Grafana theme ID
+  data_format?: '' | 'csv' | 'xlsx';
+  data_only?: boolean;
   enabled: boolean;
 }